@@ -0,0 +1,476 @@
+package ads
+
+import (
+	"bytes"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
+)
+
+// BatchChange is a change of a single key that is applied by Map.ApplyBatch.
+type BatchChange[K, V any] struct {
+	// Key is the key that is changed.
+	Key K
+
+	// Value is the new value of the key (ignored for deletions).
+	Value V
+
+	// Delete defines whether the key is deleted instead of set.
+	Delete bool
+}
+
+// batchWorkerPool returns the worker pool that is used to apply a batch with the given options and a function that
+// releases it once the batch is done (pools that were not passed with WithBatchWorkerPool only live as long as the
+// batch).
+func batchWorkerPool(options *Options) (workerPool *workerpool.WorkerPool, release func()) {
+	if options.batchWorkerPool != nil {
+		return options.batchWorkerPool, func() {}
+	}
+
+	workerPool = workerpool.New("ads.ApplyBatch", workerpool.WithWorkerCount(options.batchWorkerCount)).Start()
+
+	return workerPool, func() { workerPool.Shutdown() }
+}
+
+// runParallel runs the given tasks on the given worker pool and waits until all of them are done.
+//
+// Tasks that were not started by a worker once all tasks are submitted are run by the caller itself, so it only waits
+// for tasks that are already running. This makes it safe to call from a task of the same pool, which would otherwise
+// deadlock once all workers are waiting for tasks that are queued behind them.
+func runParallel(workerPool *workerpool.WorkerPool, tasks []func()) {
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+
+	isStarted := make([]atomic.Bool, len(tasks))
+	runTask := func(index int) {
+		if isStarted[index].CompareAndSwap(false, true) {
+			defer wg.Done()
+
+			tasks[index]()
+		}
+	}
+
+	for index := range tasks {
+		index := index
+		workerPool.Submit(func() { runTask(index) })
+	}
+
+	for index := range tasks {
+		runTask(index)
+	}
+
+	wg.Wait()
+}
+
+// batchEntry is a prepared BatchChange.
+type batchEntry[K any] struct {
+	key        K
+	keyBytes   []byte
+	valueBytes []byte
+	path       []byte
+	leafData   []byte
+	isDeletion bool
+
+	// previousLeafData is the leaf data of the key before the batch was applied (nil if the key did not exist).
+	previousLeafData []byte
+}
+
+// batchNode is a node of the tree that is (re)built by a treeBatch.
+type batchNode struct {
+	// hash is the hash of the node (nil if it was not computed yet).
+	hash []byte
+
+	// prefix is the type of the node.
+	prefix byte
+
+	// isStored is true if the node exists in the store.
+	isStored bool
+
+	// isLoaded is true if the fields of the node are known (stored nodes are loaded lazily).
+	isLoaded bool
+
+	// path is the path of a leaf or an extension (and an arbitrary path below an inner node, if known).
+	path []byte
+
+	// leafData is the data that is stored in a leaf.
+	leafData []byte
+
+	// childHashes contains the hashes of the children of an inner node.
+	childHashes [2][]byte
+
+	// extensionStart is the first bit of the path that is covered by an extension.
+	extensionStart int
+
+	// extensionEnd is the first bit of the path that is not covered by an extension anymore.
+	extensionEnd int
+
+	// extensionChild is the hash of the child of an extension.
+	extensionChild []byte
+}
+
+// createdNode is a node that was created by a treeBatch.
+type createdNode struct {
+	hash     []byte
+	preimage []byte
+}
+
+// treeBatch applies a batch of changes to a subtree of a sparse merkle tree that is stored in the format of
+// pokt-network/smt. The resulting tree has the same root as if the changes were applied one by one.
+type treeBatch[K any] struct {
+	*treeDiff
+
+	options       *Options
+	reader        TreeReader
+	createdNodes  []*createdNode
+	orphanedNodes [][]byte
+}
+
+// newTreeBatch creates a new treeBatch.
+func newTreeBatch[K any](opts *Options, reader TreeReader) *treeBatch[K] {
+	return &treeBatch[K]{
+		treeDiff:      newTreeDiff(opts, reader, reader, nil),
+		options:       opts,
+		reader:        reader,
+		createdNodes:  make([]*createdNode, 0),
+		orphanedNodes: make([][]byte, 0),
+	}
+}
+
+// Run applies the given entries (sorted by path) to the tree with the given root and returns the new root, using the
+// given worker pool to build the subtrees in parallel.
+func (t *treeBatch[K]) Run(root []byte, entries []*batchEntry[K], workerPool *workerpool.WorkerPool) ([]byte, error) {
+	var subtreeTasks []func()
+	var subtreeBatches []*treeBatch[K]
+
+	resolveRoot := t.applyParallel(t.storedNode(root), 0, entries, bits.Len(uint(workerPool.WorkerCount()))+2, func(node *batchNode, depth int, entries []*batchEntry[K]) func() (*batchNode, error) {
+		subtreeBatch := newTreeBatch[K](t.options, t.reader)
+		subtreeBatches = append(subtreeBatches, subtreeBatch)
+
+		var result *batchNode
+		var err error
+
+		subtreeTasks = append(subtreeTasks, func() {
+			result, err = subtreeBatch.apply(node, depth, entries)
+		})
+
+		return func() (*batchNode, error) {
+			return result, err
+		}
+	})
+
+	runParallel(workerPool, subtreeTasks)
+
+	for _, subtreeBatch := range subtreeBatches {
+		t.createdNodes = append(t.createdNodes, subtreeBatch.createdNodes...)
+		t.orphanedNodes = append(t.orphanedNodes, subtreeBatch.orphanedNodes...)
+	}
+
+	newRoot, err := resolveRoot()
+	if err != nil {
+		return nil, err
+	} else if newRoot == nil {
+		return make([]byte, t.hashSize), nil
+	}
+
+	t.materialize(newRoot)
+
+	return newRoot.hash, nil
+}
+
+// applyParallel splits the given entries along the tree until the given depth is reached and applies the parts with
+// the given subtree function (the returned function resolves the result once all subtrees are done).
+func (t *treeBatch[K]) applyParallel(node *batchNode, depth int, entries []*batchEntry[K], parallelDepth int, subtreeFunc func(node *batchNode, depth int, entries []*batchEntry[K]) func() (*batchNode, error)) func() (*batchNode, error) {
+	if node != nil && len(entries) > 0 {
+		if err := t.load(node); err != nil {
+			return func() (*batchNode, error) { return nil, err }
+		}
+	}
+
+	if depth >= parallelDepth || len(entries) == 0 || node == nil || node.prefix == nodePrefixLeaf {
+		return subtreeFunc(node, depth, entries)
+	}
+
+	children, childEntries := t.split(node, depth, entries)
+	resolveLeft := t.applyParallel(children[0], depth+1, childEntries[0], parallelDepth, subtreeFunc)
+	resolveRight := t.applyParallel(children[1], depth+1, childEntries[1], parallelDepth, subtreeFunc)
+
+	return func() (*batchNode, error) {
+		left, err := resolveLeft()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := resolveRight()
+		if err != nil {
+			return nil, err
+		}
+
+		return t.combine(left, right, depth, entries[0].path)
+	}
+}
+
+// apply applies the given entries to the given subtree at the given depth.
+func (t *treeBatch[K]) apply(node *batchNode, depth int, entries []*batchEntry[K]) (*batchNode, error) {
+	if len(entries) == 0 {
+		return node, nil
+	} else if node == nil {
+		return t.build(t.newLeaves(nil, entries), depth)
+	}
+
+	if err := t.load(node); err != nil {
+		return nil, err
+	}
+
+	if node.prefix == nodePrefixLeaf {
+		return t.build(t.newLeaves(node, entries), depth)
+	}
+
+	children, childEntries := t.split(node, depth, entries)
+
+	left, err := t.apply(children[0], depth+1, childEntries[0])
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := t.apply(children[1], depth+1, childEntries[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return t.combine(left, right, depth, entries[0].path)
+}
+
+// split orphans the given inner node or extension and returns its children and the entries that belong to them.
+func (t *treeBatch[K]) split(node *batchNode, depth int, entries []*batchEntry[K]) (children [2]*batchNode, childEntries [2][]*batchEntry[K]) {
+	t.orphan(node)
+
+	switch node.prefix {
+	case nodePrefixInner:
+		children = [2]*batchNode{t.storedNode(node.childHashes[0]), t.storedNode(node.childHashes[1])}
+	case nodePrefixExtension:
+		if depth+1 == node.extensionEnd {
+			children[pathBit(node.path, depth)] = t.storedNode(node.extensionChild)
+		} else {
+			children[pathBit(node.path, depth)] = &batchNode{
+				prefix:         nodePrefixExtension,
+				isLoaded:       true,
+				path:           node.path,
+				extensionStart: depth + 1,
+				extensionEnd:   node.extensionEnd,
+				extensionChild: node.extensionChild,
+			}
+		}
+	}
+
+	// the entries are sorted by path and share the bits before the depth
+	splitIndex := sort.Search(len(entries), func(i int) bool {
+		return pathBit(entries[i].path, depth) == 1
+	})
+
+	return children, [2][]*batchEntry[K]{entries[:splitIndex], entries[splitIndex:]}
+}
+
+// newLeaves returns the sorted leaves that result from applying the given entries to the given (optional) leaf.
+func (t *treeBatch[K]) newLeaves(existingLeaf *batchNode, entries []*batchEntry[K]) []*batchNode {
+	leaves := make([]*batchNode, 0, len(entries)+1)
+	for _, entry := range entries {
+		if existingLeaf != nil && bytes.Equal(entry.path, existingLeaf.path) {
+			entry.previousLeafData = existingLeaf.leafData
+
+			t.orphan(existingLeaf)
+			existingLeaf = nil
+		} else if existingLeaf != nil && bytes.Compare(existingLeaf.path, entry.path) < 0 {
+			leaves = append(leaves, existingLeaf)
+			existingLeaf = nil
+		}
+
+		if !entry.isDeletion {
+			leaves = append(leaves, &batchNode{
+				prefix:   nodePrefixLeaf,
+				isLoaded: true,
+				path:     entry.path,
+				leafData: entry.leafData,
+			})
+		}
+	}
+
+	if existingLeaf != nil {
+		leaves = append(leaves, existingLeaf)
+	}
+
+	return leaves
+}
+
+// build returns the subtree at the given depth that contains the given sorted leaves.
+func (t *treeBatch[K]) build(leaves []*batchNode, depth int) (*batchNode, error) {
+	switch len(leaves) {
+	case 0:
+		return nil, nil
+	case 1:
+		return leaves[0], nil
+	}
+
+	splitIndex := sort.Search(len(leaves), func(i int) bool {
+		return pathBit(leaves[i].path, depth) == 1
+	})
+
+	left, err := t.build(leaves[:splitIndex], depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := t.build(leaves[splitIndex:], depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.combine(left, right, depth, leaves[0].path)
+}
+
+// combine returns the subtree at the given depth that has the given children (the prefix path shares the bits before
+// the depth with all paths of the subtree).
+func (t *treeBatch[K]) combine(left *batchNode, right *batchNode, depth int, prefixPath []byte) (*batchNode, error) {
+	if left == nil && right == nil {
+		return nil, nil
+	}
+
+	if left != nil && right != nil {
+		t.materialize(left)
+		t.materialize(right)
+
+		return &batchNode{
+			prefix:      nodePrefixInner,
+			isLoaded:    true,
+			path:        lo.Cond(left.path != nil, left.path, right.path),
+			childHashes: [2][]byte{left.hash, right.hash},
+		}, nil
+	}
+
+	child, side := left, 0
+	if child == nil {
+		child, side = right, 1
+	}
+
+	if err := t.load(child); err != nil {
+		return nil, err
+	}
+
+	switch child.prefix {
+	case nodePrefixLeaf:
+		// single leaves are moved up to the highest possible position
+		return child, nil
+	case nodePrefixExtension:
+		t.orphan(child)
+
+		return &batchNode{
+			prefix:         nodePrefixExtension,
+			isLoaded:       true,
+			path:           child.path,
+			extensionStart: depth,
+			extensionEnd:   child.extensionEnd,
+			extensionChild: child.extensionChild,
+		}, nil
+	default:
+		t.materialize(child)
+
+		path := child.path
+		if path == nil {
+			path = withPathBit(prefixPath, depth, side)
+		}
+
+		return &batchNode{
+			prefix:         nodePrefixExtension,
+			isLoaded:       true,
+			path:           path,
+			extensionStart: depth,
+			extensionEnd:   depth + 1,
+			extensionChild: child.hash,
+		}, nil
+	}
+}
+
+// storedNode returns a lazily loaded node for the given hash (nil for empty subtrees).
+func (t *treeBatch[K]) storedNode(hash []byte) *batchNode {
+	if bytes.Equal(hash, make([]byte, t.hashSize)) {
+		return nil
+	}
+
+	return &batchNode{
+		hash:     hash,
+		isStored: true,
+	}
+}
+
+// load loads the fields of a stored node.
+func (t *treeBatch[K]) load(node *batchNode) error {
+	if node.isLoaded {
+		return nil
+	}
+
+	storedNode, err := t.node(t.reader, node.hash)
+	if err != nil {
+		return ierrors.Wrapf(err, "failed to load node %x", node.hash)
+	}
+
+	node.prefix = storedNode.prefix
+	node.path = storedNode.path
+	node.leafData = storedNode.leafData
+	node.childHashes = storedNode.children
+	node.extensionStart = storedNode.extensionStart
+	node.extensionEnd = storedNode.extensionEnd
+	node.extensionChild = storedNode.extensionChild
+	node.isLoaded = true
+
+	return nil
+}
+
+// materialize computes the hash of the given node and marks it to be stored (if it is not stored already).
+func (t *treeBatch[K]) materialize(node *batchNode) {
+	if node.isStored {
+		return
+	}
+
+	var preimage []byte
+	switch node.prefix {
+	case nodePrefixLeaf:
+		preimage = byteutils.ConcatBytes([]byte{nodePrefixLeaf}, node.path, node.leafData)
+		node.hash = t.digest(t.hasher, preimage)
+	case nodePrefixInner:
+		preimage = byteutils.ConcatBytes([]byte{nodePrefixInner}, node.childHashes[0], node.childHashes[1])
+		node.hash = t.digest(t.hasher, preimage)
+	case nodePrefixExtension:
+		preimage = byteutils.ConcatBytes([]byte{nodePrefixExtension, byte(node.extensionStart), byte(node.extensionEnd)}, node.path, node.extensionChild)
+		node.hash = t.extensionHash(node.path, node.extensionStart, node.extensionEnd, node.extensionChild)
+	}
+
+	t.createdNodes = append(t.createdNodes, &createdNode{
+		hash:     node.hash,
+		preimage: preimage,
+	})
+	node.isStored = true
+}
+
+// orphan marks the given node as orphaned (if it is stored).
+func (t *treeBatch[K]) orphan(node *batchNode) {
+	if node.isStored {
+		t.orphanedNodes = append(t.orphanedNodes, node.hash)
+	}
+}
+
+// withPathBit returns a copy of the given path with the bit at the given position set to the given value.
+func withPathBit(path []byte, position int, bit int) []byte {
+	modifiedPath := byteutils.ConcatBytes(path)
+	if bit == 1 {
+		modifiedPath[position/8] |= 1 << (7 - uint(position)%8)
+	} else {
+		modifiedPath[position/8] &^= 1 << (7 - uint(position)%8)
+	}
+
+	return modifiedPath
+}
@@ -0,0 +1,195 @@
+package ads
+
+import (
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
+)
+
+func TestMapApplyBatch(t *testing.T) {
+	workerPool := workerpool.New(t.Name(), workerpool.WithWorkerCount(2)).Start()
+	defer workerPool.Shutdown()
+
+	// maps without a worker pool use a pool that only lives as long as the batch
+	batchPool, releaseBatchPool := batchWorkerPool(newOptions(WithBatchWorkerCount(4)))
+	require.Equal(t, 4, batchPool.WorkerCount())
+	releaseBatchPool()
+	require.False(t, batchPool.IsRunning())
+
+	// passed pools are shared and not shut down by the maps
+	sharedPool, releaseSharedPool := batchWorkerPool(newOptions(WithBatchWorkerCount(4), WithBatchWorkerPool(workerPool)))
+	require.Same(t, workerPool, sharedPool)
+	releaseSharedPool()
+	require.True(t, workerPool.IsRunning())
+
+	// the worker count is at least 1 and passed pools need workers
+	require.Equal(t, 1, newOptions(WithBatchWorkerCount(0)).batchWorkerCount)
+	_, err := OpenMap[[32]byte](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+		WithBatchWorkerPool(workerpool.New(t.Name(), workerpool.WithWorkerCount(0))),
+	)
+	require.ErrorIs(t, err, ErrInvalidBatchWorkerPool)
+
+	for _, opts := range [][]options.Option[Options]{
+		{WithBatchWorkerCount(0)},
+		{WithBatchWorkerCount(4)},
+		{WithBatchWorkerCount(1), WithValueHasher(sha256.New)},
+		{WithBatchWorkerPool(workerPool)},
+	} {
+		newMap := func() *authenticatedMap[[32]byte, testKey, testValue] {
+			return newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+				typeutils.ByteArray32ToBytes,
+				typeutils.ByteArray32FromBytes,
+				testKey.Bytes,
+				testKeyFromBytes,
+				testValue.Bytes,
+				testValueFromBytes,
+				opts...,
+			)
+		}
+
+		batchMap, sequentialMap := newMap(), newMap()
+
+		// an empty batch on an empty map does not create a version
+		require.NoError(t, batchMap.ApplyBatch(nil))
+		require.NoError(t, sequentialMap.Commit())
+		require.Equal(t, sequentialMap.Root(), batchMap.Root())
+		require.Equal(t, uint64(0), batchMap.Version())
+
+		random := rand.New(rand.NewSource(1))
+		for round := 0; round < 10; round++ {
+			// batches are rejected as long as there are uncommitted changes
+			require.NoError(t, batchMap.Set(testKey{byte(250 + round%6)}, testValueFromString(string(rune('A'+round)))))
+			require.NoError(t, sequentialMap.Set(testKey{byte(250 + round%6)}, testValueFromString(string(rune('A'+round)))))
+			require.ErrorIs(t, batchMap.ApplyBatch([]BatchChange[testKey, testValue]{{Key: testKey{1}, Value: testValueFromString("rejected")}}), ErrUncommittedChanges)
+			require.NoError(t, batchMap.Commit())
+			require.NoError(t, sequentialMap.Commit())
+
+			changes := make([]BatchChange[testKey, testValue], 0)
+			for i := 0; i < 200; i++ {
+				change := BatchChange[testKey, testValue]{
+					Key:    testKey{byte(random.Intn(250))},
+					Value:  testValueFromString(string(rune('a' + random.Intn(26)))),
+					Delete: random.Intn(3) == 0,
+				}
+				changes = append(changes, change)
+
+				if change.Delete {
+					lo.PanicOnErr(sequentialMap.Delete(change.Key))
+				} else {
+					require.NoError(t, sequentialMap.Set(change.Key, change.Value))
+				}
+			}
+
+			require.NoError(t, batchMap.ApplyBatch(changes))
+			require.NoError(t, sequentialMap.Commit())
+
+			require.Equal(t, sequentialMap.Root(), batchMap.Root())
+			require.Equal(t, sequentialMap.Size(), batchMap.Size())
+			require.Equal(t, sequentialMap.Version(), batchMap.Version())
+
+			expectedEntries := make(map[testKey]testValue)
+			require.NoError(t, sequentialMap.Stream(func(key testKey, value testValue) error {
+				expectedEntries[key] = value
+
+				return nil
+			}))
+
+			actualEntries := make(map[testKey]testValue)
+			require.NoError(t, batchMap.Stream(func(key testKey, value testValue) error {
+				actualEntries[key] = value
+
+				return nil
+			}))
+			require.Equal(t, expectedEntries, actualEntries)
+		}
+
+		// the map can still be modified, read and rolled back after a batch
+		rootBeforeBatch := batchMap.Root()
+		require.NoError(t, batchMap.ApplyBatch([]BatchChange[testKey, testValue]{
+			{Key: testKey{1}, Value: testValueFromString("first")},
+			{Key: testKey{1}, Value: testValueFromString("last")},
+			{Key: testKey{2}, Delete: true},
+		}))
+
+		value, exists, err := batchMap.Get(testKey{1})
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, testValueFromString("last"), value)
+		require.False(t, lo.PanicOnErr(batchMap.Has(testKey{2})))
+
+		require.NoError(t, batchMap.Set(testKey{3}, testValueFromString("set")))
+		require.NoError(t, batchMap.Commit())
+
+		previousVersion, err := batchMap.ReadAt(batchMap.Version() - 2)
+		require.NoError(t, err)
+		require.Equal(t, rootBeforeBatch, previousVersion.Root())
+
+		require.NoError(t, batchMap.Rollback(batchMap.Version()-2))
+		require.Equal(t, rootBeforeBatch, batchMap.Root())
+		require.Equal(t, sequentialMap.Size(), batchMap.Size())
+
+		require.NoError(t, batchMap.Prune(batchMap.Version()))
+		require.Equal(t, rootBeforeBatch, batchMap.Root())
+	}
+}
+
+func TestMapApplyBatchFromPoolTask(t *testing.T) {
+	workerPool := workerpool.New(t.Name(), workerpool.WithWorkerCount(2)).Start()
+	defer workerPool.Shutdown()
+
+	newMap := func(opts ...options.Option[Options]) *authenticatedMap[[32]byte, testKey, testValue] {
+		return newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			opts...,
+		)
+	}
+
+	changes := make([]BatchChange[testKey, testValue], 0)
+	for i := 0; i < 200; i++ {
+		changes = append(changes, BatchChange[testKey, testValue]{Key: testKey{byte(i)}, Value: testValueFromString(string(rune('a' + i%26)))})
+	}
+
+	expectedMap := newMap()
+	require.NoError(t, expectedMap.ApplyBatch(changes))
+
+	// batches that are applied from tasks that occupy all workers of the shared pool do not deadlock
+	batchMaps := []*authenticatedMap[[32]byte, testKey, testValue]{newMap(WithBatchWorkerPool(workerPool)), newMap(WithBatchWorkerPool(workerPool))}
+	errs := make(chan error, len(batchMaps))
+	for _, batchMap := range batchMaps {
+		batchMap := batchMap
+		workerPool.Submit(func() { errs <- batchMap.ApplyBatch(changes) })
+	}
+
+	for range batchMaps {
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "ApplyBatch deadlocked in a task of its worker pool")
+		}
+	}
+
+	for _, batchMap := range batchMaps {
+		require.Equal(t, expectedMap.Root(), batchMap.Root())
+	}
+}
@@ -0,0 +1,382 @@
+package ads
+
+import (
+	"bytes"
+	"hash"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
+)
+
+// DiffType is the type of change of a key between two roots.
+type DiffType uint8
+
+const (
+	// DiffTypeAdded marks a key that only exists in the target root.
+	DiffTypeAdded DiffType = iota
+
+	// DiffTypeModified marks a key that exists in both roots but with different values.
+	DiffTypeModified
+
+	// DiffTypeDeleted marks a key that only exists in the source root.
+	DiffTypeDeleted
+)
+
+// String returns a human-readable representation of the DiffType.
+func (d DiffType) String() string {
+	switch d {
+	case DiffTypeAdded:
+		return "Added"
+	case DiffTypeModified:
+		return "Modified"
+	case DiffTypeDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+// ErrInvalidTreeData is returned when a TreeReader returns data that does not match the requested hashes.
+var ErrInvalidTreeData = ierrors.New("invalid tree data")
+
+// TreeReader provides read access to the nodes and leaves of a sparse merkle tree.
+//
+// It can be implemented by a remote peer: all returned data is verified against the requested hashes, so that only
+// the parts of the trees that differ need to be transferred.
+type TreeReader interface {
+	// Node returns the preimage of the tree node with the given hash.
+	Node(hash []byte) (preimage []byte, err error)
+
+	// Leaf returns the key and the value bytes of the leaf with the given path and leaf data.
+	Leaf(path []byte, leafData []byte) (keyBytes []byte, valueBytes []byte, err error)
+}
+
+const (
+	// nodePrefixLeaf is the prefix of serialized leaf nodes in pokt-network/smt.
+	nodePrefixLeaf byte = iota
+
+	// nodePrefixInner is the prefix of serialized inner nodes in pokt-network/smt.
+	nodePrefixInner
+
+	// nodePrefixExtension is the prefix of serialized extension nodes in pokt-network/smt.
+	nodePrefixExtension
+)
+
+// diffNode is a (possibly virtual) node of a tree that is visited during a diff.
+type diffNode struct {
+	// hash is the hash of the underlying tree node.
+	hash []byte
+
+	// prefix is the type of the node.
+	prefix byte
+
+	// path is the path of a leaf or an extension.
+	path []byte
+
+	// leafData is the data that is stored in a leaf.
+	leafData []byte
+
+	// children contains the hashes of the children of an inner node.
+	children [2][]byte
+
+	// extensionStart is the first bit of the path that is covered by the (remaining) extension.
+	extensionStart int
+
+	// extensionEnd is the first bit of the path that is not covered by the extension anymore.
+	extensionEnd int
+
+	// extensionChild is the hash of the child of an extension.
+	extensionChild []byte
+
+	// extensionHashes contains the hashes of the subtrees that start at the bits of the extension.
+	extensionHashes map[int][]byte
+
+	// isVirtual is true for the remaining part of an extension, which is not stored as a node of its own.
+	isVirtual bool
+}
+
+// diffLeaf is a changed leaf that was found by a treeDiff.
+type diffLeaf struct {
+	path     []byte
+	leafData []byte
+}
+
+// treeDiff walks two sparse merkle trees and reports the leaves that differ.
+type treeDiff struct {
+	hasher      hash.Hash
+	valueHasher hash.Hash
+	hashSize    int
+	source      TreeReader
+	target      TreeReader
+	consumer    func(diffType DiffType, source *diffLeaf, target *diffLeaf) error
+}
+
+// newTreeDiff creates a new treeDiff.
+func newTreeDiff(opts *Options, source TreeReader, target TreeReader, consumer func(diffType DiffType, source *diffLeaf, target *diffLeaf) error) *treeDiff {
+	t := &treeDiff{
+		hasher:   opts.hasher(),
+		source:   source,
+		target:   target,
+		consumer: consumer,
+	}
+	t.hashSize = t.hasher.Size()
+
+	if opts.valueHasher != nil {
+		t.valueHasher = opts.valueHasher()
+	}
+
+	return t
+}
+
+// Run reports all leaves that differ between the given roots.
+func (t *treeDiff) Run(sourceRoot []byte, targetRoot []byte) error {
+	sourceNode, err := t.node(t.source, sourceRoot)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to resolve source root")
+	}
+
+	targetNode, err := t.node(t.target, targetRoot)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to resolve target root")
+	}
+
+	return t.diff(sourceNode, targetNode, 0)
+}
+
+// VerifyLeaf checks that the given key and value bytes belong to the given leaf.
+func (t *treeDiff) VerifyLeaf(leaf *diffLeaf, keyBytes []byte, valueBytes []byte) error {
+	if !bytes.Equal(t.digest(t.hasher, keyBytes), leaf.path) {
+		return ierrors.Wrapf(ErrInvalidTreeData, "key does not match path %x", leaf.path)
+	}
+
+	if t.valueHasher != nil {
+		valueBytes = t.digest(t.valueHasher, valueBytes)
+	}
+
+	if !bytes.Equal(valueBytes, leaf.leafData) {
+		return ierrors.Wrapf(ErrInvalidTreeData, "value does not match leaf %x", leaf.path)
+	}
+
+	return nil
+}
+
+// diff compares the two given nodes at the given depth.
+func (t *treeDiff) diff(source *diffNode, target *diffNode, depth int) error {
+	switch {
+	case source == nil && target == nil:
+		return nil
+	case source != nil && target != nil && bytes.Equal(source.hash, target.hash):
+		return nil
+	case source == nil:
+		return t.reportAll(t.target, target, depth, DiffTypeAdded)
+	case target == nil:
+		return t.reportAll(t.source, source, depth, DiffTypeDeleted)
+	case source.prefix == nodePrefixLeaf && target.prefix == nodePrefixLeaf:
+		if !bytes.Equal(source.path, target.path) {
+			if err := t.consumer(DiffTypeDeleted, source.leaf(), nil); err != nil {
+				return err
+			}
+
+			return t.consumer(DiffTypeAdded, nil, target.leaf())
+		}
+
+		if !bytes.Equal(source.leafData, target.leafData) {
+			return t.consumer(DiffTypeModified, source.leaf(), target.leaf())
+		}
+
+		return nil
+	}
+
+	sourceChildren, err := t.expand(t.source, source, depth)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to expand source node")
+	}
+
+	targetChildren, err := t.expand(t.target, target, depth)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to expand target node")
+	}
+
+	for i := range sourceChildren {
+		if err = t.diff(sourceChildren[i], targetChildren[i], depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportAll reports all leaves below the given node with the given DiffType.
+func (t *treeDiff) reportAll(reader TreeReader, node *diffNode, depth int, diffType DiffType) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.prefix == nodePrefixLeaf {
+		if diffType == DiffTypeAdded {
+			return t.consumer(diffType, nil, node.leaf())
+		}
+
+		return t.consumer(diffType, node.leaf(), nil)
+	}
+
+	children, err := t.expand(reader, node, depth)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err = t.reportAll(reader, child, depth+1, diffType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expand returns the left and right children of the given node at the given depth (leaves are treated as if they
+// were the only child of an inner node).
+func (t *treeDiff) expand(reader TreeReader, node *diffNode, depth int) (children [2]*diffNode, err error) {
+	switch node.prefix {
+	case nodePrefixLeaf:
+		children[pathBit(node.path, depth)] = node
+	case nodePrefixInner:
+		for i, childHash := range node.children {
+			if children[i], err = t.node(reader, childHash); err != nil {
+				return children, err
+			}
+		}
+	case nodePrefixExtension:
+		if node.extensionStart+1 == node.extensionEnd {
+			children[pathBit(node.path, depth)], err = t.node(reader, node.extensionChild)
+
+			return children, err
+		}
+
+		remainingExtension := *node
+		remainingExtension.extensionStart++
+		remainingExtension.hash = node.extensionHashes[remainingExtension.extensionStart]
+		remainingExtension.isVirtual = true
+		children[pathBit(node.path, depth)] = &remainingExtension
+	}
+
+	return children, nil
+}
+
+// node retrieves and parses the node with the given hash (returns nil for empty subtrees).
+func (t *treeDiff) node(reader TreeReader, nodeHash []byte) (*diffNode, error) {
+	if bytes.Equal(nodeHash, make([]byte, t.hashSize)) {
+		return nil, nil
+	}
+
+	preimage, err := reader.Node(nodeHash)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to retrieve node %x", nodeHash)
+	}
+
+	node, err := t.parseNode(nodeHash, preimage)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(t.hashNode(node, preimage), nodeHash) {
+		return nil, ierrors.Wrapf(ErrInvalidTreeData, "preimage does not match node %x", nodeHash)
+	}
+
+	return node, nil
+}
+
+// hashNode returns the hash of the given node (extensions are hashed like the chain of inner nodes they represent).
+func (t *treeDiff) hashNode(node *diffNode, preimage []byte) []byte {
+	if node.prefix != nodePrefixExtension {
+		return t.digest(t.hasher, preimage)
+	}
+
+	node.extensionHashes = make(map[int][]byte, node.extensionEnd-node.extensionStart)
+
+	return t.extensionHash(node.path, node.extensionStart, node.extensionEnd, node.extensionChild, func(position int, subtreeHash []byte) {
+		node.extensionHashes[position] = subtreeHash
+	})
+}
+
+// extensionHash returns the hash of the chain of inner nodes that is represented by an extension (the optional callback
+// is called with the hashes of the subtrees that start at the bits of the extension).
+func (t *treeDiff) extensionHash(path []byte, start int, end int, childHash []byte, optCallback ...func(position int, subtreeHash []byte)) []byte {
+	placeholder := make([]byte, t.hashSize)
+
+	subtreeHash := childHash
+	for position := end - 1; position >= start; position-- {
+		if pathBit(path, position) == 0 {
+			subtreeHash = t.digest(t.hasher, byteutils.ConcatBytes([]byte{nodePrefixInner}, subtreeHash, placeholder))
+		} else {
+			subtreeHash = t.digest(t.hasher, byteutils.ConcatBytes([]byte{nodePrefixInner}, placeholder, subtreeHash))
+		}
+
+		if len(optCallback) > 0 {
+			optCallback[0](position, subtreeHash)
+		}
+	}
+
+	return subtreeHash
+}
+
+// parseNode parses the preimage of a node that was serialized by pokt-network/smt.
+func (t *treeDiff) parseNode(nodeHash []byte, preimage []byte) (*diffNode, error) {
+	if len(preimage) == 0 {
+		return nil, ierrors.Wrapf(ErrInvalidTreeData, "empty preimage of node %x", nodeHash)
+	}
+
+	node := &diffNode{hash: nodeHash, prefix: preimage[0]}
+	data := preimage[1:]
+
+	switch node.prefix {
+	case nodePrefixLeaf:
+		if len(data) < t.hashSize {
+			return nil, ierrors.Wrapf(ErrInvalidTreeData, "leaf %x is too short", nodeHash)
+		}
+
+		node.path, node.leafData = data[:t.hashSize], data[t.hashSize:]
+	case nodePrefixInner:
+		if len(data) != 2*t.hashSize {
+			return nil, ierrors.Wrapf(ErrInvalidTreeData, "inner node %x has an invalid length", nodeHash)
+		}
+
+		node.children = [2][]byte{data[:t.hashSize], data[t.hashSize:]}
+	case nodePrefixExtension:
+		if len(data) != 2+2*t.hashSize || data[0] >= data[1] {
+			return nil, ierrors.Wrapf(ErrInvalidTreeData, "extension %x is invalid", nodeHash)
+		}
+
+		node.extensionStart, node.extensionEnd = int(data[0]), int(data[1])
+		node.path, node.extensionChild = data[2:2+t.hashSize], data[2+t.hashSize:]
+	default:
+		return nil, ierrors.Wrapf(ErrInvalidTreeData, "node %x has an unknown type", nodeHash)
+	}
+
+	return node, nil
+}
+
+// digest returns the digest of the given data.
+func (t *treeDiff) digest(hasher hash.Hash, data []byte) []byte {
+	hasher.Reset()
+	_, _ = hasher.Write(data)
+
+	return hasher.Sum(nil)
+}
+
+// leaf returns the diffLeaf of a leaf node.
+func (d *diffNode) leaf() *diffLeaf {
+	return &diffLeaf{
+		path:     d.path,
+		leafData: d.leafData,
+	}
+}
+
+// pathBit returns the bit of the path at the given position (0 = left, 1 = right).
+func pathBit(path []byte, position int) int {
+	if path[position/8]&(1<<(7-uint(position)%8)) > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,146 @@
+package ads
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
+)
+
+func TestMapDiff(t *testing.T) {
+	newTestMap := func() *authenticatedMap[[32]byte, testKey, testValue] {
+		return newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+		)
+	}
+	collectDiff := func(diffFunc func(func(key testKey, diffType DiffType, sourceValue testValue, targetValue testValue) error) error) map[testKey]string {
+		result := make(map[testKey]string)
+		require.NoError(t, diffFunc(func(key testKey, diffType DiffType, sourceValue testValue, targetValue testValue) error {
+			_, exists := result[key]
+			require.False(t, exists)
+
+			result[key] = fmt.Sprintf("%s %s->%s", diffType, sourceValue, targetValue)
+
+			return nil
+		}))
+
+		return result
+	}
+
+	localMap := newTestMap()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, localMap.Set(testKey{byte(i)}, testValueFromString("1")))
+	}
+	require.NoError(t, localMap.Commit())
+	sourceRoot := localMap.Root()
+
+	for i := 0; i < 10; i++ {
+		require.True(t, lo.PanicOnErr(localMap.Delete(testKey{byte(i)})))
+		require.NoError(t, localMap.Set(testKey{byte(10 + i)}, testValueFromString("2")))
+		require.NoError(t, localMap.Set(testKey{byte(100 + i)}, testValueFromString("3")))
+	}
+	require.NoError(t, localMap.Set(testKey{byte(20)}, testValueFromString("1")))
+	require.NoError(t, localMap.Commit())
+	targetRoot := localMap.Root()
+
+	expectedDiff := make(map[testKey]string)
+	for i := 0; i < 10; i++ {
+		expectedDiff[testKey{byte(i)}] = "Deleted 1->"
+		expectedDiff[testKey{byte(10 + i)}] = "Modified 1->2"
+		expectedDiff[testKey{byte(100 + i)}] = "Added ->3"
+	}
+
+	require.Equal(t, expectedDiff, collectDiff(func(consumer func(testKey, DiffType, testValue, testValue) error) error {
+		return localMap.Diff(sourceRoot, targetRoot, consumer)
+	}))
+	require.Empty(t, collectDiff(func(consumer func(testKey, DiffType, testValue, testValue) error) error {
+		return localMap.Diff(targetRoot, targetRoot, consumer)
+	}))
+	require.Len(t, collectDiff(func(consumer func(testKey, DiffType, testValue, testValue) error) error {
+		return localMap.Diff([32]byte{}, targetRoot, consumer)
+	}), 100)
+
+	// diff against a remote tree that only shares the source state
+	remoteMap := newTestMap()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, remoteMap.Set(testKey{byte(i)}, testValueFromString("1")))
+	}
+	require.NoError(t, remoteMap.Commit())
+	require.Equal(t, sourceRoot, remoteMap.Root())
+
+	remoteReader := &countingTreeReader{TreeReader: localMap.TreeReader()}
+	require.Equal(t, expectedDiff, collectDiff(func(consumer func(testKey, DiffType, testValue, testValue) error) error {
+		return remoteMap.DiffRemote(sourceRoot, remoteReader, targetRoot, consumer)
+	}))
+
+	fullReader := &countingTreeReader{TreeReader: localMap.TreeReader()}
+	require.Len(t, collectDiff(func(consumer func(testKey, DiffType, testValue, testValue) error) error {
+		return remoteMap.DiffRemote([32]byte{}, fullReader, targetRoot, consumer)
+	}), 100)
+	require.Less(t, remoteReader.nodes, fullReader.nodes, "identical subtrees should not be transferred")
+
+	// manipulated remote data is rejected
+	require.ErrorIs(t, remoteMap.DiffRemote(sourceRoot, &tamperingTreeReader{TreeReader: localMap.TreeReader()}, targetRoot, func(testKey, DiffType, testValue, testValue) error {
+		return nil
+	}), ErrInvalidTreeData)
+}
+
+func TestSetDiff(t *testing.T) {
+	newSet := newAuthenticatedSet[[32]byte](
+		mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+
+	require.NoError(t, newSet.Add(testKey{'a'}))
+	require.NoError(t, newSet.Add(testKey{'b'}))
+	require.NoError(t, newSet.Commit())
+	sourceRoot := newSet.Root()
+
+	require.True(t, lo.PanicOnErr(newSet.Delete(testKey{'a'})))
+	require.NoError(t, newSet.Add(testKey{'c'}))
+	require.NoError(t, newSet.Commit())
+
+	diff := make(map[testKey]DiffType)
+	require.NoError(t, newSet.Diff(sourceRoot, newSet.Root(), func(key testKey, diffType DiffType) error {
+		diff[key] = diffType
+
+		return nil
+	}))
+	require.Equal(t, map[testKey]DiffType{{'a'}: DiffTypeDeleted, {'c'}: DiffTypeAdded}, diff)
+}
+
+// countingTreeReader is a TreeReader that counts the requested nodes.
+type countingTreeReader struct {
+	TreeReader
+
+	nodes int
+}
+
+func (c *countingTreeReader) Node(hash []byte) ([]byte, error) {
+	c.nodes++
+
+	return c.TreeReader.Node(hash)
+}
+
+// tamperingTreeReader is a TreeReader that returns manipulated values.
+type tamperingTreeReader struct {
+	TreeReader
+}
+
+func (t *tamperingTreeReader) Leaf(path []byte, leafData []byte) ([]byte, []byte, error) {
+	keyBytes, _, err := t.TreeReader.Leaf(path, leafData)
+
+	return keyBytes, []byte("manipulated"), err
+}
@@ -7,6 +7,7 @@ require (
 	github.com/iotaledger/hive.go/ierrors v0.0.0-20240124160029-1d3bd93f451c
 	github.com/iotaledger/hive.go/kvstore v0.0.0-20240124160029-1d3bd93f451c
 	github.com/iotaledger/hive.go/lo v0.0.0-20240124160029-1d3bd93f451c
+	github.com/iotaledger/hive.go/runtime v0.0.0-20240124160029-1d3bd93f451c
 	github.com/iotaledger/hive.go/serializer/v2 v2.0.0-rc.1.0.20240124160029-1d3bd93f451c
 	github.com/pokt-network/smt v0.9.2
 	github.com/stretchr/testify v1.8.4
@@ -15,7 +16,6 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/iotaledger/hive.go/constraints v0.0.0-20240124160029-1d3bd93f451c // indirect
-	github.com/iotaledger/hive.go/runtime v0.0.0-20240124160029-1d3bd93f451c // indirect
 	github.com/iotaledger/hive.go/stringify v0.0.0-20240124160029-1d3bd93f451c // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/petermattis/goid v0.0.0-20231207134359-e60b3f734c67 // indirect
@@ -0,0 +1,98 @@
+package ads
+
+import (
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// Log is an append-only list that can produce proofs for its values and for its history, which can be verified
+// against a known merkle root that is formed using a merkle mountain range.
+//
+// Leaves are hashed as H(0x00 || value) and inner nodes as H(0x01 || left || right). The peaks of the mountain range
+// are bagged from right to left, so that the root of a log equals the root of the RFC 6962 merkle tree of its values
+// (the root of an empty log is the hash of an empty string).
+type Log[IdentifierType types.IdentifierType, V any] interface {
+	// Append appends the given value to the log and returns its index. The value is persisted immediately.
+	Append(value V) (index uint64, err error)
+
+	// Get returns the value at the given index.
+	Get(index uint64) (value V, exists bool, err error)
+
+	// Size returns the number of values in the log.
+	Size() uint64
+
+	// Root returns the root of the log.
+	Root() IdentifierType
+
+	// RootAt returns the root that the log had when it contained the given number of values.
+	RootAt(size uint64) (IdentifierType, error)
+
+	// InclusionProof returns a proof that the value at the given index is part of the log with the given size.
+	InclusionProof(index uint64, size uint64) (*LogInclusionProof[IdentifierType], error)
+
+	// ConsistencyProof returns a proof that the log with the given new size is an extension of the log with the given
+	// old size.
+	ConsistencyProof(oldSize uint64, newSize uint64) (*LogConsistencyProof[IdentifierType], error)
+
+	// WasRestoredFromStorage returns true if the log was restored from an existing storage.
+	WasRestoredFromStorage() bool
+}
+
+// LogInclusionProof is a proof that a value is part of a Log of a certain size.
+type LogInclusionProof[IdentifierType types.IdentifierType] struct {
+	// Index is the index of the value.
+	Index uint64
+
+	// Size is the size of the log that the proof was created for.
+	Size uint64
+
+	// Path contains the hashes of the siblings on the path from the leaf to the root.
+	Path []IdentifierType
+}
+
+// LogConsistencyProof is a proof that a Log of a certain size is an extension of the same Log at a smaller size.
+type LogConsistencyProof[IdentifierType types.IdentifierType] struct {
+	// OldSize is the size of the older log.
+	OldSize uint64
+
+	// NewSize is the size of the newer log.
+	NewSize uint64
+
+	// Path contains the hashes of the subtrees that are needed to compute both roots.
+	Path []IdentifierType
+}
+
+// NewLog creates a new Log (only the hasher of the options is used).
+//
+// It panics if the log can not be opened, e.g. if the store was created with a different hasher (ErrHasherMismatch)
+// or the hasher does not produce identifiers of the expected size (ErrInvalidHasher) - use OpenLog to handle these
+// errors instead.
+func NewLog[IdentifierType types.IdentifierType, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) Log[IdentifierType, V] {
+	return newAuthenticatedLog[IdentifierType](store, identifierToBytes, bytesToIdentifier, valueToBytes, bytesToValue, opts...)
+}
+
+// OpenLog opens the Log in the given store and returns an error if it can not be opened (only the hasher of the options
+// is used).
+func OpenLog[IdentifierType types.IdentifierType, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (Log[IdentifierType, V], error) {
+	openedLog, err := openAuthenticatedLog[IdentifierType](store, identifierToBytes, bytesToIdentifier, valueToBytes, bytesToValue, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return openedLog, nil
+}
@@ -0,0 +1,467 @@
+package ads
+
+import (
+	"bytes"
+	"hash"
+	"math/bits"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
+)
+
+const (
+	prefixLogSizeKey uint8 = iota
+	prefixLogNodesStorage
+	prefixLogValuesStorage
+	prefixLogHasherKey
+)
+
+// ErrIndexOutOfBounds is returned when a Log is accessed at an index or size that it does not have (yet).
+var ErrIndexOutOfBounds = ierrors.New("index out of bounds")
+
+// authenticatedLog is a merkle mountain range based append-only log.
+type authenticatedLog[IdentifierType types.IdentifierType, V any] struct {
+	store   kvstore.KVStore
+	nodes   *kvstore.TypedStore[uint64, IdentifierType]
+	values  *kvstore.TypedStore[uint64, V]
+	size    *kvstore.TypedValue[uint64]
+	root    IdentifierType
+	options *Options
+	hasher  hash.Hash
+	mutex   sync.RWMutex
+
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType]
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType]
+	valueToBytes      kvstore.ObjectToBytes[V]
+	bytesToValue      kvstore.BytesToObject[V]
+}
+
+// newAuthenticatedLog creates a new authenticated log and panics if it can not be opened.
+func newAuthenticatedLog[IdentifierType types.IdentifierType, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) *authenticatedLog[IdentifierType, V] {
+	return lo.PanicOnErr(openAuthenticatedLog(store, identifierToBytes, bytesToIdentifier, valueToBytes, bytesToValue, opts...))
+}
+
+// openAuthenticatedLog opens the authenticated log in the given store.
+func openAuthenticatedLog[IdentifierType types.IdentifierType, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (*authenticatedLog[IdentifierType, V], error) {
+	newLog := &authenticatedLog[IdentifierType, V]{
+		store:   store,
+		options: newOptions(opts...),
+
+		identifierToBytes: identifierToBytes,
+		bytesToIdentifier: bytesToIdentifier,
+		valueToBytes:      valueToBytes,
+		bytesToValue:      bytesToValue,
+	}
+	newLog.hasher = newLog.options.hasher()
+
+	if err := newLog.initStores(store); err != nil {
+		return nil, err
+	}
+
+	if err := newLog.verifyHasher(store); err != nil {
+		return nil, err
+	}
+
+	size, err := newLog.currentSize()
+	if err != nil {
+		return nil, err
+	}
+
+	if newLog.root, err = newLog.rootAt(newLog.hasher, size); err != nil {
+		return nil, err
+	}
+
+	return newLog, nil
+}
+
+// WasRestoredFromStorage returns true if the log has been restored from storage.
+func (l *authenticatedLog[IdentifierType, V]) WasRestoredFromStorage() bool {
+	_, err := l.size.Get()
+	return !ierrors.Is(err, kvstore.ErrKeyNotFound)
+}
+
+// Append appends the given value to the log and returns its index.
+//
+// Unlike the modifications of a Map, the value is persisted immediately and there is no Commit. All changes of an
+// Append are written in a single batch, so a failed Append does not leave any partial changes in the storage.
+func (l *authenticatedLog[IdentifierType, V]) Append(value V) (index uint64, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if index, err = l.currentSize(); err != nil {
+		return 0, err
+	}
+
+	stagingStore := newStagedStore(l.store)
+	stagingLog := &authenticatedLog[IdentifierType, V]{
+		options: l.options,
+		hasher:  l.hasher,
+
+		identifierToBytes: l.identifierToBytes,
+		bytesToIdentifier: l.bytesToIdentifier,
+		valueToBytes:      l.valueToBytes,
+		bytesToValue:      l.bytesToValue,
+	}
+	if err = stagingLog.initStores(stagingStore); err != nil {
+		return 0, err
+	}
+
+	root, err := stagingLog.append(index, value)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = stagingStore.WriteBatch(); err != nil {
+		return 0, ierrors.Wrapf(err, "failed to write value %d", index)
+	}
+
+	// the size is reopened, as its cached value is outdated
+	if err = l.initStores(l.store); err != nil {
+		return 0, err
+	}
+
+	l.root = root
+
+	return index, nil
+}
+
+// Get returns the value at the given index.
+func (l *authenticatedLog[IdentifierType, V]) Get(index uint64) (value V, exists bool, err error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	size, err := l.currentSize()
+	if err != nil {
+		return value, false, err
+	}
+
+	if index >= size {
+		return value, false, nil
+	}
+
+	if value, err = l.values.Get(index); err != nil {
+		return value, false, ierrors.Wrapf(err, "failed to get value %d", index)
+	}
+
+	return value, true, nil
+}
+
+// Size returns the number of values in the log.
+func (l *authenticatedLog[IdentifierType, V]) Size() uint64 {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	size, err := l.currentSize()
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// Root returns the root of the log.
+func (l *authenticatedLog[IdentifierType, V]) Root() IdentifierType {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.root
+}
+
+// RootAt returns the root that the log had when it contained the given number of values.
+func (l *authenticatedLog[IdentifierType, V]) RootAt(size uint64) (root IdentifierType, err error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if err = l.checkSize(size); err != nil {
+		return root, err
+	}
+
+	return l.rootAt(l.options.hasher(), size)
+}
+
+// InclusionProof returns a proof that the value at the given index is part of the log with the given size.
+func (l *authenticatedLog[IdentifierType, V]) InclusionProof(index uint64, size uint64) (*LogInclusionProof[IdentifierType], error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if err := l.checkSize(size); err != nil {
+		return nil, err
+	} else if index >= size {
+		return nil, ierrors.Wrapf(ErrIndexOutOfBounds, "index %d is out of bounds for size %d", index, size)
+	}
+
+	path, err := l.inclusionPath(l.options.hasher(), index, 0, size)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to create inclusion proof for index %d", index)
+	}
+
+	return &LogInclusionProof[IdentifierType]{
+		Index: index,
+		Size:  size,
+		Path:  path,
+	}, nil
+}
+
+// ConsistencyProof returns a proof that the log with the given new size is an extension of the log with the given old
+// size.
+func (l *authenticatedLog[IdentifierType, V]) ConsistencyProof(oldSize uint64, newSize uint64) (*LogConsistencyProof[IdentifierType], error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if err := l.checkSize(newSize); err != nil {
+		return nil, err
+	} else if oldSize > newSize {
+		return nil, ierrors.Wrapf(ErrIndexOutOfBounds, "old size %d is larger than new size %d", oldSize, newSize)
+	}
+
+	proof := &LogConsistencyProof[IdentifierType]{
+		OldSize: oldSize,
+		NewSize: newSize,
+		Path:    make([]IdentifierType, 0),
+	}
+
+	if oldSize == 0 || oldSize == newSize {
+		return proof, nil
+	}
+
+	path, err := l.consistencyPath(l.options.hasher(), oldSize, 0, newSize, true)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to create consistency proof for sizes %d and %d", oldSize, newSize)
+	}
+	proof.Path = path
+
+	return proof, nil
+}
+
+// initStores initializes the typed stores of the log in the given store.
+func (l *authenticatedLog[IdentifierType, V]) initStores(store kvstore.KVStore) error {
+	nodesStore, err := store.WithExtendedRealm([]byte{prefixLogNodesStorage})
+	if err != nil {
+		return ierrors.Wrap(err, "failed to create nodes storage")
+	}
+
+	valuesStore, err := store.WithExtendedRealm([]byte{prefixLogValuesStorage})
+	if err != nil {
+		return ierrors.Wrap(err, "failed to create values storage")
+	}
+
+	l.nodes = kvstore.NewTypedStore(nodesStore, versionToBytes, versionFromBytes, l.identifierToBytes, l.bytesToIdentifier)
+	l.values = kvstore.NewTypedStore(valuesStore, versionToBytes, versionFromBytes, l.valueToBytes, l.bytesToValue)
+	l.size = kvstore.NewTypedValue(store, []byte{prefixLogSizeKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+
+	return nil
+}
+
+// append writes the given value with the given index and the nodes that it completes to the stores of the log and
+// returns the resulting root (without locking).
+func (l *authenticatedLog[IdentifierType, V]) append(index uint64, value V) (root IdentifierType, err error) {
+	valueBytes, err := l.valueToBytes(value)
+	if err != nil {
+		return root, ierrors.Wrap(err, "failed to serialize value")
+	}
+
+	nodeHash := logLeafHash[IdentifierType](l.hasher, valueBytes)
+	if err = l.nodes.Set(logLeafPosition(index), nodeHash); err != nil {
+		return root, ierrors.Wrapf(err, "failed to set leaf %d", index)
+	}
+
+	// merge all perfect subtrees that are completed by the new leaf
+	for height := 1; (index+1)%(1<<height) == 0; height++ {
+		start := index + 1 - (1 << height)
+
+		leftHash, err := l.nodes.Get(logSubtreePosition(start, height-1))
+		if err != nil {
+			return root, ierrors.Wrapf(err, "failed to get left child of subtree %d/%d", start, height)
+		}
+
+		nodeHash = logNodeHash(l.hasher, leftHash, nodeHash)
+		if err = l.nodes.Set(logSubtreePosition(start, height), nodeHash); err != nil {
+			return root, ierrors.Wrapf(err, "failed to set subtree %d/%d", start, height)
+		}
+	}
+
+	if err = l.values.Set(index, value); err != nil {
+		return root, ierrors.Wrapf(err, "failed to set value %d", index)
+	}
+
+	if root, err = l.rootAt(l.hasher, index+1); err != nil {
+		return root, err
+	}
+
+	if err = l.size.Set(index + 1); err != nil {
+		return root, ierrors.Wrap(err, "failed to set size")
+	}
+
+	return root, nil
+}
+
+// currentSize returns the number of values in the log (without locking).
+func (l *authenticatedLog[IdentifierType, V]) currentSize() (uint64, error) {
+	size, err := l.size.Get()
+	if err != nil {
+		if ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return 0, nil
+		}
+
+		return 0, ierrors.Wrap(err, "failed to get size")
+	}
+
+	return size, nil
+}
+
+// checkSize makes sure that the log has (had) the given size.
+func (l *authenticatedLog[IdentifierType, V]) checkSize(size uint64) error {
+	currentSize, err := l.currentSize()
+	if err != nil {
+		return err
+	}
+
+	if size > currentSize {
+		return ierrors.Wrapf(ErrIndexOutOfBounds, "size %d is larger than the size of the log %d", size, currentSize)
+	}
+
+	return nil
+}
+
+// rootAt returns the root of the log with the given size.
+func (l *authenticatedLog[IdentifierType, V]) rootAt(hasher hash.Hash, size uint64) (root IdentifierType, err error) {
+	// the root of an empty log is the hash of an empty string (as defined by RFC 6962)
+	if size == 0 {
+		hasher.Reset()
+
+		return IdentifierType(hasher.Sum(nil)), nil
+	}
+
+	if root, err = l.subtreeHash(hasher, 0, size); err != nil {
+		return root, ierrors.Wrapf(err, "failed to compute root of size %d", size)
+	}
+
+	return root, nil
+}
+
+// subtreeHash returns the hash of the subtree that covers the given number of leaves starting at the given index.
+func (l *authenticatedLog[IdentifierType, V]) subtreeHash(hasher hash.Hash, start uint64, size uint64) (subtreeHash IdentifierType, err error) {
+	// perfect subtrees are stored as nodes of the mountain range
+	if bits.OnesCount64(size) == 1 {
+		return l.nodes.Get(logSubtreePosition(start, bits.TrailingZeros64(size)))
+	}
+
+	split := logSplit(size)
+
+	leftHash, err := l.subtreeHash(hasher, start, split)
+	if err != nil {
+		return subtreeHash, err
+	}
+
+	rightHash, err := l.subtreeHash(hasher, start+split, size-split)
+	if err != nil {
+		return subtreeHash, err
+	}
+
+	return logNodeHash(hasher, leftHash, rightHash), nil
+}
+
+// inclusionPath returns the inclusion path of the leaf with the given index in the subtree that covers the given number
+// of leaves starting at the given index.
+func (l *authenticatedLog[IdentifierType, V]) inclusionPath(hasher hash.Hash, index uint64, start uint64, size uint64) ([]IdentifierType, error) {
+	if size == 1 {
+		return make([]IdentifierType, 0), nil
+	}
+
+	split := logSplit(size)
+	if index < split {
+		path, err := l.inclusionPath(hasher, index, start, split)
+
+		return l.extendPath(hasher, path, err, start+split, size-split)
+	}
+
+	path, err := l.inclusionPath(hasher, index-split, start+split, size-split)
+
+	return l.extendPath(hasher, path, err, start, split)
+}
+
+// consistencyPath returns the consistency path between the first given number of leaves and the subtree that covers
+// the given number of leaves starting at the given index.
+func (l *authenticatedLog[IdentifierType, V]) consistencyPath(hasher hash.Hash, oldSize uint64, start uint64, size uint64, isComplete bool) ([]IdentifierType, error) {
+	if oldSize == size {
+		if isComplete {
+			return make([]IdentifierType, 0), nil
+		}
+
+		subtreeHash, err := l.subtreeHash(hasher, start, size)
+
+		return []IdentifierType{subtreeHash}, err
+	}
+
+	split := logSplit(size)
+	if oldSize <= split {
+		path, err := l.consistencyPath(hasher, oldSize, start, split, isComplete)
+
+		return l.extendPath(hasher, path, err, start+split, size-split)
+	}
+
+	path, err := l.consistencyPath(hasher, oldSize-split, start+split, size-split, false)
+
+	return l.extendPath(hasher, path, err, start, split)
+}
+
+// extendPath appends the hash of the given subtree to the given path.
+func (l *authenticatedLog[IdentifierType, V]) extendPath(hasher hash.Hash, path []IdentifierType, err error, start uint64, size uint64) ([]IdentifierType, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	subtreeHash, err := l.subtreeHash(hasher, start, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(path, subtreeHash), nil
+}
+
+// verifyHasher makes sure that the log is opened with the hash function that it was created with.
+func (l *authenticatedLog[IdentifierType, V]) verifyHasher(store kvstore.KVStore) error {
+	if hasherSize, identifierSize := l.hasher.Size(), len(IdentifierType{}); hasherSize != identifierSize {
+		return ierrors.Wrapf(ErrInvalidHasher, "hasher produces %d bytes instead of %d bytes", hasherSize, identifierSize)
+	}
+
+	fingerprint := hasherFingerprint(l.options.hasher)
+
+	storedFingerprint, err := store.Get([]byte{prefixLogHasherKey})
+	if err != nil {
+		if !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return ierrors.Wrap(err, "failed to get hasher")
+		}
+
+		if err = store.Set([]byte{prefixLogHasherKey}, fingerprint); err != nil {
+			return ierrors.Wrap(err, "failed to set hasher")
+		}
+
+		return nil
+	}
+
+	if !bytes.Equal(fingerprint, storedFingerprint) {
+		return ierrors.Wrap(ErrHasherMismatch, "the log was created with a different hasher")
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package ads
+
+import (
+	"hash"
+	"math/bits"
+
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+const (
+	// logLeafPrefix is the domain separation prefix of the leaves of a Log.
+	logLeafPrefix byte = iota
+
+	// logNodePrefix is the domain separation prefix of the inner nodes of a Log.
+	logNodePrefix
+)
+
+// ErrInvalidProof is returned when a proof does not match the given roots.
+var ErrInvalidProof = ierrors.New("invalid proof")
+
+// VerifyLogInclusionProof checks that the given value bytes are part of the Log with the given root.
+func VerifyLogInclusionProof[IdentifierType types.IdentifierType](proof *LogInclusionProof[IdentifierType], valueBytes []byte, root IdentifierType, opts ...options.Option[Options]) error {
+	if proof.Index >= proof.Size {
+		return ierrors.Wrapf(ErrInvalidProof, "index %d is out of bounds for size %d", proof.Index, proof.Size)
+	}
+
+	hasher := newOptions(opts...).hasher()
+	index, lastIndex := proof.Index, proof.Size-1
+	result := logLeafHash[IdentifierType](hasher, valueBytes)
+
+	for _, sibling := range proof.Path {
+		if lastIndex == 0 {
+			return ierrors.Wrap(ErrInvalidProof, "path is too long")
+		}
+
+		if index%2 == 1 || index == lastIndex {
+			result = logNodeHash(hasher, sibling, result)
+
+			for index%2 == 0 && index != 0 {
+				index, lastIndex = index>>1, lastIndex>>1
+			}
+		} else {
+			result = logNodeHash(hasher, result, sibling)
+		}
+
+		index, lastIndex = index>>1, lastIndex>>1
+	}
+
+	if lastIndex != 0 || result != root {
+		return ierrors.Wrapf(ErrInvalidProof, "value %d is not part of the log", proof.Index)
+	}
+
+	return nil
+}
+
+// VerifyLogConsistencyProof checks that the Log with the given new root is an extension of the Log with the given old
+// root.
+func VerifyLogConsistencyProof[IdentifierType types.IdentifierType](proof *LogConsistencyProof[IdentifierType], oldRoot IdentifierType, newRoot IdentifierType, opts ...options.Option[Options]) error {
+	switch {
+	case proof.OldSize > proof.NewSize:
+		return ierrors.Wrapf(ErrInvalidProof, "old size %d is larger than new size %d", proof.OldSize, proof.NewSize)
+	case proof.OldSize == 0:
+		if len(proof.Path) != 0 {
+			return ierrors.Wrap(ErrInvalidProof, "proof of an empty log must be empty")
+		}
+
+		return nil
+	case proof.OldSize == proof.NewSize:
+		if len(proof.Path) != 0 || oldRoot != newRoot {
+			return ierrors.Wrap(ErrInvalidProof, "roots of logs with the same size must be equal")
+		}
+
+		return nil
+	}
+
+	path := proof.Path
+	if bits.OnesCount64(proof.OldSize) == 1 {
+		path = append([]IdentifierType{oldRoot}, path...)
+	}
+
+	if len(path) == 0 {
+		return ierrors.Wrap(ErrInvalidProof, "path is empty")
+	}
+
+	hasher := newOptions(opts...).hasher()
+	index, lastIndex := proof.OldSize-1, proof.NewSize-1
+	for index%2 == 1 {
+		index, lastIndex = index>>1, lastIndex>>1
+	}
+
+	oldResult, newResult := path[0], path[0]
+	for _, sibling := range path[1:] {
+		if lastIndex == 0 {
+			return ierrors.Wrap(ErrInvalidProof, "path is too long")
+		}
+
+		if index%2 == 1 || index == lastIndex {
+			oldResult = logNodeHash(hasher, sibling, oldResult)
+			newResult = logNodeHash(hasher, sibling, newResult)
+
+			for index%2 == 0 && index != 0 {
+				index, lastIndex = index>>1, lastIndex>>1
+			}
+		} else {
+			newResult = logNodeHash(hasher, newResult, sibling)
+		}
+
+		index, lastIndex = index>>1, lastIndex>>1
+	}
+
+	if lastIndex != 0 || oldResult != oldRoot || newResult != newRoot {
+		return ierrors.Wrapf(ErrInvalidProof, "log of size %d is not an extension of log of size %d", proof.NewSize, proof.OldSize)
+	}
+
+	return nil
+}
+
+// logLeafHash returns the hash of a leaf with the given value bytes.
+func logLeafHash[IdentifierType types.IdentifierType](hasher hash.Hash, valueBytes []byte) (leafHash IdentifierType) {
+	hasher.Reset()
+	_, _ = hasher.Write([]byte{logLeafPrefix})
+	_, _ = hasher.Write(valueBytes)
+
+	return IdentifierType(hasher.Sum(nil))
+}
+
+// logNodeHash returns the hash of an inner node with the given children.
+func logNodeHash[IdentifierType types.IdentifierType](hasher hash.Hash, left IdentifierType, right IdentifierType) (nodeHash IdentifierType) {
+	hasher.Reset()
+	_, _ = hasher.Write([]byte{logNodePrefix})
+	_, _ = hasher.Write(left[:])
+	_, _ = hasher.Write(right[:])
+
+	return IdentifierType(hasher.Sum(nil))
+}
+
+// logSplit returns the largest power of two that is smaller than the given size (size must be at least 2).
+func logSplit(size uint64) uint64 {
+	return 1 << (bits.Len64(size-1) - 1)
+}
+
+// logLeafPosition returns the position of the leaf with the given index in the post-order of the mountain range.
+func logLeafPosition(index uint64) uint64 {
+	return 2*index - uint64(bits.OnesCount64(index))
+}
+
+// logSubtreePosition returns the position of the root of the perfect subtree with the given height, which covers the
+// leaves that start at the given (aligned) index, in the post-order of the mountain range.
+func logSubtreePosition(start uint64, height int) uint64 {
+	return logLeafPosition(start+(1<<height)-1) + uint64(height)
+}
@@ -0,0 +1,251 @@
+package ads
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
+)
+
+func TestLog(t *testing.T) {
+	store := mapdb.NewMapDB()
+	newTestLog := func() *authenticatedLog[[32]byte, testValue] {
+		return newAuthenticatedLog[[32]byte](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+		)
+	}
+
+	newLog := newTestLog()
+	require.False(t, newLog.WasRestoredFromStorage())
+	require.Equal(t, uint64(0), newLog.Size())
+	require.Equal(t, sha256.Sum256(nil), newLog.Root())
+	require.Equal(t, rfc6962Root(nil), newLog.Root())
+
+	values := make([]testValue, 0)
+	roots := [][32]byte{newLog.Root()}
+	for i := 0; i < 21; i++ {
+		values = append(values, testValueFromString(string(rune('a'+i))))
+		require.Equal(t, uint64(i), lo.PanicOnErr(newLog.Append(values[i])))
+		require.NotContains(t, roots, newLog.Root())
+
+		roots = append(roots, newLog.Root())
+	}
+
+	// the root equals the root of the RFC 6962 merkle tree
+	require.Equal(t, rfc6962Root(values), newLog.Root())
+
+	for size := uint64(0); size <= newLog.Size(); size++ {
+		require.Equal(t, roots[size], lo.PanicOnErr(newLog.RootAt(size)))
+
+		for index := uint64(0); index < size; index++ {
+			proof, err := newLog.InclusionProof(index, size)
+			require.NoError(t, err)
+			require.NoError(t, VerifyLogInclusionProof(proof, lo.PanicOnErr(values[index].Bytes()), roots[size]))
+			require.ErrorIs(t, VerifyLogInclusionProof(proof, []byte("other"), roots[size]), ErrInvalidProof)
+		}
+
+		for oldSize := uint64(0); oldSize <= size; oldSize++ {
+			proof, err := newLog.ConsistencyProof(oldSize, size)
+			require.NoError(t, err)
+			require.NoError(t, VerifyLogConsistencyProof(proof, roots[oldSize], roots[size]))
+
+			if oldSize > 0 && oldSize < size {
+				require.ErrorIs(t, VerifyLogConsistencyProof(proof, roots[oldSize-1], roots[size]), ErrInvalidProof)
+				require.ErrorIs(t, VerifyLogConsistencyProof(proof, roots[oldSize], roots[size-1]), ErrInvalidProof)
+			}
+		}
+	}
+
+	value, exists, err := newLog.Get(3)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, values[3], value)
+
+	_, exists, err = newLog.Get(21)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = newLog.InclusionProof(21, 22)
+	require.ErrorIs(t, err, ErrIndexOutOfBounds)
+	_, err = newLog.ConsistencyProof(5, 4)
+	require.ErrorIs(t, err, ErrIndexOutOfBounds)
+
+	// restore the log from the storage
+	restoredLog := newTestLog()
+	require.True(t, restoredLog.WasRestoredFromStorage())
+	require.Equal(t, newLog.Size(), restoredLog.Size())
+	require.Equal(t, newLog.Root(), restoredLog.Root())
+
+	require.Equal(t, uint64(21), lo.PanicOnErr(restoredLog.Append(testValueFromString("v"))))
+	require.NoError(t, VerifyLogConsistencyProof(lo.PanicOnErr(restoredLog.ConsistencyProof(21, 22)), roots[21], restoredLog.Root()))
+
+	// the hasher can not be changed after the log was created
+	require.Panics(t, func() {
+		defer func() {
+			err, isError := recover().(error)
+			require.True(t, isError)
+			require.ErrorIs(t, err, ErrHasherMismatch)
+
+			panic(err)
+		}()
+
+		newAuthenticatedLog[[32]byte](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			WithHasher(sha512.New512_256),
+		)
+	})
+
+	_, err = OpenLog[[32]byte](store,
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+		WithHasher(sha512.New512_256),
+	)
+	require.ErrorIs(t, err, ErrHasherMismatch)
+}
+
+func TestLogAppendIsAtomic(t *testing.T) {
+	store := &failingBatchStore{KVStore: mapdb.NewMapDB()}
+	newLog := newAuthenticatedLog[[32]byte](store,
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+	)
+	values := []testValue{testValueFromString("a"), testValueFromString("b"), testValueFromString("c"), testValueFromString("d")}
+	for _, value := range values[:3] {
+		lo.PanicOnErr(newLog.Append(value))
+	}
+
+	root := newLog.Root()
+	storedEntries := func() (count int) {
+		require.NoError(t, store.IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+			count++
+
+			return true
+		}))
+
+		return count
+	}
+	entriesBefore := storedEntries()
+
+	// the 4th value completes two subtrees, but a failed batch does not write any of their nodes
+	store.failing = true
+	_, err := newLog.Append(values[3])
+	require.ErrorIs(t, err, errStorageFailure)
+	require.Equal(t, entriesBefore, storedEntries())
+	require.Equal(t, uint64(3), newLog.Size())
+	require.Equal(t, root, newLog.Root())
+
+	store.failing = false
+	require.Equal(t, uint64(3), lo.PanicOnErr(newLog.Append(values[3])))
+	require.Equal(t, rfc6962Root(values), newLog.Root())
+}
+
+// rfc6962Root computes the merkle tree hash of RFC 6962 for the given values.
+func rfc6962Root(values []testValue) (root [32]byte) {
+	switch len(values) {
+	case 0:
+		return sha256.Sum256(nil)
+	case 1:
+		return sha256.Sum256(append([]byte{logLeafPrefix}, lo.PanicOnErr(values[0].Bytes())...))
+	}
+
+	split := logSplit(uint64(len(values)))
+	left, right := rfc6962Root(values[:split]), rfc6962Root(values[split:])
+
+	return sha256.Sum256(append(append([]byte{logNodePrefix}, left[:]...), right[:]...))
+}
+
+func TestLogSizeStorageError(t *testing.T) {
+	store := &failingSizeStore{KVStore: mapdb.NewMapDB()}
+	newLog := newAuthenticatedLog[[32]byte](store,
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+	)
+	require.Equal(t, uint64(0), lo.PanicOnErr(newLog.Append(testValueFromString("a"))))
+
+	// errors of the storage are not mistaken for an empty log (the size is reopened to drop its cached value)
+	store.failing = true
+	newLog.size = kvstore.NewTypedValue(store, []byte{prefixLogSizeKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+
+	_, err := newLog.Append(testValueFromString("b"))
+	require.ErrorIs(t, err, errStorageFailure)
+
+	_, _, err = newLog.Get(0)
+	require.ErrorIs(t, err, errStorageFailure)
+
+	_, err = newLog.RootAt(1)
+	require.ErrorIs(t, err, errStorageFailure)
+
+	store.failing = false
+	require.Equal(t, uint64(1), newLog.Size())
+	require.Equal(t, uint64(1), lo.PanicOnErr(newLog.Append(testValueFromString("b"))))
+}
+
+var errStorageFailure = ierrors.New("storage failure")
+
+// failingSizeStore is a KVStore that fails to read the size of a log while failing is set.
+type failingSizeStore struct {
+	kvstore.KVStore
+
+	failing bool
+}
+
+func (f *failingSizeStore) Get(key kvstore.Key) (kvstore.Value, error) {
+	if f.failing && bytes.Equal(key, []byte{prefixLogSizeKey}) {
+		return nil, errStorageFailure
+	}
+
+	return f.KVStore.Get(key)
+}
+
+// failingBatchStore is a KVStore whose batches fail to commit while failing is set.
+type failingBatchStore struct {
+	kvstore.KVStore
+
+	failing bool
+}
+
+func (f *failingBatchStore) Batched() (kvstore.BatchedMutations, error) {
+	batch, err := f.KVStore.Batched()
+	if err != nil {
+		return nil, err
+	}
+
+	return &failingBatch{BatchedMutations: batch, store: f}, nil
+}
+
+// failingBatch is a batch of a failingBatchStore.
+type failingBatch struct {
+	kvstore.BatchedMutations
+
+	store *failingBatchStore
+}
+
+func (f *failingBatch) Commit() error {
+	if f.store.failing {
+		f.Cancel()
+
+		return errStorageFailure
+	}
+
+	return f.BatchedMutations.Commit()
+}
@@ -1,8 +1,11 @@
 package ads
 
 import (
+	"io"
+
 	"github.com/iotaledger/hive.go/ds/types"
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
 )
 
 // Map is a map that can produce proofs for its values which can be verified against a known merkle root
@@ -23,20 +26,86 @@ type Map[IdentifierType types.IdentifierType, K, V any] interface {
 	// Stream streams all key-value pairs to the given consumer function.
 	Stream(consumerFunc func(key K, value V) error) error
 
-	// Commit persists the changes to the underlying store.
+	// StreamRange streams the key-value pairs in the range that is defined by the given options (ordered by their
+	// serialized keys) and returns the cursor of the next element (nil if there are no more elements).
+	StreamRange(consumerFunc func(key K, value V) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error)
+
+	// Commit persists the changes to the underlying store as a new version.
 	Commit() error
 
+	// ApplyBatch applies the given changes and commits them as a new version (the map must not have uncommitted changes).
+	// The changes are hashed in parallel and written to the underlying store in a single batch.
+	ApplyBatch(changes []BatchChange[K, V]) error
+
 	// Root returns the root of the sparse merkle tree.
 	Root() IdentifierType
 
 	// Size returns the number of elements in the map.
 	Size() int
 
+	// Version returns the version of the latest commit (0 if the map was never committed).
+	Version() uint64
+
+	// ReadAt returns a read-only view of the map at the given committed version.
+	ReadAt(version uint64) (ReadOnlyMap[IdentifierType, K, V], error)
+
+	// Rollback restores the map to the given committed version and discards all later versions and uncommitted changes.
+	Rollback(version uint64) error
+
+	// Prune discards all versions older than the given version and deletes the tree nodes that are no longer needed.
+	Prune(version uint64) error
+
+	// Diff streams the keys that differ between the given committed roots of the map.
+	Diff(sourceRoot IdentifierType, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType, sourceValue V, targetValue V) error) error
+
+	// DiffRemote streams the keys that differ between the given committed root of the map and the root of a remote
+	// tree, that is accessed through the given TreeReader.
+	DiffRemote(sourceRoot IdentifierType, remote TreeReader, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType, sourceValue V, targetValue V) error) error
+
+	// TreeReader returns a TreeReader for the committed nodes of the map (e.g. to serve the requests of remote peers).
+	TreeReader() TreeReader
+
+	// Export writes the key-value pairs of the latest committed version to the given writer (see ImportMap).
+	Export(writer io.Writer) error
+
+	// ExportNodes writes the tree nodes of the latest committed version to the given writer (in depth-first order,
+	// so that every node can be verified against its parent and the root while reading, see ImportMapNodes).
+	ExportNodes(writer io.Writer) error
+
 	// WasRestoredFromStorage returns true if the map was restored from an existing storage.
 	WasRestoredFromStorage() bool
 }
 
+// ReadOnlyMap is a read-only view of a Map at a committed version.
+type ReadOnlyMap[IdentifierType types.IdentifierType, K, V any] interface {
+	// Get returns the value for the given key.
+	Get(key K) (value V, exists bool, err error)
+
+	// Has returns true if the given key exists.
+	Has(key K) (exists bool, err error)
+
+	// Stream streams all key-value pairs to the given consumer function.
+	Stream(consumerFunc func(key K, value V) error) error
+
+	// StreamRange streams the key-value pairs in the range that is defined by the given options (ordered by their
+	// serialized keys) and returns the cursor of the next element (nil if there are no more elements).
+	StreamRange(consumerFunc func(key K, value V) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error)
+
+	// Root returns the root of the sparse merkle tree.
+	Root() IdentifierType
+
+	// Size returns the number of elements in the map.
+	Size() int
+
+	// Version returns the version of the view.
+	Version() uint64
+}
+
 // NewMap creates a new AuthenticatedMap.
+//
+// It panics if the map can not be opened, e.g. because the store was created with different hash functions (see
+// ErrHasherMismatch) or the hash function does not fit the IdentifierType (see ErrInvalidHasher). Use OpenMap to
+// handle these errors instead.
 func NewMap[IdentifierType types.IdentifierType, K, V any](
 	store kvstore.KVStore,
 	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
@@ -45,6 +114,26 @@ func NewMap[IdentifierType types.IdentifierType, K, V any](
 	bytesToKey kvstore.BytesToObject[K],
 	valueToBytes kvstore.ObjectToBytes[V],
 	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
 ) Map[IdentifierType, K, V] {
-	return newAuthenticatedMap[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue)
+	return newAuthenticatedMap[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+}
+
+// OpenMap creates a new AuthenticatedMap and returns an error if the map can not be opened.
+func OpenMap[IdentifierType types.IdentifierType, K, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (Map[IdentifierType, K, V], error) {
+	authenticatedMap, err := openAuthenticatedMap[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticatedMap, nil
 }
@@ -1,7 +1,9 @@
 package ads
 
 import (
-	"crypto/sha256"
+	"bytes"
+	"hash"
+	"sort"
 	"sync"
 
 	"github.com/pokt-network/smt"
@@ -10,6 +12,9 @@ import (
 	"github.com/iotaledger/hive.go/ierrors"
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
 	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
 )
 
@@ -18,22 +23,63 @@ const (
 	prefixTreeStorage
 	prefixRootKey
 	prefixSizeKey
+	prefixVersionKey
+	prefixOldestVersionKey
+	prefixVersionRootsStorage
+	prefixVersionSizesStorage
+	prefixOrphansStorage
+	prefixChangesStorage
+	prefixValuesStorage
+	prefixValueOrphansStorage
+	prefixHashersKey
+	prefixKeyPathsStorage
+	prefixKeyPathOrphansStorage
+)
+
+var (
+	// ErrHasherMismatch is returned when a tree is opened with different hash functions than it was created with.
+	ErrHasherMismatch = ierrors.New("hasher mismatch")
+
+	// ErrInvalidHasher is returned when the hash function does not produce digests of the size of the identifier.
+	ErrInvalidHasher = ierrors.New("invalid hasher")
+
+	// ErrUncommittedChanges is returned when a batch is applied to a map that has uncommitted changes.
+	ErrUncommittedChanges = ierrors.New("uncommitted changes")
+
+	// ErrInvalidBatchWorkerPool is returned when a map is opened with a batch worker pool that has no workers.
+	ErrInvalidBatchWorkerPool = ierrors.New("invalid batch worker pool")
 )
 
 // AuthenticatedMap is a sparse merkle tree based map.
 type authenticatedMap[IdentifierType types.IdentifierType, K, V any] struct {
-	rawKeysStore *kvstore.TypedStore[K, types.Empty]
-	tree         *smt.SMT
-	size         *kvstore.TypedValue[uint64]
-	root         *kvstore.TypedValue[IdentifierType]
-	mutex        sync.RWMutex
+	store         kvstore.KVStore
+	rawKeysStore  *kvstore.TypedStore[K, types.Empty]
+	treeStore     kvstore.KVStore
+	valuesStore   kvstore.KVStore
+	keyPathsStore kvstore.KVStore
+	tree          *smt.SMT
+	treeAdapter   *mapStoreAdapter
+	size          *kvstore.TypedValue[uint64]
+	root          *kvstore.TypedValue[IdentifierType]
+	version       *kvstore.TypedValue[uint64]
+	oldestVersion *kvstore.TypedValue[uint64]
+	versionRoots  *kvstore.TypedStore[uint64, IdentifierType]
+	versionSizes  *kvstore.TypedStore[uint64, uint64]
+	versionLog    *versionLog
+	options       *Options
+	valueHasher   *valueHasher
+	pathHasher    hash.Hash
+	mutex         sync.RWMutex
 
-	keyToBytes   kvstore.ObjectToBytes[K]
-	valueToBytes kvstore.ObjectToBytes[V]
-	bytesToValue kvstore.BytesToObject[V]
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType]
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType]
+	keyToBytes        kvstore.ObjectToBytes[K]
+	bytesToKey        kvstore.BytesToObject[K]
+	valueToBytes      kvstore.ObjectToBytes[V]
+	bytesToValue      kvstore.BytesToObject[V]
 }
 
-// NewAuthenticatedMap creates a new authenticated map.
+// NewAuthenticatedMap creates a new authenticated map and panics if it can not be opened.
 func newAuthenticatedMap[IdentifierType types.IdentifierType, K, V any](
 	store kvstore.KVStore,
 	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
@@ -42,25 +88,86 @@ func newAuthenticatedMap[IdentifierType types.IdentifierType, K, V any](
 	bytesToKey kvstore.BytesToObject[K],
 	valueToBytes kvstore.ObjectToBytes[V],
 	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
 ) *authenticatedMap[IdentifierType, K, V] {
+	return lo.PanicOnErr(openAuthenticatedMap(store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...))
+}
+
+// openAuthenticatedMap creates a new authenticated map and returns an error if it can not be opened.
+func openAuthenticatedMap[IdentifierType types.IdentifierType, K, V any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (*authenticatedMap[IdentifierType, K, V], error) {
 	newMap := &authenticatedMap[IdentifierType, K, V]{
-		rawKeysStore: kvstore.NewTypedStore(lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixRawKeysStorage})), keyToBytes, bytesToKey, types.Empty.Bytes, types.EmptyFromBytes),
-		size:         kvstore.NewTypedValue(store, []byte{prefixSizeKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes),
-		root:         kvstore.NewTypedValue(store, []byte{prefixRootKey}, identifierToBytes, bytesToIdentifier),
+		options: newOptions(opts...),
+
+		identifierToBytes: identifierToBytes,
+		bytesToIdentifier: bytesToIdentifier,
+		keyToBytes:        keyToBytes,
+		bytesToKey:        bytesToKey,
+		valueToBytes:      valueToBytes,
+		bytesToValue:      bytesToValue,
+	}
+	newMap.initStores(store)
+
+	if newMap.options.batchWorkerPool != nil && newMap.options.batchWorkerPool.WorkerCount() < 1 {
+		return nil, ierrors.Wrap(ErrInvalidBatchWorkerPool, "the batch worker pool has no workers")
+	}
+
+	if err := newMap.verifyHashers(store); err != nil {
+		return nil, err
+	}
+
+	if newMap.options.valueHasher != nil {
+		newMap.valueHasher = newValueHasher(newMap.options.valueHasher)
+	}
+
+	newMap.pathHasher = newMap.options.hasher()
+	if err := newMap.indexKeyPaths(); err != nil {
+		return nil, err
+	}
 
-		keyToBytes:   keyToBytes,
-		valueToBytes: valueToBytes,
-		bytesToValue: bytesToValue,
+	if err := newMap.migrateVersions(); err != nil {
+		return nil, err
 	}
 
-	mapStoreAdapter := newMapStoreAdapter(lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixTreeStorage})))
-	if root, err := newMap.root.Get(); err == nil {
-		newMap.tree = smt.ImportSparseMerkleTrie(mapStoreAdapter, sha256.New(), root[:], smt.WithValueHasher(nil))
-	} else {
-		newMap.tree = smt.NewSparseMerkleTrie(mapStoreAdapter, sha256.New(), smt.WithValueHasher(nil))
+	if err := newMap.loadTree(); err != nil {
+		return nil, err
 	}
 
-	return newMap
+	return newMap, nil
+}
+
+// initStores initializes the storage of the map (and the versionLog) on top of the given store.
+func (m *authenticatedMap[IdentifierType, K, V]) initStores(store kvstore.KVStore) {
+	m.store = store
+	m.rawKeysStore = kvstore.NewTypedStore(lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixRawKeysStorage})), m.keyToBytes, m.bytesToKey, types.Empty.Bytes, types.EmptyFromBytes)
+	m.size = kvstore.NewTypedValue(store, []byte{prefixSizeKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+	m.root = kvstore.NewTypedValue(store, []byte{prefixRootKey}, m.identifierToBytes, m.bytesToIdentifier)
+	m.treeStore = lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixTreeStorage}))
+	m.valuesStore = lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixValuesStorage}))
+	m.keyPathsStore = lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixKeyPathsStorage}))
+	m.version = kvstore.NewTypedValue(store, []byte{prefixVersionKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+	m.oldestVersion = kvstore.NewTypedValue(store, []byte{prefixOldestVersionKey}, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+	m.versionRoots = kvstore.NewTypedStore(lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixVersionRootsStorage})), versionToBytes, versionFromBytes, m.identifierToBytes, m.bytesToIdentifier)
+	m.versionSizes = kvstore.NewTypedStore(lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixVersionSizesStorage})), versionToBytes, versionFromBytes, typeutils.Uint64ToBytes, typeutils.Uint64FromBytes)
+	m.versionLog = newVersionLog(
+		m.treeStore,
+		lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixOrphansStorage})),
+		m.valuesStore,
+		lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixValueOrphansStorage})),
+		m.keyPathsStore,
+		lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixKeyPathOrphansStorage})),
+		lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixChangesStorage})),
+		lo.PanicOnErr(storedVersion(m.version)),
+		lo.PanicOnErr(storedVersion(m.oldestVersion)),
+	)
 }
 
 // WasRestoredFromStorage returns true if the map has been restored from storage.
@@ -92,20 +199,33 @@ func (m *authenticatedMap[IdentifierType, K, V]) Set(key K, value V) error {
 		return ierrors.Wrap(err, "failed to serialize key")
 	}
 
-	has, err := m.has(keyBytes)
+	previousLeafData, err := m.tree.Get(keyBytes)
 	if err != nil {
 		return ierrors.Wrap(err, "failed to check if key exists")
 	}
+	has := previousLeafData != nil
 
 	if err := m.tree.Update(keyBytes, valueBytes); err != nil {
 		return ierrors.Wrap(err, "failed to update tree")
 	}
 
+	if err := m.setHashedValue(keyBytes, previousLeafData, valueBytes); err != nil {
+		return err
+	}
+
 	if err := m.rawKeysStore.Set(key, types.Void); err != nil {
 		return ierrors.Wrap(err, "failed to set raw key")
 	}
 
 	if !has {
+		if err := m.versionLog.AddKey(keyBytes); err != nil {
+			return ierrors.Wrap(err, "failed to record added key")
+		}
+
+		if err := m.versionLog.SetKeyPath(m.keyPath(keyBytes), keyBytes); err != nil {
+			return ierrors.Wrap(err, "failed to set key path")
+		}
+
 		if err := m.addSize(1); err != nil {
 			return ierrors.Wrap(err, "failed to increase size")
 		}
@@ -127,16 +247,171 @@ func (m *authenticatedMap[IdentifierType, K, V]) Size() int {
 	return int(size)
 }
 
-// Commit persists the current state of the map to the storage.
+// Commit persists the current state of the map to the storage as a new version.
+//
+// The tree nodes and the version are written to the store in a single batch. No version is created if there are no
+// uncommitted changes. If the batch can not be written, the uncommitted changes are discarded from the tree and the
+// map has to be rolled back to its latest version before it is used again.
 func (m *authenticatedMap[IdentifierType, K, V]) Commit() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	if err := m.root.Set(IdentifierType(m.tree.Root())); err != nil {
+	if hasChanges, err := m.hasUncommittedChanges(); err != nil {
+		return err
+	} else if !hasChanges {
+		return nil
+	}
+
+	return m.commitStaged(func(stagingMap *authenticatedMap[IdentifierType, K, V]) (IdentifierType, error) {
+		// redirect the writes of the tree to the staging store
+		m.treeAdapter.underlying, m.treeAdapter.versionLog = stagingMap.treeStore, stagingMap.versionLog
+
+		if err := m.tree.Commit(); err != nil {
+			return IdentifierType{}, ierrors.Wrap(err, "failed to commit tree")
+		}
+
+		return IdentifierType(m.tree.Root()), nil
+	})
+}
+
+// ApplyBatch applies the given changes and commits them as a new version.
+//
+// The changes are hashed and the affected subtrees are rebuilt in parallel, before all modifications are written to
+// the store in a single batch. If the batch contains multiple changes of the same key, the last one wins. The batch is
+// rejected with ErrUncommittedChanges if the map has changes that were not committed yet and an empty batch does not
+// create a new version.
+//
+// ApplyBatch can be called from a task of the pool that was passed with WithBatchWorkerPool (the calling goroutine runs
+// the submitted tasks that no idle worker picked up).
+func (m *authenticatedMap[IdentifierType, K, V]) ApplyBatch(changes []BatchChange[K, V]) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if hasChanges, err := m.hasUncommittedChanges(); err != nil {
+		return err
+	} else if hasChanges {
+		return ErrUncommittedChanges
+	} else if len(changes) == 0 {
+		return nil
+	}
+
+	workerPool, releaseWorkerPool := batchWorkerPool(m.options)
+	defer releaseWorkerPool()
+
+	entries, err := m.prepareBatch(changes, workerPool)
+	if err != nil {
+		return err
+	}
+
+	treeBatch := newTreeBatch[K](m.options, newLocalTreeReader(m, false))
+	root, err := treeBatch.Run(m.tree.Root(), entries, workerPool)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to apply batch to tree")
+	}
+
+	return m.commitStaged(func(stagingMap *authenticatedMap[IdentifierType, K, V]) (IdentifierType, error) {
+		return IdentifierType(root), stagingMap.applyBatchEntries(entries, treeBatch)
+	})
+}
+
+// Version returns the version of the latest commit (0 if the map was never committed).
+func (m *authenticatedMap[IdentifierType, K, V]) Version() uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.versionLog.pendingVersion - 1
+}
+
+// ReadAt returns a read-only view of the map at the given committed version.
+// The view must not be used anymore once its version was pruned or rolled back.
+func (m *authenticatedMap[IdentifierType, K, V]) ReadAt(version uint64) (ReadOnlyMap[IdentifierType, K, V], error) {
+	return m.readAt(version)
+}
+
+// Rollback restores the map to the given committed version and discards all later versions and uncommitted changes.
+func (m *authenticatedMap[IdentifierType, K, V]) Rollback(version uint64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	root, size, err := m.versionState(version)
+	if err != nil {
+		return err
+	}
+
+	latestVersion := m.versionLog.pendingVersion - 1
+	if err = m.versionLog.Rollback(version, m.rawKeysStore.KVStore()); err != nil {
+		return ierrors.Wrapf(err, "failed to roll back to version %d", version)
+	}
+
+	for discardedVersion := version + 1; discardedVersion <= latestVersion; discardedVersion++ {
+		if err = m.deleteVersionState(discardedVersion); err != nil {
+			return err
+		}
+	}
+
+	if err = m.root.Set(root); err != nil {
 		return ierrors.Wrap(err, "failed to set root")
 	}
 
-	return m.tree.Commit()
+	if err = m.size.Set(size); err != nil {
+		return ierrors.Wrap(err, "failed to set size")
+	}
+
+	if err = m.version.Set(version); err != nil {
+		return ierrors.Wrap(err, "failed to set version")
+	}
+
+	return m.loadTree()
+}
+
+// Prune discards all versions older than the given version and deletes the tree nodes that are no longer needed.
+func (m *authenticatedMap[IdentifierType, K, V]) Prune(version uint64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, _, err := m.versionState(version); err != nil {
+		return err
+	}
+
+	previousOldestVersion := m.versionLog.oldestVersion
+	if err := m.versionLog.Prune(version); err != nil {
+		return ierrors.Wrapf(err, "failed to prune versions older than %d", version)
+	}
+
+	for prunedVersion := max(previousOldestVersion, 1); prunedVersion < version; prunedVersion++ {
+		if err := m.deleteVersionState(prunedVersion); err != nil {
+			return err
+		}
+	}
+
+	if err := m.oldestVersion.Set(version); err != nil {
+		return ierrors.Wrap(err, "failed to set oldest version")
+	}
+
+	return nil
+}
+
+// Diff streams the keys that differ between the given committed roots of the map.
+func (m *authenticatedMap[IdentifierType, K, V]) Diff(sourceRoot IdentifierType, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType, sourceValue V, targetValue V) error) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	localReader := newLocalTreeReader(m, false)
+
+	return m.diff(localReader, sourceRoot, localReader, targetRoot, consumerFunc)
+}
+
+// DiffRemote streams the keys that differ between the given committed root of the map and the root of a remote tree.
+func (m *authenticatedMap[IdentifierType, K, V]) DiffRemote(sourceRoot IdentifierType, remote TreeReader, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType, sourceValue V, targetValue V) error) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.diff(newLocalTreeReader(m, false), sourceRoot, remote, targetRoot, consumerFunc)
+}
+
+// TreeReader returns a TreeReader for the committed nodes of the map (e.g. to serve the requests of remote peers).
+func (m *authenticatedMap[IdentifierType, K, V]) TreeReader() TreeReader {
+	return newLocalTreeReader(m, true)
 }
 
 // Delete removes the key from the map.
@@ -149,10 +424,11 @@ func (m *authenticatedMap[IdentifierType, K, V]) Delete(key K) (deleted bool, er
 		return false, ierrors.Wrap(err, "failed to serialize key")
 	}
 
-	has, err := m.has(keyBytes)
+	leafData, err := m.tree.Get(keyBytes)
 	if err != nil {
 		return false, ierrors.Wrap(err, "failed to check if key exists")
 	}
+	has := leafData != nil
 
 	if !has {
 		return false, nil
@@ -162,10 +438,22 @@ func (m *authenticatedMap[IdentifierType, K, V]) Delete(key K) (deleted bool, er
 		return false, ierrors.Wrap(err, "failed to delete from tree")
 	}
 
+	if err := m.deleteHashedValue(keyBytes, leafData); err != nil {
+		return false, err
+	}
+
 	if err := m.rawKeysStore.Delete(key); err != nil {
 		return false, ierrors.Wrap(err, "failed to delete from raw keys store")
 	}
 
+	if err := m.versionLog.RemoveKey(keyBytes); err != nil {
+		return false, ierrors.Wrap(err, "failed to record removed key")
+	}
+
+	if err := m.versionLog.DeleteKeyPath(m.keyPath(keyBytes)); err != nil {
+		return false, ierrors.Wrap(err, "failed to delete key path")
+	}
+
 	if has {
 		if err := m.addSize(-1); err != nil {
 			return false, ierrors.Wrap(err, "failed to decrease size")
@@ -198,12 +486,17 @@ func (m *authenticatedMap[IdentifierType, K, V]) Get(key K) (value V, exists boo
 		return value, false, ierrors.Wrap(err, "failed to serialize key")
 	}
 
-	valueBytes, err := m.tree.Get(keyBytes)
+	leafData, err := m.tree.Get(keyBytes)
 	if err != nil {
 		return value, false, ierrors.Wrap(err, "failed to get from tree")
 	}
 
-	if valueBytes == nil {
+	if leafData == nil {
+		return value, false, err
+	}
+
+	valueBytes, err := m.resolveValue(keyBytes, leafData)
+	if err != nil {
 		return value, false, err
 	}
 
@@ -221,25 +514,42 @@ func (m *authenticatedMap[IdentifierType, K, V]) Get(key K) (value V, exists boo
 
 // Stream streams all the keys and values.
 func (m *authenticatedMap[IdentifierType, K, V]) Stream(callback func(key K, value V) error) error {
+	_, err := m.StreamRange(callback)
+
+	return err
+}
+
+// StreamRange streams the keys and values in the range that is defined by the given options and returns the cursor
+// of the next element (nil if there are no more elements).
+func (m *authenticatedMap[IdentifierType, K, V]) StreamRange(callback func(key K, value V) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	streamOptions := newStreamOptions(opts...)
+	streamedElements := 0
+
 	var innerErr error
-	if iterationErr := m.rawKeysStore.IterateKeys([]byte{}, func(key K) bool {
-		keyBytes, err := m.keyToBytes(key)
-		if err != nil {
-			innerErr = ierrors.Wrapf(err, "failed to serialize key %s", keyBytes)
+	if iterationErr := streamOptions.iterateKeys(m.rawKeysStore.KVStore(), func(keyBytes kvstore.Key) bool {
+		if streamOptions.limit > 0 && streamedElements == streamOptions.limit {
+			nextCursor = keyBytes
 
 			return false
 		}
 
-		valueBytes, valueErr := m.tree.Get(keyBytes)
+		leafData, valueErr := m.tree.Get(keyBytes)
 		if valueErr != nil {
 			innerErr = ierrors.Wrapf(valueErr, "failed to get value for key %s", keyBytes)
 
 			return false
 		}
 
+		valueBytes, valueErr := m.resolveValue(keyBytes, leafData)
+		if valueErr != nil {
+			innerErr = ierrors.Wrapf(valueErr, "failed to resolve value for key %s", keyBytes)
+
+			return false
+		}
+
 		value, _, valueErr := m.bytesToValue(valueBytes)
 		if valueErr != nil {
 			innerErr = ierrors.Wrapf(valueErr, "failed to deserialize value %s", valueBytes)
@@ -247,18 +557,30 @@ func (m *authenticatedMap[IdentifierType, K, V]) Stream(callback func(key K, val
 			return false
 		}
 
+		key, _, keyErr := m.bytesToKey(keyBytes)
+		if keyErr != nil {
+			innerErr = ierrors.Wrapf(keyErr, "failed to deserialize key %s", keyBytes)
+
+			return false
+		}
+
 		if callbackErr := callback(key, value); callbackErr != nil {
 			innerErr = ierrors.Wrapf(callbackErr, "failed to execute callback for key %s", keyBytes)
 
 			return false
 		}
+		streamedElements++
 
 		return true
 	}); iterationErr != nil {
-		return ierrors.Wrap(iterationErr, "failed to iterate over raw keys")
+		return nil, ierrors.Wrap(iterationErr, "failed to iterate over raw keys")
 	}
 
-	return innerErr
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	return nextCursor, nil
 }
 
 // has returns true if the key is in the map.
@@ -271,6 +593,529 @@ func (m *authenticatedMap[IdentifierType, K, V]) has(keyBytes []byte) (has bool,
 	return value != nil, nil
 }
 
+// diff streams the keys that differ between the given roots of the given trees.
+func (m *authenticatedMap[IdentifierType, K, V]) diff(source TreeReader, sourceRoot IdentifierType, target TreeReader, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType, sourceValue V, targetValue V) error) error {
+	var treeDiff *treeDiff
+	resolveLeaf := func(reader TreeReader, leaf *diffLeaf) (key K, value V, err error) {
+		if leaf == nil {
+			return key, value, nil
+		}
+
+		keyBytes, valueBytes, err := reader.Leaf(leaf.path, leaf.leafData)
+		if err != nil {
+			return key, value, ierrors.Wrapf(err, "failed to retrieve leaf %x", leaf.path)
+		} else if err = treeDiff.VerifyLeaf(leaf, keyBytes, valueBytes); err != nil {
+			return key, value, err
+		}
+
+		if key, _, err = m.bytesToKey(keyBytes); err != nil {
+			return key, value, ierrors.Wrapf(err, "failed to deserialize key %x", keyBytes)
+		} else if value, _, err = m.bytesToValue(valueBytes); err != nil {
+			return key, value, ierrors.Wrapf(err, "failed to deserialize value %x", valueBytes)
+		}
+
+		return key, value, nil
+	}
+
+	treeDiff = newTreeDiff(m.options, source, target, func(diffType DiffType, sourceLeaf *diffLeaf, targetLeaf *diffLeaf) error {
+		sourceKey, sourceValue, err := resolveLeaf(source, sourceLeaf)
+		if err != nil {
+			return ierrors.Wrap(err, "failed to resolve source leaf")
+		}
+
+		targetKey, targetValue, err := resolveLeaf(target, targetLeaf)
+		if err != nil {
+			return ierrors.Wrap(err, "failed to resolve target leaf")
+		}
+
+		return consumerFunc(lo.Cond(targetLeaf != nil, targetKey, sourceKey), diffType, sourceValue, targetValue)
+	})
+
+	return treeDiff.Run(sourceRoot[:], targetRoot[:])
+}
+
+// prepareBatch serializes and hashes the given changes in parallel and returns them sorted by their paths (only the
+// last change of every key is kept).
+func (m *authenticatedMap[IdentifierType, K, V]) prepareBatch(changes []BatchChange[K, V], workerPool *workerpool.WorkerPool) ([]*batchEntry[K], error) {
+	entries := make([]*batchEntry[K], len(changes))
+	errs := make([]error, workerPool.WorkerCount())
+	chunkSize := (len(changes) + len(errs) - 1) / len(errs)
+
+	tasks := make([]func(), 0, len(errs))
+	for worker := range errs {
+		start, end := min(worker*chunkSize, len(changes)), min((worker+1)*chunkSize, len(changes))
+		if start == end {
+			break
+		}
+
+		worker := worker
+		tasks = append(tasks, func() {
+			errs[worker] = m.prepareBatchEntries(changes[start:end], entries[start:end])
+		})
+	}
+	runParallel(workerPool, tasks)
+
+	if err := ierrors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].path, entries[j].path) < 0
+	})
+
+	dedupedEntries := make([]*batchEntry[K], 0, len(entries))
+	for i, entry := range entries {
+		if i+1 < len(entries) && bytes.Equal(entry.path, entries[i+1].path) {
+			continue
+		}
+
+		dedupedEntries = append(dedupedEntries, entry)
+	}
+
+	return dedupedEntries, nil
+}
+
+// prepareBatchEntries serializes and hashes the given changes into the given entries (using its own hashers, so that
+// it can be called in parallel).
+func (m *authenticatedMap[IdentifierType, K, V]) prepareBatchEntries(changes []BatchChange[K, V], entries []*batchEntry[K]) error {
+	pathHasher := m.options.hasher()
+
+	var valueHasher *valueHasher
+	if m.options.valueHasher != nil {
+		valueHasher = newValueHasher(m.options.valueHasher)
+	}
+
+	for i, change := range changes {
+		keyBytes, err := m.keyToBytes(change.Key)
+		if err != nil {
+			return ierrors.Wrap(err, "failed to serialize key")
+		}
+
+		pathHasher.Reset()
+		_, _ = pathHasher.Write(keyBytes)
+
+		entries[i] = &batchEntry[K]{
+			key:        change.Key,
+			keyBytes:   keyBytes,
+			path:       pathHasher.Sum(nil),
+			isDeletion: change.Delete,
+		}
+
+		if change.Delete {
+			continue
+		}
+
+		if entries[i].valueBytes, err = m.valueToBytes(change.Value); err != nil {
+			return ierrors.Wrapf(err, "failed to serialize value of key %x", keyBytes)
+		}
+
+		entries[i].leafData = entries[i].valueBytes
+		if valueHasher != nil {
+			entries[i].leafData = valueHasher.HashValue(entries[i].valueBytes)
+		}
+	}
+
+	return nil
+}
+
+// applyBatchEntries records the nodes of the given treeBatch and the changes of the given entries.
+func (m *authenticatedMap[IdentifierType, K, V]) applyBatchEntries(entries []*batchEntry[K], treeBatch *treeBatch[K]) error {
+	for _, orphanedNode := range treeBatch.orphanedNodes {
+		if err := m.versionLog.DeleteNode(orphanedNode); err != nil {
+			return ierrors.Wrapf(err, "failed to delete node %x", orphanedNode)
+		}
+	}
+
+	for _, createdNode := range treeBatch.createdNodes {
+		if err := m.versionLog.SetNode(createdNode.hash, createdNode.preimage); err != nil {
+			return ierrors.Wrapf(err, "failed to set node %x", createdNode.hash)
+		}
+	}
+
+	for _, entry := range entries {
+		existed := entry.previousLeafData != nil
+
+		if entry.isDeletion {
+			if !existed {
+				continue
+			}
+
+			if err := m.deleteHashedValue(entry.keyBytes, entry.previousLeafData); err != nil {
+				return err
+			}
+
+			if err := m.rawKeysStore.Delete(entry.key); err != nil {
+				return ierrors.Wrap(err, "failed to delete from raw keys store")
+			}
+
+			if err := m.versionLog.RemoveKey(entry.keyBytes); err != nil {
+				return ierrors.Wrap(err, "failed to record removed key")
+			}
+
+			if err := m.versionLog.DeleteKeyPath(entry.path); err != nil {
+				return ierrors.Wrap(err, "failed to delete key path")
+			}
+
+			if err := m.addSize(-1); err != nil {
+				return ierrors.Wrap(err, "failed to decrease size")
+			}
+
+			continue
+		}
+
+		if err := m.setHashedValue(entry.keyBytes, entry.previousLeafData, entry.valueBytes); err != nil {
+			return err
+		}
+
+		if err := m.rawKeysStore.Set(entry.key, types.Void); err != nil {
+			return ierrors.Wrap(err, "failed to set raw key")
+		}
+
+		if existed {
+			continue
+		}
+
+		if err := m.versionLog.AddKey(entry.keyBytes); err != nil {
+			return ierrors.Wrap(err, "failed to record added key")
+		}
+
+		if err := m.versionLog.SetKeyPath(entry.path, entry.keyBytes); err != nil {
+			return ierrors.Wrap(err, "failed to set key path")
+		}
+
+		if err := m.addSize(1); err != nil {
+			return ierrors.Wrap(err, "failed to increase size")
+		}
+	}
+
+	return nil
+}
+
+// commitStaged stages the modifications of the given function in a shadow map and writes them to the store (together
+// with the returned root as the state of the new version) in a single batch.
+func (m *authenticatedMap[IdentifierType, K, V]) commitStaged(stageModifications func(stagingMap *authenticatedMap[IdentifierType, K, V]) (IdentifierType, error)) error {
+	stagingStore := newStagedStore(m.store)
+	stagingMap := m.stagingMap(stagingStore)
+
+	version := stagingMap.versionLog.pendingVersion
+	err := stageVersion(stagingMap, version, stageModifications)
+	if err == nil {
+		if err = stagingStore.WriteBatch(); err != nil {
+			err = ierrors.Wrap(err, "failed to write batch")
+		}
+	}
+
+	// the tree is reloaded in any case, as it might reference the discarded staging store
+	m.initStores(m.store)
+	if loadErr := m.loadTree(); loadErr != nil {
+		return ierrors.Join(err, loadErr)
+	}
+
+	return err
+}
+
+// stagingMap returns a shadow map on top of the given staged store that shares the configuration of the map.
+func (m *authenticatedMap[IdentifierType, K, V]) stagingMap(stagingStore *stagedStore) *authenticatedMap[IdentifierType, K, V] {
+	stagingMap := &authenticatedMap[IdentifierType, K, V]{
+		options:           m.options,
+		valueHasher:       m.valueHasher,
+		pathHasher:        m.pathHasher,
+		identifierToBytes: m.identifierToBytes,
+		bytesToIdentifier: m.bytesToIdentifier,
+		keyToBytes:        m.keyToBytes,
+		bytesToKey:        m.bytesToKey,
+		valueToBytes:      m.valueToBytes,
+		bytesToValue:      m.bytesToValue,
+	}
+	stagingMap.initStores(stagingStore)
+
+	return stagingMap
+}
+
+// stageVersion stages the modifications of the given function and the resulting state of the given version.
+func stageVersion[IdentifierType types.IdentifierType, K, V any](stagingMap *authenticatedMap[IdentifierType, K, V], version uint64, stageModifications func(stagingMap *authenticatedMap[IdentifierType, K, V]) (IdentifierType, error)) error {
+	root, err := stageModifications(stagingMap)
+	if err != nil {
+		return err
+	}
+
+	return stagingMap.storeVersion(version, root)
+}
+
+// hasUncommittedChanges returns true if the map was modified since the latest commit.
+func (m *authenticatedMap[IdentifierType, K, V]) hasUncommittedChanges() (bool, error) {
+	committedRoot, err := m.root.Get()
+	if err != nil && !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+		return false, ierrors.Wrap(err, "failed to get root")
+	}
+
+	if IdentifierType(m.tree.Root()) != committedRoot {
+		return true, nil
+	}
+
+	hasChanges, err := m.versionLog.HasPendingChanges()
+	if err != nil {
+		return false, ierrors.Wrap(err, "failed to check for pending changes")
+	}
+
+	return hasChanges, nil
+}
+
+// migrateVersions records the state of a map that was persisted before versions were introduced as version 1.
+func (m *authenticatedMap[IdentifierType, K, V]) migrateVersions() error {
+	if m.versionLog.pendingVersion != 1 {
+		return nil
+	}
+
+	root, err := m.root.Get()
+	if err != nil {
+		if ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil
+		}
+
+		return ierrors.Wrap(err, "failed to get root")
+	}
+
+	if err = m.commitStaged(func(*authenticatedMap[IdentifierType, K, V]) (IdentifierType, error) {
+		return root, nil
+	}); err != nil {
+		return ierrors.Wrap(err, "failed to migrate versions")
+	}
+
+	return nil
+}
+
+// loadTree (re)creates the tree from the latest committed root in the storage.
+func (m *authenticatedMap[IdentifierType, K, V]) loadTree() error {
+	m.treeAdapter = newMapStoreAdapter(m.treeStore, m.versionLog)
+
+	root, err := m.root.Get()
+	if err != nil {
+		if !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return ierrors.Wrap(err, "failed to get root")
+		}
+
+		m.tree = smt.NewSparseMerkleTrie(m.treeAdapter, m.options.hasher(), m.treeOptions()...)
+
+		return nil
+	}
+
+	m.tree = smt.ImportSparseMerkleTrie(m.treeAdapter, m.options.hasher(), root[:], m.treeOptions()...)
+
+	return nil
+}
+
+// readAt returns a read-only view of the map at the given committed version.
+func (m *authenticatedMap[IdentifierType, K, V]) readAt(version uint64) (*readOnlyMap[IdentifierType, K, V], error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	root, size, err := m.versionState(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadOnlyMap(m, version, root, size), nil
+}
+
+// storeVersion stores the given root and the current size as the state of the given version.
+func (m *authenticatedMap[IdentifierType, K, V]) storeVersion(version uint64, root IdentifierType) error {
+	if err := m.versionRoots.Set(version, root); err != nil {
+		return ierrors.Wrapf(err, "failed to set root of version %d", version)
+	}
+
+	size, err := m.size.Get()
+	if err != nil && !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+		return ierrors.Wrap(err, "failed to get size")
+	}
+
+	if err = m.versionSizes.Set(version, size); err != nil {
+		return ierrors.Wrapf(err, "failed to set size of version %d", version)
+	}
+
+	if err = m.root.Set(root); err != nil {
+		return ierrors.Wrap(err, "failed to set root")
+	}
+
+	if err = m.version.Set(version); err != nil {
+		return ierrors.Wrap(err, "failed to set version")
+	}
+
+	return nil
+}
+
+// versionState returns the root and size of the given retained version.
+func (m *authenticatedMap[IdentifierType, K, V]) versionState(version uint64) (root IdentifierType, size uint64, err error) {
+	if version == 0 || version < m.versionLog.oldestVersion || version >= m.versionLog.pendingVersion {
+		return root, 0, ierrors.Wrapf(ErrVersionNotFound, "version %d is not retained", version)
+	}
+
+	if root, err = m.versionRoots.Get(version); err != nil {
+		if ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return root, 0, ierrors.Wrapf(ErrVersionNotFound, "version %d has no root", version)
+		}
+
+		return root, 0, ierrors.Wrapf(err, "failed to get root of version %d", version)
+	}
+
+	if size, err = m.versionSizes.Get(version); err != nil {
+		return root, 0, ierrors.Wrapf(err, "failed to get size of version %d", version)
+	}
+
+	return root, size, nil
+}
+
+// deleteVersionState deletes the root and size of the given version.
+func (m *authenticatedMap[IdentifierType, K, V]) deleteVersionState(version uint64) error {
+	if err := m.versionRoots.Delete(version); err != nil {
+		return ierrors.Wrapf(err, "failed to delete root of version %d", version)
+	}
+
+	if err := m.versionSizes.Delete(version); err != nil {
+		return ierrors.Wrapf(err, "failed to delete size of version %d", version)
+	}
+
+	return nil
+}
+
+// importTree creates a tree from the nodes in the storage using the given root (a nil versionLog makes it read-only).
+func (m *authenticatedMap[IdentifierType, K, V]) importTree(root IdentifierType, versionLog *versionLog) *smt.SMT {
+	return smt.ImportSparseMerkleTrie(newMapStoreAdapter(m.treeStore, versionLog), m.options.hasher(), root[:], m.treeOptions()...)
+}
+
+// treeOptions returns the options of the sparse merkle tree.
+func (m *authenticatedMap[IdentifierType, K, V]) treeOptions() []smt.Option {
+	if m.options.valueHasher == nil {
+		return []smt.Option{smt.WithValueHasher(nil)}
+	}
+
+	return []smt.Option{smt.WithValueHasher(newValueHasher(m.options.valueHasher))}
+}
+
+// verifyHashers makes sure that the tree is opened with the hash functions that it was created with.
+func (m *authenticatedMap[IdentifierType, K, V]) verifyHashers(store kvstore.KVStore) error {
+	if hasherSize, identifierSize := m.options.hasher().Size(), len(IdentifierType{}); hasherSize != identifierSize {
+		return ierrors.Wrapf(ErrInvalidHasher, "hasher produces %d bytes instead of %d bytes", hasherSize, identifierSize)
+	}
+
+	fingerprint := m.options.fingerprint()
+
+	storedFingerprint, err := store.Get([]byte{prefixHashersKey})
+	if err != nil {
+		if !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return ierrors.Wrap(err, "failed to get hashers")
+		}
+
+		// trees that were created before the hashers were persisted always used the default hashers
+		if m.WasRestoredFromStorage() {
+			storedFingerprint = newOptions().fingerprint()
+		} else {
+			storedFingerprint = fingerprint
+		}
+
+		if err = store.Set([]byte{prefixHashersKey}, storedFingerprint); err != nil {
+			return ierrors.Wrap(err, "failed to set hashers")
+		}
+	}
+
+	if !bytes.Equal(fingerprint, storedFingerprint) {
+		return ierrors.Wrap(ErrHasherMismatch, "the tree was created with different hashers")
+	}
+
+	return nil
+}
+
+// resolveValue returns the value bytes for the given leaf data of the tree.
+func (m *authenticatedMap[IdentifierType, K, V]) resolveValue(keyBytes []byte, leafData []byte) ([]byte, error) {
+	if m.valueHasher == nil {
+		return leafData, nil
+	}
+
+	valueBytes, err := m.valuesStore.Get(valueIdentifier(keyBytes, leafData))
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to get hashed value")
+	}
+
+	return valueBytes, nil
+}
+
+// setHashedValue stores the hashed value of the given key and orphans the previous one.
+func (m *authenticatedMap[IdentifierType, K, V]) setHashedValue(keyBytes []byte, previousLeafData []byte, valueBytes []byte) error {
+	if m.valueHasher == nil {
+		return nil
+	}
+
+	leafData := m.valueHasher.HashValue(valueBytes)
+	if bytes.Equal(previousLeafData, leafData) {
+		return nil
+	}
+
+	if err := m.deleteHashedValue(keyBytes, previousLeafData); err != nil {
+		return err
+	}
+
+	if err := m.versionLog.SetValue(valueIdentifier(keyBytes, leafData), valueBytes); err != nil {
+		return ierrors.Wrap(err, "failed to set hashed value")
+	}
+
+	return nil
+}
+
+// deleteHashedValue orphans the hashed value of the given key.
+func (m *authenticatedMap[IdentifierType, K, V]) deleteHashedValue(keyBytes []byte, leafData []byte) error {
+	if m.valueHasher == nil || leafData == nil {
+		return nil
+	}
+
+	if err := m.versionLog.DeleteValue(valueIdentifier(keyBytes, leafData)); err != nil {
+		return ierrors.Wrap(err, "failed to delete hashed value")
+	}
+
+	return nil
+}
+
+// keyPath returns the path of the given key in the tree.
+func (m *authenticatedMap[IdentifierType, K, V]) keyPath(keyBytes []byte) []byte {
+	m.pathHasher.Reset()
+	_, _ = m.pathHasher.Write(keyBytes)
+
+	return m.pathHasher.Sum(nil)
+}
+
+// indexKeyPaths creates the key paths of maps that were created before the key paths were stored.
+func (m *authenticatedMap[IdentifierType, K, V]) indexKeyPaths() error {
+	if !m.WasRestoredFromStorage() {
+		return nil
+	}
+
+	isIndexed := false
+	if err := m.keyPathsStore.IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+		isIndexed = true
+
+		return false
+	}); err != nil {
+		return ierrors.Wrap(err, "failed to check key paths")
+	} else if isIndexed {
+		return nil
+	}
+
+	var innerErr error
+	if err := m.rawKeysStore.KVStore().IterateKeys(kvstore.EmptyPrefix, func(keyBytes kvstore.Key) bool {
+		innerErr = m.keyPathsStore.Set(m.keyPath(keyBytes), keyBytes)
+
+		return innerErr == nil
+	}); err != nil {
+		return ierrors.Wrap(err, "failed to iterate over raw keys")
+	}
+
+	return innerErr
+}
+
+// valueIdentifier returns the identifier of a hashed value.
+func valueIdentifier(keyBytes []byte, valueHash []byte) []byte {
+	return byteutils.ConcatBytes(keyBytes, valueHash)
+}
+
 func (m *authenticatedMap[IdentifierType, K, V]) addSize(delta int) error {
 	size, err := m.size.Get()
 	if err != nil && !ierrors.Is(err, kvstore.ErrKeyNotFound) {
@@ -9,15 +9,22 @@ import (
 
 var _ kvstore.MapStore = &mapStoreAdapter{}
 
+// ErrReadOnly is returned when trying to modify a read-only view of a tree.
+var ErrReadOnly = ierrors.New("tree is read-only")
+
 // mapStoreAdapter is a wrapper around a hive KVStore that implements the MapStore interface
 // from pokt-network/smt/kvstore.
 type mapStoreAdapter struct {
 	underlying hivekvstore.KVStore
+
+	// versionLog records the modifications of the tree (nil for read-only views).
+	versionLog *versionLog
 }
 
-func newMapStoreAdapter(store hivekvstore.KVStore) *mapStoreAdapter {
+func newMapStoreAdapter(store hivekvstore.KVStore, versionLog *versionLog) *mapStoreAdapter {
 	return &mapStoreAdapter{
 		underlying: store,
+		versionLog: versionLog,
 	}
 }
 
@@ -28,12 +35,20 @@ func (k *mapStoreAdapter) Get(key []byte) ([]byte, error) {
 
 // Set sets/updates the value for a given key.
 func (k *mapStoreAdapter) Set(key, value []byte) error {
-	return k.underlying.Set(key, value)
+	if k.versionLog == nil {
+		return ErrReadOnly
+	}
+
+	return k.versionLog.SetNode(key, value)
 }
 
-// Delete removes a key.
+// Delete removes a key (the underlying node is only deleted once no retained version needs it anymore).
 func (k *mapStoreAdapter) Delete(key []byte) error {
-	return k.underlying.Delete(key)
+	if k.versionLog == nil {
+		return ErrReadOnly
+	}
+
+	return k.versionLog.DeleteNode(key)
 }
 
 // Len returns the number of key-value pairs in the store.
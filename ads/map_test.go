@@ -1,13 +1,17 @@
 package ads
 
 import (
+	"crypto/sha512"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
 	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
 )
 
@@ -148,6 +152,458 @@ func TestStreamMap(t *testing.T) {
 	}
 }
 
+func TestMapVersions(t *testing.T) {
+	store := mapdb.NewMapDB()
+	newTestMap := func() *authenticatedMap[[32]byte, testKey, testValue] {
+		return newAuthenticatedMap[[32]byte, testKey, testValue](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+		)
+	}
+	streamToMap := func(stream func(func(key testKey, value testValue) error) error) map[testKey]string {
+		result := make(map[testKey]string)
+		require.NoError(t, stream(func(key testKey, value testValue) error {
+			result[key] = string(value)
+
+			return nil
+		}))
+
+		return result
+	}
+	treeNodes := func() (count int) {
+		require.NoError(t, lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixTreeStorage})).IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+			count++
+
+			return true
+		}))
+
+		return count
+	}
+
+	newMap := newTestMap()
+	require.EqualValues(t, 0, newMap.Version())
+
+	// version 1: a=1, b=1
+	require.NoError(t, newMap.Set(testKey{'a'}, testValueFromString("1")))
+	require.NoError(t, newMap.Set(testKey{'b'}, testValueFromString("1")))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 1, newMap.Version())
+	rootVersion1 := newMap.Root()
+	nodesVersion1 := treeNodes()
+
+	// version 2: a=2, b deleted, c=2
+	require.NoError(t, newMap.Set(testKey{'a'}, testValueFromString("2")))
+	require.True(t, lo.PanicOnErr(newMap.Delete(testKey{'b'})))
+	require.NoError(t, newMap.Set(testKey{'c'}, testValueFromString("2")))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 2, newMap.Version())
+	rootVersion2 := newMap.Root()
+
+	// version 3: a=1, b=1 (same content as version 1), c deleted
+	require.NoError(t, newMap.Set(testKey{'a'}, testValueFromString("1")))
+	require.NoError(t, newMap.Set(testKey{'b'}, testValueFromString("1")))
+	require.True(t, lo.PanicOnErr(newMap.Delete(testKey{'c'})))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, rootVersion1, newMap.Root())
+
+	// historical versions are readable
+	version1 := lo.PanicOnErr(newMap.ReadAt(1))
+	require.EqualValues(t, rootVersion1, version1.Root())
+	require.Equal(t, 2, version1.Size())
+	require.Equal(t, map[testKey]string{{'a'}: "1", {'b'}: "1"}, streamToMap(version1.Stream))
+
+	version2 := lo.PanicOnErr(newMap.ReadAt(2))
+	require.EqualValues(t, rootVersion2, version2.Root())
+	require.Equal(t, 2, version2.Size())
+	require.Equal(t, map[testKey]string{{'a'}: "2", {'c'}: "2"}, streamToMap(version2.Stream))
+	require.False(t, lo.PanicOnErr(version2.Has(testKey{'b'})))
+	value, exists, err := version2.Get(testKey{'a'})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "2", string(value))
+
+	_, err = newMap.ReadAt(4)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+
+	// roll back to version 2 (also discarding uncommitted changes)
+	require.NoError(t, newMap.Set(testKey{'d'}, testValueFromString("uncommitted")))
+	require.NoError(t, newMap.Rollback(2))
+	require.EqualValues(t, 2, newMap.Version())
+	require.EqualValues(t, rootVersion2, newMap.Root())
+	require.Equal(t, 2, newMap.Size())
+	require.Equal(t, map[testKey]string{{'a'}: "2", {'c'}: "2"}, streamToMap(newMap.Stream))
+
+	_, err = newMap.ReadAt(3)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+
+	// the rolled back state survives a restart
+	newMap = newTestMap()
+	require.EqualValues(t, 2, newMap.Version())
+	require.EqualValues(t, rootVersion2, newMap.Root())
+	require.Equal(t, map[testKey]string{{'a'}: "2", {'c'}: "2"}, streamToMap(newMap.Stream))
+
+	// version 3: c deleted
+	require.True(t, lo.PanicOnErr(newMap.Delete(testKey{'c'})))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 3, newMap.Version())
+
+	// pruning removes the nodes that are only needed by version 1 and 2
+	nodesBeforePruning := treeNodes()
+	require.NoError(t, newMap.Prune(2))
+	require.Less(t, treeNodes(), nodesBeforePruning)
+	_, err = newMap.ReadAt(1)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+	require.Equal(t, map[testKey]string{{'a'}: "2", {'c'}: "2"}, streamToMap(lo.PanicOnErr(newMap.ReadAt(2)).Stream))
+
+	require.NoError(t, newMap.Prune(3))
+	require.Equal(t, map[testKey]string{{'a'}: "2"}, streamToMap(lo.PanicOnErr(newMap.ReadAt(3)).Stream))
+	require.Equal(t, 1, treeNodes())
+	require.ErrorIs(t, newMap.Rollback(2), ErrVersionNotFound)
+
+	// rolling back to the pruned version is not possible, but rolling back to the oldest retained one is
+	require.NoError(t, newMap.Set(testKey{'a'}, testValueFromString("1")))
+	require.NoError(t, newMap.Set(testKey{'b'}, testValueFromString("1")))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, rootVersion1, newMap.Root())
+	require.Equal(t, nodesVersion1+1, treeNodes())
+	require.NoError(t, newMap.Rollback(3))
+	require.Equal(t, 1, treeNodes())
+	require.Equal(t, map[testKey]string{{'a'}: "2"}, streamToMap(newMap.Stream))
+}
+
+func TestMapVersionsMigration(t *testing.T) {
+	store := mapdb.NewMapDB()
+	newTestMap := func() *authenticatedMap[[32]byte, testKey, testValue] {
+		return newAuthenticatedMap[[32]byte, testKey, testValue](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+		)
+	}
+
+	newMap := newTestMap()
+	require.NoError(t, newMap.Set(testKey{'a'}, testValueFromString("1")))
+	require.NoError(t, newMap.Set(testKey{'b'}, testValueFromString("1")))
+	require.NoError(t, newMap.Commit())
+	legacyRoot := newMap.Root()
+
+	// committing without changes does not create a new version
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 1, newMap.Version())
+
+	// remove the version information to get the storage layout of maps that were persisted before versions existed
+	for _, prefix := range []byte{prefixVersionKey, prefixOldestVersionKey, prefixVersionRootsStorage, prefixVersionSizesStorage, prefixOrphansStorage, prefixChangesStorage} {
+		require.NoError(t, store.DeletePrefix([]byte{prefix}))
+	}
+
+	// the persisted state of the legacy map becomes version 1
+	newMap = newTestMap()
+	require.EqualValues(t, 1, newMap.Version())
+	require.EqualValues(t, legacyRoot, newMap.Root())
+	require.Equal(t, 2, newMap.Size())
+
+	version1 := lo.PanicOnErr(newMap.ReadAt(1))
+	require.EqualValues(t, legacyRoot, version1.Root())
+	require.Equal(t, 2, version1.Size())
+	value, exists, err := version1.Get(testKey{'a'})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "1", string(value))
+
+	// the migrated map can be modified and rolled back to the migrated version
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 1, newMap.Version())
+	require.NoError(t, newMap.Set(testKey{'c'}, testValueFromString("2")))
+	require.NoError(t, newMap.Commit())
+	require.EqualValues(t, 2, newMap.Version())
+	require.Equal(t, 3, newMap.Size())
+
+	require.NoError(t, newMap.Rollback(1))
+	require.EqualValues(t, legacyRoot, newMap.Root())
+	require.Equal(t, 2, newMap.Size())
+	require.False(t, lo.PanicOnErr(newMap.Has(testKey{'c'})))
+
+	// reopening a migrated map does not migrate it again
+	newMap = newTestMap()
+	require.EqualValues(t, 1, newMap.Version())
+	require.EqualValues(t, legacyRoot, newMap.Root())
+}
+
+func TestMapHashers(t *testing.T) {
+	newTestMap := func(store kvstore.KVStore, opts ...options.Option[Options]) *authenticatedMap[[32]byte, testKey, testValue] {
+		return newAuthenticatedMap[[32]byte, testKey, testValue](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			opts...,
+		)
+	}
+
+	defaultStore := mapdb.NewMapDB()
+	defaultMap := newTestMap(defaultStore)
+	require.NoError(t, defaultMap.Set(testKey{'a'}, testValueFromString("a")))
+	require.NoError(t, defaultMap.Commit())
+
+	customStore := mapdb.NewMapDB()
+	customMap := newTestMap(customStore, WithHasher(sha512.New512_256), WithValueHasher(sha512.New512_256))
+	require.NoError(t, customMap.Set(testKey{'a'}, testValueFromString("a")))
+	require.NoError(t, customMap.Commit())
+
+	// different hashers produce different roots
+	require.NotEqual(t, defaultMap.Root(), customMap.Root())
+
+	// values can be retrieved even though only their hashes are stored in the tree
+	value, exists, err := customMap.Get(testKey{'a'})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "a", string(value))
+
+	require.NoError(t, customMap.Set(testKey{'a'}, testValueFromString("b")))
+	require.NoError(t, customMap.Commit())
+	value, exists, err = lo.PanicOnErr(customMap.ReadAt(1)).Get(testKey{'a'})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "a", string(value))
+
+	// reopening with the same hashers works
+	reopenedMap := newTestMap(customStore, WithHasher(sha512.New512_256), WithValueHasher(sha512.New512_256))
+	require.Equal(t, customMap.Root(), reopenedMap.Root())
+	value, exists, err = reopenedMap.Get(testKey{'a'})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "b", string(value))
+
+	// reopening with different hashers is refused
+	openTestMap := func(store kvstore.KVStore, opts ...options.Option[Options]) error {
+		_, err := OpenMap[[32]byte, testKey, testValue](store,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			opts...,
+		)
+
+		return err
+	}
+	require.ErrorIs(t, openTestMap(customStore), ErrHasherMismatch)
+	require.ErrorIs(t, openTestMap(customStore, WithHasher(sha512.New512_256)), ErrHasherMismatch)
+	require.ErrorIs(t, openTestMap(defaultStore, WithHasher(sha512.New512_256)), ErrHasherMismatch)
+	require.ErrorIs(t, openTestMap(mapdb.NewMapDB(), WithHasher(sha512.New)), ErrInvalidHasher)
+	require.NoError(t, openTestMap(customStore, WithHasher(sha512.New512_256), WithValueHasher(sha512.New512_256)))
+
+	// NewMap panics instead of returning the error
+	func() {
+		defer func() {
+			err, isError := recover().(error)
+			require.True(t, isError)
+			require.ErrorIs(t, err, ErrHasherMismatch)
+		}()
+
+		newTestMap(customStore)
+	}()
+	require.Equal(t, defaultMap.Root(), newTestMap(defaultStore).Root())
+
+	// pruning removes hashed values that are not needed anymore
+	require.NoError(t, reopenedMap.Prune(2))
+	valuesCount := 0
+	require.NoError(t, lo.PanicOnErr(customStore.WithExtendedRealm([]byte{prefixValuesStorage})).IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+		valuesCount++
+
+		return true
+	}))
+	require.Equal(t, 1, valuesCount)
+}
+
+func TestStreamOptionsIterateKeys(t *testing.T) {
+	store := mapdb.NewMapDB()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, store.Set([]byte{byte(i / 5), byte(i)}, []byte{}))
+	}
+
+	collectKeys := func(store kvstore.KVStore, opts ...options.Option[StreamOptions]) (keys []byte) {
+		require.NoError(t, newStreamOptions(opts...).iterateKeys(store, func(key kvstore.Key) bool {
+			keys = append(keys, key[1])
+
+			return true
+		}))
+
+		return keys
+	}
+
+	// stores that support it seek to the cursor, other stores skip the keys before it (with the same result)
+	for expectedKeys, opts := range map[string][]options.Option[StreamOptions]{
+		"\x03\x04\x05\x06\x07\x08\x09": {WithStreamCursor([]byte{0, 3})},
+		"\x06\x05\x04\x03\x02\x01\x00": {WithStreamCursor([]byte{1, 6}), WithStreamDirection(kvstore.IterDirectionBackward)},
+		"\x07\x08\x09":                 {WithStreamCursor([]byte{1, 7}), WithStreamPrefix([]byte{1})},
+		"\x05\x06\x07\x08\x09":         {WithStreamCursor([]byte{0, 9}), WithStreamPrefix([]byte{1})},
+		"\x04\x03\x02\x01\x00":         {WithStreamCursor([]byte{2}), WithStreamPrefix([]byte{0}), WithStreamDirection(kvstore.IterDirectionBackward)},
+	} {
+		require.Equal(t, []byte(expectedKeys), collectKeys(store, opts...))
+		require.Equal(t, []byte(expectedKeys), collectKeys(&nonSeekingStore{KVStore: store}, opts...))
+	}
+}
+
+// nonSeekingStore is a KVStore that can not start an iteration at a given key.
+type nonSeekingStore struct {
+	kvstore.KVStore
+}
+
+func TestMapStreamRange(t *testing.T) {
+	newMap := newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+	)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, newMap.Set(testKey{byte(i)}, testValueFromString(fmt.Sprintf("%d", i))))
+	}
+
+	streamPage := func(opts ...options.Option[StreamOptions]) (keys []byte, nextCursor []byte) {
+		nextCursor, err := newMap.StreamRange(func(key testKey, value testValue) error {
+			require.Equal(t, testValueFromString(fmt.Sprintf("%d", key[0])), value)
+			keys = append(keys, key[0])
+
+			return nil
+		}, opts...)
+		require.NoError(t, err)
+
+		return keys, nextCursor
+	}
+
+	keys, nextCursor := streamPage()
+	require.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, keys)
+	require.Nil(t, nextCursor)
+
+	// paginate forward
+	keys, nextCursor = streamPage(WithStreamLimit(4))
+	require.Equal(t, []byte{0, 1, 2, 3}, keys)
+	require.Equal(t, []byte{4}, nextCursor)
+
+	keys, nextCursor = streamPage(WithStreamLimit(4), WithStreamCursor(nextCursor))
+	require.Equal(t, []byte{4, 5, 6, 7}, keys)
+	require.Equal(t, []byte{8}, nextCursor)
+
+	keys, nextCursor = streamPage(WithStreamLimit(4), WithStreamCursor(nextCursor))
+	require.Equal(t, []byte{8, 9}, keys)
+	require.Nil(t, nextCursor)
+
+	// paginate backward from a key
+	keys, nextCursor = streamPage(WithStreamLimit(3), WithStreamCursor([]byte{6}), WithStreamDirection(kvstore.IterDirectionBackward))
+	require.Equal(t, []byte{6, 5, 4}, keys)
+	require.Equal(t, []byte{3}, nextCursor)
+
+	// restrict to a prefix
+	keys, nextCursor = streamPage(WithStreamPrefix([]byte{7}))
+	require.Equal(t, []byte{7}, keys)
+	require.Nil(t, nextCursor)
+
+	// sets are streamed in the same way
+	newSet := newAuthenticatedSet[[32]byte](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+	require.NoError(t, newSet.Add(testKey{'a'}))
+	require.NoError(t, newSet.Add(testKey{'b'}))
+	require.NoError(t, newSet.Add(testKey{'c'}))
+
+	setKeys := make([]testKey, 0)
+	nextCursor, err := newSet.StreamRange(func(key testKey) error {
+		setKeys = append(setKeys, key)
+
+		return nil
+	}, WithStreamDirection(kvstore.IterDirectionBackward), WithStreamLimit(2))
+	require.NoError(t, err)
+	require.Equal(t, []testKey{{'c'}, {'b'}}, setKeys)
+	require.Equal(t, []byte{'a'}, nextCursor)
+}
+
+func TestReadOnlyMapStreamRange(t *testing.T) {
+	newMap := newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+	)
+
+	// version 1: 0-9
+	for i := 0; i < 10; i++ {
+		require.NoError(t, newMap.Set(testKey{byte(i)}, testValueFromString(fmt.Sprintf("%d", i))))
+	}
+	require.NoError(t, newMap.Commit())
+
+	// version 2: even keys deleted, 10-12 added
+	for i := 0; i < 10; i += 2 {
+		require.True(t, lo.PanicOnErr(newMap.Delete(testKey{byte(i)})))
+	}
+	for i := 10; i < 13; i++ {
+		require.NoError(t, newMap.Set(testKey{byte(i)}, testValueFromString(fmt.Sprintf("%d", i))))
+	}
+	require.NoError(t, newMap.Commit())
+
+	// version 3: 4 added again with a different value
+	require.NoError(t, newMap.Set(testKey{4}, testValueFromString("new")))
+	require.NoError(t, newMap.Commit())
+
+	version1 := lo.PanicOnErr(newMap.ReadAt(1))
+	streamPage := func(opts ...options.Option[StreamOptions]) (keys []byte, nextCursor []byte) {
+		nextCursor, err := version1.StreamRange(func(key testKey, value testValue) error {
+			require.Equal(t, testValueFromString(fmt.Sprintf("%d", key[0])), value)
+			keys = append(keys, key[0])
+
+			return nil
+		}, opts...)
+		require.NoError(t, err)
+
+		return keys, nextCursor
+	}
+
+	keys, nextCursor := streamPage()
+	require.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, keys)
+	require.Nil(t, nextCursor)
+
+	// paginate forward over existing and removed keys
+	keys, nextCursor = streamPage(WithStreamLimit(4))
+	require.Equal(t, []byte{0, 1, 2, 3}, keys)
+	require.Equal(t, []byte{4}, nextCursor)
+
+	keys, nextCursor = streamPage(WithStreamLimit(4), WithStreamCursor(nextCursor))
+	require.Equal(t, []byte{4, 5, 6, 7}, keys)
+	require.Equal(t, []byte{8}, nextCursor)
+
+	keys, nextCursor = streamPage(WithStreamLimit(4), WithStreamCursor(nextCursor))
+	require.Equal(t, []byte{8, 9}, keys)
+	require.Nil(t, nextCursor)
+
+	// paginate backward from a key
+	keys, nextCursor = streamPage(WithStreamLimit(3), WithStreamCursor([]byte{6}), WithStreamDirection(kvstore.IterDirectionBackward))
+	require.Equal(t, []byte{6, 5, 4}, keys)
+	require.Equal(t, []byte{3}, nextCursor)
+
+	// restrict to a prefix of a removed key
+	keys, nextCursor = streamPage(WithStreamPrefix([]byte{8}))
+	require.Equal(t, []byte{8}, keys)
+	require.Nil(t, nextCursor)
+}
+
 type testKey [1]byte
 
 func (t testKey) Bytes() ([]byte, error) {
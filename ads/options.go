@@ -0,0 +1,128 @@
+package ads
+
+import (
+	"crypto/sha256"
+	"hash"
+	"runtime"
+
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
+)
+
+// hasherFingerprintInput is the data that is hashed to identify a hash function.
+var hasherFingerprintInput = []byte("hive.go/ads")
+
+// Options contains the options of a Map or a Set.
+type Options struct {
+	// hasher is the hash function that is used to build the sparse merkle tree.
+	hasher func() hash.Hash
+
+	// valueHasher is the hash function that is used to hash the values before they are stored in the leaves.
+	valueHasher func() hash.Hash
+
+	// batchWorkerCount is the number of workers that hash the subtrees of a batch in parallel.
+	batchWorkerCount int
+
+	// batchWorkerPool is the worker pool that hashes the subtrees of a batch in parallel.
+	batchWorkerPool *workerpool.WorkerPool
+
+	// importBatchSize is the number of snapshot entries (or tree nodes) that are written to the store in a single batch.
+	importBatchSize int
+}
+
+// WithHasher sets the hash function that is used to build the sparse merkle tree (default: SHA-256).
+//
+// The hash function is persisted with the map, so opening an existing map with a different hash function fails with
+// ErrHasherMismatch (NewMap and NewSet panic with it).
+func WithHasher(hasher func() hash.Hash) options.Option[Options] {
+	return func(o *Options) {
+		o.hasher = hasher
+	}
+}
+
+// WithValueHasher sets the hash function that is used to hash the values before they are stored in the leaves of the
+// sparse merkle tree (default: nil - values are stored in the leaves as they are).
+func WithValueHasher(valueHasher func() hash.Hash) options.Option[Options] {
+	return func(o *Options) {
+		o.valueHasher = valueHasher
+	}
+}
+
+// WithBatchWorkerCount sets the number of workers that hash the subtrees of a batch in parallel (default: number of
+// logical CPUs, minimum: 1).
+func WithBatchWorkerCount(batchWorkerCount int) options.Option[Options] {
+	return func(o *Options) {
+		o.batchWorkerCount = max(batchWorkerCount, 1)
+	}
+}
+
+// WithBatchWorkerPool sets the (started) worker pool that hashes the subtrees of a batch in parallel, which overrides
+// WithBatchWorkerCount (default: a pool with batchWorkerCount workers that is started for every batch and shut down
+// once it is done).
+//
+// Maps only share a pool if it is passed with this option, and the caller is responsible for shutting it down once it
+// is not used by any map anymore. The pool needs at least one worker (opening the map fails with
+// ErrInvalidBatchWorkerPool otherwise).
+func WithBatchWorkerPool(batchWorkerPool *workerpool.WorkerPool) options.Option[Options] {
+	return func(o *Options) {
+		o.batchWorkerPool = batchWorkerPool
+	}
+}
+
+// WithImportBatchSize sets the number of snapshot entries (or tree nodes) that ImportMap and ImportMapNodes write to
+// the store in a single batch, which bounds the memory that is used by an import (default: 10000, minimum: 1).
+func WithImportBatchSize(importBatchSize int) options.Option[Options] {
+	return func(o *Options) {
+		o.importBatchSize = max(importBatchSize, 1)
+	}
+}
+
+// newOptions creates the Options from the given options.
+func newOptions(opts ...options.Option[Options]) *Options {
+	return options.Apply(&Options{
+		hasher:           sha256.New,
+		batchWorkerCount: runtime.NumCPU(),
+		importBatchSize:  10000,
+	}, opts)
+}
+
+// fingerprint returns a byte representation that identifies the configured hash functions.
+func (o *Options) fingerprint() []byte {
+	treeHasherFingerprint := hasherFingerprint(o.hasher)
+
+	return byteutils.ConcatBytes([]byte{byte(len(treeHasherFingerprint))}, treeHasherFingerprint, hasherFingerprint(o.valueHasher))
+}
+
+// hasherFingerprint returns the hash of a fixed input, which identifies the hash function (nil hashers have an empty
+// fingerprint).
+func hasherFingerprint(newHasher func() hash.Hash) []byte {
+	if newHasher == nil {
+		return []byte{}
+	}
+
+	hasher := newHasher()
+	_, _ = hasher.Write(hasherFingerprintInput)
+
+	return hasher.Sum(nil)
+}
+
+// valueHasher is an adapter that implements the ValueHasher interface of pokt-network/smt.
+type valueHasher struct {
+	hasher hash.Hash
+}
+
+// newValueHasher creates a new valueHasher.
+func newValueHasher(newHasher func() hash.Hash) *valueHasher {
+	return &valueHasher{
+		hasher: newHasher(),
+	}
+}
+
+// HashValue returns the digest of the given value.
+func (v *valueHasher) HashValue(data []byte) []byte {
+	v.hasher.Reset()
+	_, _ = v.hasher.Write(data)
+
+	return v.hasher.Sum(nil)
+}
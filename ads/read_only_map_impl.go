@@ -0,0 +1,214 @@
+package ads
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/pokt-network/smt"
+
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// readOnlyMap is a read-only view of an authenticatedMap at a committed version.
+type readOnlyMap[IdentifierType types.IdentifierType, K, V any] struct {
+	source  *authenticatedMap[IdentifierType, K, V]
+	tree    *smt.SMT
+	version uint64
+	root    IdentifierType
+	size    uint64
+}
+
+// newReadOnlyMap creates a new read-only view of the given map.
+func newReadOnlyMap[IdentifierType types.IdentifierType, K, V any](source *authenticatedMap[IdentifierType, K, V], version uint64, root IdentifierType, size uint64) *readOnlyMap[IdentifierType, K, V] {
+	return &readOnlyMap[IdentifierType, K, V]{
+		source:  source,
+		tree:    source.importTree(root, nil),
+		version: version,
+		root:    root,
+		size:    size,
+	}
+}
+
+// Get returns the value for the given key.
+func (r *readOnlyMap[IdentifierType, K, V]) Get(key K) (value V, exists bool, err error) {
+	r.source.mutex.Lock()
+	defer r.source.mutex.Unlock()
+
+	keyBytes, err := r.source.keyToBytes(key)
+	if err != nil {
+		return value, false, ierrors.Wrap(err, "failed to serialize key")
+	}
+
+	return r.get(keyBytes)
+}
+
+// Has returns true if the given key exists.
+func (r *readOnlyMap[IdentifierType, K, V]) Has(key K) (exists bool, err error) {
+	r.source.mutex.Lock()
+	defer r.source.mutex.Unlock()
+
+	keyBytes, err := r.source.keyToBytes(key)
+	if err != nil {
+		return false, ierrors.Wrap(err, "failed to serialize key")
+	}
+
+	valueBytes, err := r.tree.Get(keyBytes)
+	if err != nil {
+		return false, ierrors.Wrap(err, "failed to get from tree")
+	}
+
+	return valueBytes != nil, nil
+}
+
+// Stream streams all key-value pairs to the given consumer function.
+func (r *readOnlyMap[IdentifierType, K, V]) Stream(consumerFunc func(key K, value V) error) error {
+	_, err := r.StreamRange(consumerFunc)
+
+	return err
+}
+
+// StreamRange streams the key-value pairs in the range that is defined by the given options and returns the cursor of
+// the next element (nil if there are no more elements).
+//
+// The keys that currently exist are merged with the keys that were removed after the version of the view, so that the
+// elements are streamed in the order of their serialized keys. Only the removed keys are kept in memory.
+func (r *readOnlyMap[IdentifierType, K, V]) StreamRange(consumerFunc func(key K, value V) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error) {
+	r.source.mutex.Lock()
+	defer r.source.mutex.Unlock()
+
+	streamOptions := newStreamOptions(opts...)
+
+	removedKeys, err := r.removedKeys(streamOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	streamedElements := 0
+	streamKey := func(keyBytes []byte) (done bool, err error) {
+		value, exists, err := r.get(keyBytes)
+		if err != nil {
+			return false, ierrors.Wrapf(err, "failed to get value for key %s", keyBytes)
+		} else if !exists {
+			return false, nil
+		}
+
+		if streamOptions.limit > 0 && streamedElements == streamOptions.limit {
+			nextCursor = keyBytes
+
+			return true, nil
+		}
+
+		key, _, err := r.source.bytesToKey(keyBytes)
+		if err != nil {
+			return false, ierrors.Wrapf(err, "failed to deserialize key %s", keyBytes)
+		}
+
+		if err = consumerFunc(key, value); err != nil {
+			return false, ierrors.Wrapf(err, "failed to execute callback for key %s", keyBytes)
+		}
+		streamedElements++
+
+		return false, nil
+	}
+
+	var done bool
+	var innerErr error
+	if err = streamOptions.iterateKeys(r.source.rawKeysStore.KVStore(), func(keyBytes kvstore.Key) bool {
+		for ; len(removedKeys) > 0 && streamOptions.isBefore(removedKeys[0], keyBytes); removedKeys = removedKeys[1:] {
+			if done, innerErr = streamKey(removedKeys[0]); done || innerErr != nil {
+				return false
+			}
+		}
+
+		done, innerErr = streamKey(keyBytes)
+
+		return !done && innerErr == nil
+	}); err != nil {
+		return nil, ierrors.Wrap(err, "failed to iterate over raw keys")
+	} else if innerErr != nil {
+		return nil, innerErr
+	}
+
+	for ; !done && len(removedKeys) > 0; removedKeys = removedKeys[1:] {
+		if done, err = streamKey(removedKeys[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return nextCursor, nil
+}
+
+// Root returns the root of the sparse merkle tree.
+func (r *readOnlyMap[IdentifierType, K, V]) Root() IdentifierType {
+	return r.root
+}
+
+// Size returns the number of elements in the map.
+func (r *readOnlyMap[IdentifierType, K, V]) Size() int {
+	return int(r.size)
+}
+
+// Version returns the version of the view.
+func (r *readOnlyMap[IdentifierType, K, V]) Version() uint64 {
+	return r.version
+}
+
+// get returns the value for the given key bytes.
+func (r *readOnlyMap[IdentifierType, K, V]) get(keyBytes []byte) (value V, exists bool, err error) {
+	leafData, err := r.tree.Get(keyBytes)
+	if err != nil {
+		return value, false, ierrors.Wrap(err, "failed to get from tree")
+	} else if leafData == nil {
+		return value, false, nil
+	}
+
+	valueBytes, err := r.source.resolveValue(keyBytes, leafData)
+	if err != nil {
+		return value, false, err
+	}
+
+	v, consumed, err := r.source.bytesToValue(valueBytes)
+	if err != nil {
+		return value, false, ierrors.Wrap(err, "failed to deserialize value")
+	} else if consumed != len(valueBytes) {
+		return value, false, ierrors.New("failed to parse entire value")
+	}
+
+	return v, true, nil
+}
+
+// removedKeys returns the keys of the stream that were removed after the version of the view and that do not exist
+// anymore, ordered in the direction of the stream.
+func (r *readOnlyMap[IdentifierType, K, V]) removedKeys(streamOptions *StreamOptions) ([][]byte, error) {
+	removedKeys := make(map[string]types.Empty)
+	if err := r.source.versionLog.RemovedKeys(r.version, func(keyBytes []byte) error {
+		if _, collected := removedKeys[string(keyBytes)]; collected || !streamOptions.contains(keyBytes) {
+			return nil
+		}
+
+		// keys that were added again are streamed with the existing keys
+		if exists, err := r.source.rawKeysStore.KVStore().Has(keyBytes); err != nil {
+			return ierrors.Wrapf(err, "failed to check raw key %s", keyBytes)
+		} else if !exists {
+			removedKeys[string(keyBytes)] = types.Void
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sortedKeys := make([][]byte, 0, len(removedKeys))
+	for keyBytes := range removedKeys {
+		sortedKeys = append(sortedKeys, []byte(keyBytes))
+	}
+	slices.SortFunc(sortedKeys, bytes.Compare)
+	if streamOptions.direction == kvstore.IterDirectionBackward {
+		slices.Reverse(sortedKeys)
+	}
+
+	return sortedKeys, nil
+}
@@ -1,8 +1,11 @@
 package ads
 
 import (
+	"io"
+
 	"github.com/iotaledger/hive.go/ds/types"
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
 )
 
 // Set is a set that can produce proofs for its elements which can be verified against a known merkle root
@@ -23,23 +26,93 @@ type Set[IdentifierType types.IdentifierType, K any] interface {
 	// Stream streams all the set elements to the given consumer function.
 	Stream(consumerFunc func(key K) error) error
 
-	// Commit persists the changes to the underlying store.
+	// StreamRange streams the set elements in the range that is defined by the given options (ordered by their
+	// serialized keys) and returns the cursor of the next element (nil if there are no more elements).
+	StreamRange(consumerFunc func(key K) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error)
+
+	// Commit persists the changes to the underlying store as a new version.
 	Commit() error
 
 	// Size returns the number of elements in the set.
 	Size() int
 
+	// Version returns the version of the latest commit (0 if the set was never committed).
+	Version() uint64
+
+	// ReadAt returns a read-only view of the set at the given committed version.
+	ReadAt(version uint64) (ReadOnlySet[IdentifierType, K], error)
+
+	// Rollback restores the set to the given committed version and discards all later versions and uncommitted changes.
+	Rollback(version uint64) error
+
+	// Prune discards all versions older than the given version and deletes the tree nodes that are no longer needed.
+	Prune(version uint64) error
+
+	// Diff streams the keys that differ between the given committed roots of the set.
+	Diff(sourceRoot IdentifierType, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType) error) error
+
+	// DiffRemote streams the keys that differ between the given committed root of the set and the root of a remote
+	// tree, that is accessed through the given TreeReader.
+	DiffRemote(sourceRoot IdentifierType, remote TreeReader, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType) error) error
+
+	// TreeReader returns a TreeReader for the committed nodes of the set (e.g. to serve the requests of remote peers).
+	TreeReader() TreeReader
+
+	// Export writes the elements of the latest committed version to the given writer (see ImportSet).
+	Export(writer io.Writer) error
+
+	// ExportNodes writes the tree nodes of the latest committed version to the given writer (in depth-first order,
+	// so that every node can be verified against its parent and the root while reading, see ImportSetNodes).
+	ExportNodes(writer io.Writer) error
+
 	// WasRestoredFromStorage returns true if the set was restored from an existing storage.
 	WasRestoredFromStorage() bool
 }
 
+// ReadOnlySet is a read-only view of a Set at a committed version.
+type ReadOnlySet[IdentifierType types.IdentifierType, K any] interface {
+	// Root returns the root of the sparse merkle tree.
+	Root() IdentifierType
+
+	// Has returns true if the given key exists.
+	Has(key K) (exists bool, err error)
+
+	// Stream streams all the set elements to the given consumer function.
+	Stream(consumerFunc func(key K) error) error
+
+	// StreamRange streams the set elements in the range that is defined by the given options (ordered by their
+	// serialized keys) and returns the cursor of the next element (nil if there are no more elements).
+	StreamRange(consumerFunc func(key K) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error)
+
+	// Size returns the number of elements in the set.
+	Size() int
+
+	// Version returns the version of the view.
+	Version() uint64
+}
+
 // NewSet creates a new sparse merkle tree based set.
+//
+// It panics if the set can not be opened (see NewMap). Use OpenSet to handle these errors instead.
 func NewSet[IdentifierType types.IdentifierType, K any](
 	store kvstore.KVStore,
 	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
 	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
 	keyToBytes kvstore.ObjectToBytes[K],
 	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
 ) Set[IdentifierType, K] {
-	return newAuthenticatedSet[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey)
+	return newAuthenticatedSet[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, opts...)
+}
+
+// OpenSet creates a new sparse merkle tree based set and returns an error if the set can not be opened.
+func OpenSet[IdentifierType types.IdentifierType, K any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
+) (Set[IdentifierType, K], error) {
+	return openAuthenticatedSet[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, opts...)
 }
@@ -3,6 +3,8 @@ package ads
 import (
 	"github.com/iotaledger/hive.go/ds/types"
 	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
 )
 
 // Set is a sparse merkle tree based set.
@@ -10,17 +12,33 @@ type authenticatedSet[IdentifierType types.IdentifierType, K any] struct {
 	*authenticatedMap[IdentifierType, K, types.Empty]
 }
 
-// NewAuthenticatedSet creates a new sparse merkle tree based set.
+// NewAuthenticatedSet creates a new sparse merkle tree based set and panics if it can not be opened.
 func newAuthenticatedSet[IdentifierType types.IdentifierType, K any](
 	store kvstore.KVStore,
 	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
 	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
 	keyToBytes kvstore.ObjectToBytes[K],
 	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
 ) Set[IdentifierType, K] {
-	return &authenticatedSet[IdentifierType, K]{
-		authenticatedMap: newAuthenticatedMap[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, types.Empty.Bytes, types.EmptyFromBytes),
+	return lo.PanicOnErr(openAuthenticatedSet(store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, opts...))
+}
+
+// openAuthenticatedSet creates a new sparse merkle tree based set and returns an error if it can not be opened.
+func openAuthenticatedSet[IdentifierType types.IdentifierType, K any](
+	store kvstore.KVStore,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
+) (Set[IdentifierType, K], error) {
+	authenticatedMap, err := openAuthenticatedMap[IdentifierType](store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, types.Empty.Bytes, types.EmptyFromBytes, opts...)
+	if err != nil {
+		return nil, err
 	}
+
+	return &authenticatedSet[IdentifierType, K]{authenticatedMap: authenticatedMap}, nil
 }
 
 // Add adds the key to the set.
@@ -34,3 +52,56 @@ func (s *authenticatedSet[IdentifierType, K]) Stream(callback func(key K) error)
 		return callback(key)
 	})
 }
+
+// StreamRange iterates over the elements of the set in the range that is defined by the given options and returns the
+// cursor of the next element (nil if there are no more elements).
+func (s *authenticatedSet[IdentifierType, K]) StreamRange(callback func(key K) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error) {
+	return s.authenticatedMap.StreamRange(func(key K, _ types.Empty) error {
+		return callback(key)
+	}, opts...)
+}
+
+// Diff streams the keys that differ between the given committed roots of the set.
+func (s *authenticatedSet[IdentifierType, K]) Diff(sourceRoot IdentifierType, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType) error) error {
+	return s.authenticatedMap.Diff(sourceRoot, targetRoot, func(key K, diffType DiffType, _ types.Empty, _ types.Empty) error {
+		return consumerFunc(key, diffType)
+	})
+}
+
+// DiffRemote streams the keys that differ between the given committed root of the set and the root of a remote tree.
+func (s *authenticatedSet[IdentifierType, K]) DiffRemote(sourceRoot IdentifierType, remote TreeReader, targetRoot IdentifierType, consumerFunc func(key K, diffType DiffType) error) error {
+	return s.authenticatedMap.DiffRemote(sourceRoot, remote, targetRoot, func(key K, diffType DiffType, _ types.Empty, _ types.Empty) error {
+		return consumerFunc(key, diffType)
+	})
+}
+
+// ReadAt returns a read-only view of the set at the given committed version.
+// The view must not be used anymore once its version was pruned or rolled back.
+func (s *authenticatedSet[IdentifierType, K]) ReadAt(version uint64) (ReadOnlySet[IdentifierType, K], error) {
+	readOnlyMap, err := s.authenticatedMap.readAt(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlySet[IdentifierType, K]{readOnlyMap: readOnlyMap}, nil
+}
+
+// readOnlySet is a read-only view of an authenticatedSet at a committed version.
+type readOnlySet[IdentifierType types.IdentifierType, K any] struct {
+	*readOnlyMap[IdentifierType, K, types.Empty]
+}
+
+// Stream iterates over the set and calls the callback for each element.
+func (s *readOnlySet[IdentifierType, K]) Stream(callback func(key K) error) error {
+	return s.readOnlyMap.Stream(func(key K, _ types.Empty) error {
+		return callback(key)
+	})
+}
+
+// StreamRange iterates over the elements of the set in the range that is defined by the given options and returns the
+// cursor of the next element (nil if there are no more elements).
+func (s *readOnlySet[IdentifierType, K]) StreamRange(callback func(key K) error, opts ...options.Option[StreamOptions]) (nextCursor []byte, err error) {
+	return s.readOnlyMap.StreamRange(func(key K, _ types.Empty) error {
+		return callback(key)
+	}, opts...)
+}
@@ -0,0 +1,574 @@
+package ads
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/hive.go/serializer/v2/stream"
+)
+
+var (
+	// ErrRootMismatch is returned when an imported snapshot does not result in the expected root.
+	ErrRootMismatch = ierrors.New("root mismatch")
+
+	// ErrStoreNotEmpty is returned when a snapshot is imported into a store that already contains data.
+	ErrStoreNotEmpty = ierrors.New("store not empty")
+)
+
+// ImportMap creates a new Map in the given empty store from a snapshot that was written by Map.Export.
+//
+// The tree is built in bulk from the key-value pairs of the snapshot (like Map.ApplyBatch) and written to the store in
+// batches of bounded size (see WithImportBatchSize). The imported tree only becomes the first version of the map if
+// its root matches the expected root - otherwise the store is cleared again.
+func ImportMap[IdentifierType types.IdentifierType, K, V any](
+	store kvstore.KVStore,
+	reader io.Reader,
+	expectedRoot IdentifierType,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (Map[IdentifierType, K, V], error) {
+	importedMap, err := importAuthenticatedMap(store, reader, expectedRoot, (*authenticatedMap[IdentifierType, K, V]).importEntries, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return importedMap, nil
+}
+
+// ImportMapNodes creates a new Map in the given empty store from a snapshot that was written by Map.ExportNodes.
+//
+// Every node is verified against the hash that its parent (or the expected root) references before it is written to
+// the store (in batches of bounded size, see WithImportBatchSize). If the snapshot is invalid, the store is cleared
+// again.
+func ImportMapNodes[IdentifierType types.IdentifierType, K, V any](
+	store kvstore.KVStore,
+	reader io.Reader,
+	expectedRoot IdentifierType,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (Map[IdentifierType, K, V], error) {
+	importedMap, err := importAuthenticatedMap(store, reader, expectedRoot, (*authenticatedMap[IdentifierType, K, V]).importNodes, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return importedMap, nil
+}
+
+// ImportSet creates a new Set in the given empty store from a snapshot that was written by Set.Export (see ImportMap).
+func ImportSet[IdentifierType types.IdentifierType, K any](
+	store kvstore.KVStore,
+	reader io.Reader,
+	expectedRoot IdentifierType,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
+) (Set[IdentifierType, K], error) {
+	importedMap, err := importAuthenticatedMap(store, reader, expectedRoot, (*authenticatedMap[IdentifierType, K, types.Empty]).importEntries, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, types.Empty.Bytes, types.EmptyFromBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authenticatedSet[IdentifierType, K]{
+		authenticatedMap: importedMap,
+	}, nil
+}
+
+// ImportSetNodes creates a new Set in the given empty store from a snapshot that was written by Set.ExportNodes (see
+// ImportMapNodes).
+func ImportSetNodes[IdentifierType types.IdentifierType, K any](
+	store kvstore.KVStore,
+	reader io.Reader,
+	expectedRoot IdentifierType,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	opts ...options.Option[Options],
+) (Set[IdentifierType, K], error) {
+	importedMap, err := importAuthenticatedMap(store, reader, expectedRoot, (*authenticatedMap[IdentifierType, K, types.Empty]).importNodes, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, types.Empty.Bytes, types.EmptyFromBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authenticatedSet[IdentifierType, K]{
+		authenticatedMap: importedMap,
+	}, nil
+}
+
+// Export writes the key-value pairs of the latest committed version of the map to the given writer.
+//
+// The snapshot starts with the root and the number of elements, followed by the serialized key and value of every
+// element (each prefixed with its uint32 length).
+func (m *authenticatedMap[IdentifierType, K, V]) Export(writer io.Writer) error {
+	var root IdentifierType
+	var size uint64
+	var streamFunc func(consumerFunc func(key K, value V) error) error
+
+	if version := m.Version(); version == 0 {
+		streamFunc = func(func(key K, value V) error) error { return nil }
+	} else {
+		committedMap, err := m.readAt(version)
+		if err != nil {
+			return ierrors.Wrapf(err, "failed to read version %d", version)
+		}
+
+		root, size, streamFunc = committedMap.Root(), committedMap.size, committedMap.Stream
+	}
+
+	if err := writeSnapshotHeader(writer, root, size); err != nil {
+		return err
+	}
+
+	return streamFunc(func(key K, value V) error {
+		keyBytes, err := m.keyToBytes(key)
+		if err != nil {
+			return ierrors.Wrap(err, "failed to serialize key")
+		}
+
+		valueBytes, err := m.valueToBytes(value)
+		if err != nil {
+			return ierrors.Wrapf(err, "failed to serialize value of key %x", keyBytes)
+		}
+
+		return writeSnapshotEntry(writer, keyBytes, valueBytes)
+	})
+}
+
+// ExportNodes writes the tree nodes of the latest committed version of the map to the given writer.
+//
+// The snapshot starts with the same header as the snapshots of Export and then contains the preimages of the nodes in
+// depth-first order (each prefixed with its uint32 length). The preimage of a leaf is followed by the serialized key
+// and value of the leaf (like the entries of Export), as the leaf only contains their hashes. The hashes of the nodes
+// are not written, as they are determined by the preimages and the position in the tree.
+func (m *authenticatedMap[IdentifierType, K, V]) ExportNodes(writer io.Writer) error {
+	var root IdentifierType
+	var size uint64
+
+	if version := m.Version(); version != 0 {
+		committedMap, err := m.readAt(version)
+		if err != nil {
+			return ierrors.Wrapf(err, "failed to read version %d", version)
+		}
+
+		root, size = committedMap.Root(), committedMap.size
+	}
+
+	if err := writeSnapshotHeader(writer, root, size); err != nil {
+		return err
+	}
+
+	return exportNodes(writer, newTreeDiff(m.options, nil, nil, nil), m.TreeReader(), root[:])
+}
+
+// importAuthenticatedMap creates a new authenticatedMap in the given empty store from the given snapshot, using the
+// given function to import the snapshot after its header.
+func importAuthenticatedMap[IdentifierType types.IdentifierType, K, V any](
+	store kvstore.KVStore,
+	reader io.Reader,
+	expectedRoot IdentifierType,
+	importFunc func(targetMap *authenticatedMap[IdentifierType, K, V], reader io.Reader, root IdentifierType, size uint64) error,
+	identifierToBytes kvstore.ObjectToBytes[IdentifierType],
+	bytesToIdentifier kvstore.BytesToObject[IdentifierType],
+	keyToBytes kvstore.ObjectToBytes[K],
+	bytesToKey kvstore.BytesToObject[K],
+	valueToBytes kvstore.ObjectToBytes[V],
+	bytesToValue kvstore.BytesToObject[V],
+	opts ...options.Option[Options],
+) (importedMap *authenticatedMap[IdentifierType, K, V], err error) {
+	if err = ensureEmptyStore(store); err != nil {
+		return nil, err
+	}
+
+	root, size, err := readSnapshotHeader[IdentifierType](reader)
+	if err != nil {
+		return nil, err
+	} else if root != expectedRoot {
+		return nil, ierrors.Wrapf(ErrRootMismatch, "snapshot has root %x instead of %x", root, expectedRoot)
+	}
+
+	// the store was empty before, so clearing it removes everything that was written by an invalid snapshot
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if clearErr := store.Clear(); clearErr != nil {
+			err = ierrors.Join(err, ierrors.Wrap(clearErr, "failed to clear store"))
+		}
+	}()
+
+	targetMap, err := openAuthenticatedMap(store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to create target map")
+	}
+
+	if err = importFunc(targetMap, reader, root, size); err != nil {
+		return nil, err
+	}
+
+	if err = targetMap.commitStaged(func(*authenticatedMap[IdentifierType, K, V]) (IdentifierType, error) {
+		return expectedRoot, nil
+	}); err != nil {
+		return nil, ierrors.Wrap(err, "failed to commit imported tree")
+	}
+
+	return openAuthenticatedMap(store, identifierToBytes, bytesToIdentifier, keyToBytes, bytesToKey, valueToBytes, bytesToValue, opts...)
+}
+
+// importEntries builds the tree of the key-value pairs of a snapshot (that was written by Export) in bulk and writes
+// it to the (empty) map in batches, but returns an error if it does not result in the expected root.
+func (m *authenticatedMap[IdentifierType, K, V]) importEntries(reader io.Reader, expectedRoot IdentifierType, size uint64) error {
+	workerPool, releaseWorkerPool := batchWorkerPool(m.options)
+	defer releaseWorkerPool()
+
+	root := m.tree.Root()
+	for imported := uint64(0); imported < size; {
+		changes, err := readSnapshotEntries(reader, m.bytesToKey, m.bytesToValue, min(size-imported, uint64(m.options.importBatchSize)))
+		if err != nil {
+			return ierrors.Wrapf(err, "failed to read entries after %d imported entries", imported)
+		}
+
+		if root, err = m.importBatch(root, changes, workerPool); err != nil {
+			return err
+		}
+
+		imported += uint64(len(changes))
+	}
+
+	if importedRoot := IdentifierType(root); importedRoot != expectedRoot {
+		return ierrors.Wrapf(ErrRootMismatch, "imported tree has root %x instead of %x", importedRoot, expectedRoot)
+	}
+
+	return nil
+}
+
+// importBatch applies the given changes of an import to the tree with the given root, writes them to the store and
+// returns the new root.
+func (m *authenticatedMap[IdentifierType, K, V]) importBatch(root []byte, changes []BatchChange[K, V], workerPool *workerpool.WorkerPool) ([]byte, error) {
+	entries, err := m.prepareBatch(changes, workerPool)
+	if err != nil {
+		return nil, err
+	} else if len(entries) != len(changes) {
+		return nil, ierrors.Wrapf(ErrRootMismatch, "snapshot contains %d duplicate keys", len(changes)-len(entries))
+	}
+
+	treeBatch := newTreeBatch[K](m.options, newLocalTreeReader(m, false))
+	if root, err = treeBatch.Run(root, entries, workerPool); err != nil {
+		return nil, ierrors.Wrap(err, "failed to build imported tree")
+	}
+
+	for _, entry := range entries {
+		if entry.previousLeafData != nil {
+			return nil, ierrors.Wrapf(ErrRootMismatch, "snapshot contains the key %x more than once", entry.keyBytes)
+		}
+	}
+
+	stagingStore := newStagedStore(m.store)
+	stagingMap := m.stagingMap(stagingStore)
+
+	// the replaced nodes were created by the previous batches of the import and are not needed by any version
+	for _, orphanedNode := range treeBatch.orphanedNodes {
+		if err = stagingMap.versionLog.DiscardNode(orphanedNode); err != nil {
+			return nil, ierrors.Wrapf(err, "failed to discard node %x", orphanedNode)
+		}
+	}
+	treeBatch.orphanedNodes = nil
+
+	if err = stagingMap.applyBatchEntries(entries, treeBatch); err != nil {
+		return nil, err
+	}
+
+	if err = stagingStore.WriteBatch(); err != nil {
+		return nil, ierrors.Wrap(err, "failed to write imported entries")
+	}
+
+	return root, nil
+}
+
+// importNodes verifies the tree nodes of a snapshot (that was written by ExportNodes) against the expected root and
+// writes them (and the keys and values of the leaves) to the (empty) map in batches.
+func (m *authenticatedMap[IdentifierType, K, V]) importNodes(reader io.Reader, expectedRoot IdentifierType, _ uint64) error {
+	nodesImport := &nodesImport[IdentifierType, K, V]{
+		target:     m,
+		reader:     reader,
+		nodeParser: newTreeDiff(m.options, nil, nil, nil),
+	}
+	nodesImport.startBatch()
+
+	if err := nodesImport.importNode(expectedRoot[:]); err != nil {
+		return err
+	}
+
+	if err := nodesImport.stagingMap.addSize(nodesImport.importedLeaves); err != nil {
+		return ierrors.Wrap(err, "failed to set size")
+	}
+
+	return nodesImport.writeBatch()
+}
+
+// nodesImport writes the verified tree nodes of a snapshot to a map in batches.
+type nodesImport[IdentifierType types.IdentifierType, K, V any] struct {
+	target         *authenticatedMap[IdentifierType, K, V]
+	reader         io.Reader
+	nodeParser     *treeDiff
+	stagingStore   *stagedStore
+	stagingMap     *authenticatedMap[IdentifierType, K, V]
+	stagedNodes    int
+	importedLeaves int
+}
+
+// importNode reads the subtree with the given hash from the snapshot and stages it after verifying it.
+func (n *nodesImport[IdentifierType, K, V]) importNode(nodeHash []byte) error {
+	if bytes.Equal(nodeHash, make([]byte, n.nodeParser.hashSize)) {
+		return nil
+	}
+
+	preimage, err := stream.ReadBytesWithSize(n.reader, serializer.SeriLengthPrefixTypeAsUint32)
+	if err != nil {
+		return ierrors.Wrapf(err, "failed to read node %x", nodeHash)
+	}
+
+	node, err := n.nodeParser.parseNode(nodeHash, preimage)
+	if err != nil {
+		return ierrors.Join(ErrRootMismatch, err)
+	} else if !bytes.Equal(n.nodeParser.hashNode(node, preimage), nodeHash) {
+		return ierrors.Wrapf(ErrRootMismatch, "preimage does not match node %x", nodeHash)
+	}
+
+	if err = n.stagingMap.versionLog.SetNode(nodeHash, preimage); err != nil {
+		return ierrors.Wrapf(err, "failed to set node %x", nodeHash)
+	}
+
+	switch node.prefix {
+	case nodePrefixInner:
+		for _, childHash := range node.children {
+			if err = n.importNode(childHash); err != nil {
+				return err
+			}
+		}
+	case nodePrefixExtension:
+		if err = n.importNode(node.extensionChild); err != nil {
+			return err
+		}
+	case nodePrefixLeaf:
+		if err = n.importLeaf(node); err != nil {
+			return err
+		}
+	}
+
+	if n.stagedNodes++; n.stagedNodes >= n.target.options.importBatchSize {
+		if err = n.writeBatch(); err != nil {
+			return err
+		}
+		n.startBatch()
+	}
+
+	return nil
+}
+
+// importLeaf reads the key and the value of the given leaf from the snapshot and stages them after verifying them.
+func (n *nodesImport[IdentifierType, K, V]) importLeaf(leaf *diffNode) error {
+	keyBytes, valueBytes, err := readSnapshotEntry(n.reader)
+	if err != nil {
+		return ierrors.Wrapf(err, "failed to read key and value of leaf %x", leaf.hash)
+	}
+
+	if err = n.nodeParser.VerifyLeaf(leaf.leaf(), keyBytes, valueBytes); err != nil {
+		return ierrors.Join(ErrRootMismatch, err)
+	}
+
+	key, _, err := n.target.bytesToKey(keyBytes)
+	if err != nil {
+		return ierrors.Wrapf(err, "failed to deserialize key %x", keyBytes)
+	}
+
+	if err = n.stagingMap.setHashedValue(keyBytes, nil, valueBytes); err != nil {
+		return err
+	}
+
+	if err = n.stagingMap.rawKeysStore.Set(key, types.Void); err != nil {
+		return ierrors.Wrap(err, "failed to set raw key")
+	}
+
+	if err = n.stagingMap.versionLog.AddKey(keyBytes); err != nil {
+		return ierrors.Wrap(err, "failed to record added key")
+	}
+
+	if err = n.stagingMap.versionLog.SetKeyPath(leaf.path, keyBytes); err != nil {
+		return ierrors.Wrap(err, "failed to set key path")
+	}
+
+	n.importedLeaves++
+
+	return nil
+}
+
+// startBatch starts staging a new batch of writes.
+func (n *nodesImport[IdentifierType, K, V]) startBatch() {
+	n.stagingStore = newStagedStore(n.target.store)
+	n.stagingMap = n.target.stagingMap(n.stagingStore)
+	n.stagedNodes = 0
+}
+
+// writeBatch writes the staged batch to the store.
+func (n *nodesImport[IdentifierType, K, V]) writeBatch() error {
+	if err := n.stagingStore.WriteBatch(); err != nil {
+		return ierrors.Wrap(err, "failed to write imported nodes")
+	}
+
+	return nil
+}
+
+// writeSnapshotHeader writes the root and the number of elements of a snapshot.
+func writeSnapshotHeader[IdentifierType types.IdentifierType](writer io.Writer, root IdentifierType, size uint64) error {
+	if err := stream.WriteBytes(writer, root[:]); err != nil {
+		return ierrors.Wrap(err, "failed to write root")
+	}
+
+	if err := stream.Write(writer, size); err != nil {
+		return ierrors.Wrap(err, "failed to write size")
+	}
+
+	return nil
+}
+
+// readSnapshotHeader reads the root and the number of elements of a snapshot.
+func readSnapshotHeader[IdentifierType types.IdentifierType](reader io.Reader) (root IdentifierType, size uint64, err error) {
+	rootBytes, err := stream.ReadBytes(reader, len(root))
+	if err != nil {
+		return root, 0, ierrors.Wrap(err, "failed to read root")
+	}
+
+	if size, err = stream.Read[uint64](reader); err != nil {
+		return root, 0, ierrors.Wrap(err, "failed to read size")
+	}
+
+	return IdentifierType(rootBytes), size, nil
+}
+
+// exportNodes writes the preimages of the subtree with the given hash to the given writer (in depth-first order).
+func exportNodes(writer io.Writer, nodeParser *treeDiff, reader TreeReader, nodeHash []byte) error {
+	if bytes.Equal(nodeHash, make([]byte, nodeParser.hashSize)) {
+		return nil
+	}
+
+	preimage, err := reader.Node(nodeHash)
+	if err != nil {
+		return ierrors.Wrapf(err, "failed to retrieve node %x", nodeHash)
+	}
+
+	node, err := nodeParser.parseNode(nodeHash, preimage)
+	if err != nil {
+		return err
+	}
+
+	if err = stream.WriteBytesWithSize(writer, preimage, serializer.SeriLengthPrefixTypeAsUint32); err != nil {
+		return ierrors.Wrapf(err, "failed to write node %x", nodeHash)
+	}
+
+	switch node.prefix {
+	case nodePrefixInner:
+		for _, childHash := range node.children {
+			if err = exportNodes(writer, nodeParser, reader, childHash); err != nil {
+				return err
+			}
+		}
+	case nodePrefixExtension:
+		return exportNodes(writer, nodeParser, reader, node.extensionChild)
+	case nodePrefixLeaf:
+		keyBytes, valueBytes, err := reader.Leaf(node.path, node.leafData)
+		if err != nil {
+			return ierrors.Wrapf(err, "failed to retrieve leaf %x", node.path)
+		}
+
+		return writeSnapshotEntry(writer, keyBytes, valueBytes)
+	}
+
+	return nil
+}
+
+// writeSnapshotEntry writes the serialized key and value of an element of a snapshot.
+func writeSnapshotEntry(writer io.Writer, keyBytes []byte, valueBytes []byte) error {
+	if err := stream.WriteBytesWithSize(writer, keyBytes, serializer.SeriLengthPrefixTypeAsUint32); err != nil {
+		return ierrors.Wrapf(err, "failed to write key %x", keyBytes)
+	}
+
+	if err := stream.WriteBytesWithSize(writer, valueBytes, serializer.SeriLengthPrefixTypeAsUint32); err != nil {
+		return ierrors.Wrapf(err, "failed to write value of key %x", keyBytes)
+	}
+
+	return nil
+}
+
+// readSnapshotEntry reads the serialized key and value of an element of a snapshot.
+func readSnapshotEntry(reader io.Reader) (keyBytes []byte, valueBytes []byte, err error) {
+	if keyBytes, err = stream.ReadBytesWithSize(reader, serializer.SeriLengthPrefixTypeAsUint32); err != nil {
+		return nil, nil, ierrors.Wrap(err, "failed to read key")
+	}
+
+	if valueBytes, err = stream.ReadBytesWithSize(reader, serializer.SeriLengthPrefixTypeAsUint32); err != nil {
+		return nil, nil, ierrors.Wrapf(err, "failed to read value of key %x", keyBytes)
+	}
+
+	return keyBytes, valueBytes, nil
+}
+
+// readSnapshotEntries reads the given number of elements of a snapshot as BatchChanges.
+func readSnapshotEntries[K, V any](reader io.Reader, bytesToKey kvstore.BytesToObject[K], bytesToValue kvstore.BytesToObject[V], count uint64) ([]BatchChange[K, V], error) {
+	changes := make([]BatchChange[K, V], 0, count)
+	for i := uint64(0); i < count; i++ {
+		keyBytes, valueBytes, err := readSnapshotEntry(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		key, _, err := bytesToKey(keyBytes)
+		if err != nil {
+			return nil, ierrors.Wrapf(err, "failed to deserialize key %x", keyBytes)
+		}
+
+		value, _, err := bytesToValue(valueBytes)
+		if err != nil {
+			return nil, ierrors.Wrapf(err, "failed to deserialize value of key %x", keyBytes)
+		}
+
+		changes = append(changes, BatchChange[K, V]{Key: key, Value: value})
+	}
+
+	return changes, nil
+}
+
+// ensureEmptyStore returns an error if the given store contains any data.
+func ensureEmptyStore(store kvstore.KVStore) error {
+	isEmpty := true
+	if err := store.IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+		isEmpty = false
+
+		return false
+	}); err != nil {
+		return ierrors.Wrap(err, "failed to check if store is empty")
+	} else if !isEmpty {
+		return ErrStoreNotEmpty
+	}
+
+	return nil
+}
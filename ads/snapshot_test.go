@@ -0,0 +1,266 @@
+package ads
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/serializer/v2/typeutils"
+)
+
+func TestMapSnapshot(t *testing.T) {
+	sourceMap := newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+		WithValueHasher(sha256.New),
+	)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, sourceMap.Set(testKey{byte(i)}, testValueFromString(string(rune('a'+i)))))
+	}
+	require.NoError(t, sourceMap.Commit())
+
+	// uncommitted changes are not exported
+	require.NoError(t, sourceMap.Set(testKey{100}, testValueFromString("uncommitted")))
+
+	snapshot := new(bytes.Buffer)
+	require.NoError(t, sourceMap.Export(snapshot))
+
+	importMap := func(store kvstore.KVStore, snapshot []byte, expectedRoot [32]byte) (Map[[32]byte, testKey, testValue], error) {
+		return ImportMap(store, bytes.NewReader(snapshot), expectedRoot,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			WithValueHasher(sha256.New),
+		)
+	}
+
+	expectedRoot := lo.PanicOnErr(sourceMap.ReadAt(sourceMap.Version())).Root()
+
+	// manipulated snapshots are rejected without writing anything
+	manipulatedSnapshot := bytes.Clone(snapshot.Bytes())
+	manipulatedSnapshot[len(manipulatedSnapshot)-1]++
+
+	targetStore := mapdb.NewMapDB()
+	_, err := importMap(targetStore, manipulatedSnapshot, expectedRoot)
+	require.ErrorIs(t, err, ErrRootMismatch)
+	_, err = importMap(targetStore, snapshot.Bytes(), [32]byte{1})
+	require.ErrorIs(t, err, ErrRootMismatch)
+	_, err = importMap(targetStore, snapshot.Bytes()[:snapshot.Len()-1], expectedRoot)
+	require.Error(t, err)
+	require.NoError(t, ensureEmptyStore(targetStore))
+
+	importedMap, err := importMap(targetStore, snapshot.Bytes(), expectedRoot)
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, importedMap.Root())
+	require.Equal(t, 50, importedMap.Size())
+	require.True(t, importedMap.WasRestoredFromStorage())
+
+	value, exists, err := importedMap.Get(testKey{3})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, testValueFromString("d"), value)
+	require.False(t, lo.PanicOnErr(importedMap.Has(testKey{100})))
+
+	// snapshots can only be imported into empty stores
+	_, err = importMap(targetStore, snapshot.Bytes(), expectedRoot)
+	require.ErrorIs(t, err, ErrStoreNotEmpty)
+
+	// sets are exported and imported in the same way
+	sourceSet := newAuthenticatedSet[[32]byte](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+	require.NoError(t, sourceSet.Add(testKey{'a'}))
+	require.NoError(t, sourceSet.Add(testKey{'b'}))
+	require.NoError(t, sourceSet.Commit())
+
+	snapshot.Reset()
+	require.NoError(t, sourceSet.Export(snapshot))
+
+	importedSet, err := ImportSet(mapdb.NewMapDB(), snapshot, sourceSet.Root(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+	require.NoError(t, err)
+	require.Equal(t, sourceSet.Root(), importedSet.Root())
+	require.True(t, lo.PanicOnErr(importedSet.Has(testKey{'b'})))
+}
+
+func TestMapSnapshotBatches(t *testing.T) {
+	sourceMap := newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+	)
+	for i := 0; i < 200; i++ {
+		require.NoError(t, sourceMap.Set(testKey{byte(i)}, testValueFromString(string(rune('a'+i)))))
+	}
+	require.NoError(t, sourceMap.Commit())
+
+	snapshot := new(bytes.Buffer)
+	require.NoError(t, sourceMap.Export(snapshot))
+
+	importMap := func(store kvstore.KVStore, snapshot []byte) (Map[[32]byte, testKey, testValue], error) {
+		return ImportMap(store, bytes.NewReader(snapshot), sourceMap.Root(),
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			WithImportBatchSize(16),
+		)
+	}
+
+	// the batches that were written before the corrupted entry are removed again
+	targetStore := mapdb.NewMapDB()
+	corruptedSnapshot := bytes.Clone(snapshot.Bytes())
+	corruptedSnapshot[len(corruptedSnapshot)-1]++
+	_, err := importMap(targetStore, corruptedSnapshot)
+	require.ErrorIs(t, err, ErrRootMismatch)
+	require.NoError(t, ensureEmptyStore(targetStore))
+
+	_, err = importMap(targetStore, snapshot.Bytes()[:snapshot.Len()/2])
+	require.Error(t, err)
+	require.NoError(t, ensureEmptyStore(targetStore))
+
+	importedMap, err := importMap(targetStore, snapshot.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, sourceMap.Root(), importedMap.Root())
+	require.Equal(t, 200, importedMap.Size())
+	require.EqualValues(t, 1, importedMap.Version())
+
+	// the nodes that were replaced by later batches are not kept
+	countNodes := func(store kvstore.KVStore) (count int) {
+		require.NoError(t, lo.PanicOnErr(store.WithExtendedRealm([]byte{prefixTreeStorage})).IterateKeys(kvstore.EmptyPrefix, func(kvstore.Key) bool {
+			count++
+
+			return true
+		}))
+
+		return count
+	}
+	require.Equal(t, countNodes(sourceMap.store), countNodes(targetStore))
+}
+
+func TestMapSnapshotNodes(t *testing.T) {
+	sourceMap := newAuthenticatedMap[[32]byte, testKey, testValue](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+		testValue.Bytes,
+		testValueFromBytes,
+		WithValueHasher(sha256.New),
+	)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, sourceMap.Set(testKey{byte(i)}, testValueFromString(string(rune('a'+i)))))
+	}
+	require.NoError(t, sourceMap.Commit())
+
+	snapshot := new(bytes.Buffer)
+	require.NoError(t, sourceMap.ExportNodes(snapshot))
+
+	importMap := func(store kvstore.KVStore, snapshot []byte, expectedRoot [32]byte) (Map[[32]byte, testKey, testValue], error) {
+		return ImportMapNodes(store, bytes.NewReader(snapshot), expectedRoot,
+			typeutils.ByteArray32ToBytes,
+			typeutils.ByteArray32FromBytes,
+			testKey.Bytes,
+			testKeyFromBytes,
+			testValue.Bytes,
+			testValueFromBytes,
+			WithValueHasher(sha256.New),
+			WithImportBatchSize(8),
+		)
+	}
+
+	// manipulated snapshots are rejected and the store is left empty
+	targetStore := mapdb.NewMapDB()
+	for _, position := range []int{40, snapshot.Len() / 2, snapshot.Len() - 1} {
+		manipulatedSnapshot := bytes.Clone(snapshot.Bytes())
+		manipulatedSnapshot[position]++
+
+		_, err := importMap(targetStore, manipulatedSnapshot, sourceMap.Root())
+		require.ErrorIs(t, err, ErrRootMismatch)
+		require.NoError(t, ensureEmptyStore(targetStore))
+	}
+
+	_, err := importMap(targetStore, snapshot.Bytes(), [32]byte{1})
+	require.ErrorIs(t, err, ErrRootMismatch)
+	_, err = importMap(targetStore, snapshot.Bytes()[:snapshot.Len()-1], sourceMap.Root())
+	require.Error(t, err)
+	require.NoError(t, ensureEmptyStore(targetStore))
+
+	importedMap, err := importMap(targetStore, snapshot.Bytes(), sourceMap.Root())
+	require.NoError(t, err)
+	require.Equal(t, sourceMap.Root(), importedMap.Root())
+	require.Equal(t, 50, importedMap.Size())
+	require.EqualValues(t, 1, importedMap.Version())
+
+	// the raw keys, key paths and hashed values are restored as well
+	value, exists, err := importedMap.Get(testKey{3})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, testValueFromString("d"), value)
+	streamedElements := 0
+	require.NoError(t, importedMap.Stream(func(testKey, testValue) error {
+		streamedElements++
+
+		return nil
+	}))
+	require.Equal(t, 50, streamedElements)
+	require.NoError(t, importedMap.Diff(sourceMap.Root(), importedMap.Root(), func(testKey, DiffType, testValue, testValue) error {
+		return ierrors.New("imported map differs")
+	}))
+
+	// the imported map can be modified
+	require.True(t, lo.PanicOnErr(importedMap.Delete(testKey{3})))
+	require.NoError(t, sourceMap.Set(testKey{100}, testValueFromString("new")))
+	require.NoError(t, importedMap.Set(testKey{100}, testValueFromString("new")))
+	require.True(t, lo.PanicOnErr(sourceMap.Delete(testKey{3})))
+	require.Equal(t, sourceMap.Root(), importedMap.Root())
+
+	// sets are exported and imported in the same way
+	sourceSet := newAuthenticatedSet[[32]byte](mapdb.NewMapDB(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+	require.NoError(t, sourceSet.Add(testKey{'a'}))
+	require.NoError(t, sourceSet.Add(testKey{'b'}))
+	require.NoError(t, sourceSet.Commit())
+
+	snapshot.Reset()
+	require.NoError(t, sourceSet.ExportNodes(snapshot))
+
+	importedSet, err := ImportSetNodes(mapdb.NewMapDB(), snapshot, sourceSet.Root(),
+		typeutils.ByteArray32ToBytes,
+		typeutils.ByteArray32FromBytes,
+		testKey.Bytes,
+		testKeyFromBytes,
+	)
+	require.NoError(t, err)
+	require.Equal(t, sourceSet.Root(), importedSet.Root())
+	require.True(t, lo.PanicOnErr(importedSet.Has(testKey{'b'})))
+}
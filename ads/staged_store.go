@@ -0,0 +1,178 @@
+package ads
+
+import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
+)
+
+// ErrNotSupportedByStagedStore is returned when a staged store is used in a way that it does not support.
+var ErrNotSupportedByStagedStore = ierrors.New("operation not supported by staged store")
+
+// stagedMutations are the buffered mutations of a stagedStore (nil values mark deletions).
+type stagedMutations struct {
+	values map[string][]byte
+	mutex  sync.RWMutex
+}
+
+// stagedStore is a KVStore that buffers all mutations in memory until they are written to the underlying store in a
+// single batch.
+type stagedStore struct {
+	underlying kvstore.KVStore
+	realm      kvstore.Realm
+	mutations  *stagedMutations
+}
+
+// newStagedStore creates a new stagedStore on top of the given store.
+func newStagedStore(underlying kvstore.KVStore) *stagedStore {
+	return &stagedStore{
+		underlying: underlying,
+		realm:      kvstore.EmptyPrefix,
+		mutations: &stagedMutations{
+			values: make(map[string][]byte),
+		},
+	}
+}
+
+// WithRealm is a factory method for using the same underlying storage with a different realm.
+func (s *stagedStore) WithRealm(realm kvstore.Realm) (kvstore.KVStore, error) {
+	return &stagedStore{
+		underlying: s.underlying,
+		realm:      byteutils.ConcatBytes(realm),
+		mutations:  s.mutations,
+	}, nil
+}
+
+// WithExtendedRealm is a factory method for using the same underlying storage with a realm appended to existing one.
+func (s *stagedStore) WithExtendedRealm(realm kvstore.Realm) (kvstore.KVStore, error) {
+	return s.WithRealm(byteutils.ConcatBytes(s.realm, realm))
+}
+
+// Realm returns the configured realm.
+func (s *stagedStore) Realm() kvstore.Realm {
+	return byteutils.ConcatBytes(s.realm)
+}
+
+// Iterate is not supported by a stagedStore.
+func (s *stagedStore) Iterate(kvstore.KeyPrefix, kvstore.IteratorKeyValueConsumerFunc, ...kvstore.IterDirection) error {
+	return ierrors.Wrap(ErrNotSupportedByStagedStore, "failed to iterate")
+}
+
+// IterateKeys is not supported by a stagedStore.
+func (s *stagedStore) IterateKeys(kvstore.KeyPrefix, kvstore.IteratorKeyConsumerFunc, ...kvstore.IterDirection) error {
+	return ierrors.Wrap(ErrNotSupportedByStagedStore, "failed to iterate keys")
+}
+
+// Clear is not supported by a stagedStore.
+func (s *stagedStore) Clear() error {
+	return ierrors.Wrap(ErrNotSupportedByStagedStore, "failed to clear")
+}
+
+// Get gets the given key or nil if it doesn't exist or an error if an error occurred.
+func (s *stagedStore) Get(key kvstore.Key) (kvstore.Value, error) {
+	s.mutations.mutex.RLock()
+	value, isStaged := s.mutations.values[string(s.key(key))]
+	s.mutations.mutex.RUnlock()
+
+	if !isStaged {
+		return s.underlying.Get(s.key(key))
+	} else if value == nil {
+		return nil, kvstore.ErrKeyNotFound
+	}
+
+	return byteutils.ConcatBytes(value), nil
+}
+
+// Set sets the given key and value.
+func (s *stagedStore) Set(key kvstore.Key, value kvstore.Value) error {
+	s.mutations.mutex.Lock()
+	defer s.mutations.mutex.Unlock()
+
+	// copy into a non-nil slice, so that empty values are not mistaken for deletions
+	s.mutations.values[string(s.key(key))] = append(make([]byte, 0, len(value)), value...)
+
+	return nil
+}
+
+// Has checks whether the given key exists.
+func (s *stagedStore) Has(key kvstore.Key) (bool, error) {
+	s.mutations.mutex.RLock()
+	value, isStaged := s.mutations.values[string(s.key(key))]
+	s.mutations.mutex.RUnlock()
+
+	if !isStaged {
+		return s.underlying.Has(s.key(key))
+	}
+
+	return value != nil, nil
+}
+
+// Delete deletes the entry for the given key.
+func (s *stagedStore) Delete(key kvstore.Key) error {
+	s.mutations.mutex.Lock()
+	defer s.mutations.mutex.Unlock()
+
+	s.mutations.values[string(s.key(key))] = nil
+
+	return nil
+}
+
+// DeletePrefix is not supported by a stagedStore.
+func (s *stagedStore) DeletePrefix(kvstore.KeyPrefix) error {
+	return ierrors.Wrap(ErrNotSupportedByStagedStore, "failed to delete prefix")
+}
+
+// Flush does nothing, as the mutations are only written by WriteBatch.
+func (s *stagedStore) Flush() error {
+	return nil
+}
+
+// Close does nothing, as the underlying store is not owned by the stagedStore.
+func (s *stagedStore) Close() error {
+	return nil
+}
+
+// Batched is not supported by a stagedStore.
+func (s *stagedStore) Batched() (kvstore.BatchedMutations, error) {
+	return nil, ierrors.Wrap(ErrNotSupportedByStagedStore, "failed to create batch")
+}
+
+// WriteBatch writes all buffered mutations to the underlying store in a single batch.
+func (s *stagedStore) WriteBatch() error {
+	s.mutations.mutex.Lock()
+	defer s.mutations.mutex.Unlock()
+
+	batch, err := s.underlying.Batched()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to create batch")
+	}
+
+	for key, value := range s.mutations.values {
+		if value == nil {
+			err = batch.Delete([]byte(key))
+		} else {
+			err = batch.Set([]byte(key), value)
+		}
+
+		if err != nil {
+			batch.Cancel()
+
+			return ierrors.Wrapf(err, "failed to add mutation of key %x to batch", key)
+		}
+	}
+
+	if err = batch.Commit(); err != nil {
+		return ierrors.Wrap(err, "failed to commit batch")
+	}
+
+	s.mutations.values = make(map[string][]byte)
+
+	return nil
+}
+
+// key returns the key in the underlying store.
+func (s *stagedStore) key(key kvstore.Key) []byte {
+	return byteutils.ConcatBytes(s.realm, key)
+}
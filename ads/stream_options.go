@@ -0,0 +1,101 @@
+package ads
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// StreamOptions contains the options of a ranged stream over a Map or a Set.
+//
+// Elements are streamed in the order of their serialized keys. A cursor is the serialized key of an element, so a
+// stream can also be started at a specific key by passing the serialized key as the cursor. Streams seek to their
+// cursor if the underlying KVStore supports it (like the mapdb and rocksdb stores) - on other stores the elements
+// before the cursor are skipped. Note that the mapdb store still sorts all keys of the map for every page.
+type StreamOptions struct {
+	// cursor is the serialized key of the first element that is streamed.
+	cursor []byte
+
+	// prefix is the prefix of the serialized keys of the streamed elements.
+	prefix []byte
+
+	// limit is the maximum number of streamed elements (0 = unlimited).
+	limit int
+
+	// direction is the direction of the stream.
+	direction kvstore.IterDirection
+}
+
+// WithStreamCursor sets the cursor at which the stream starts (inclusive).
+func WithStreamCursor(cursor []byte) options.Option[StreamOptions] {
+	return func(o *StreamOptions) {
+		o.cursor = cursor
+	}
+}
+
+// WithStreamPrefix restricts the stream to the elements whose serialized keys start with the given prefix.
+func WithStreamPrefix(prefix []byte) options.Option[StreamOptions] {
+	return func(o *StreamOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithStreamLimit sets the maximum number of elements that are streamed (default: 0 - unlimited).
+func WithStreamLimit(limit int) options.Option[StreamOptions] {
+	return func(o *StreamOptions) {
+		o.limit = limit
+	}
+}
+
+// WithStreamDirection sets the direction of the stream (default: kvstore.IterDirectionForward).
+func WithStreamDirection(direction kvstore.IterDirection) options.Option[StreamOptions] {
+	return func(o *StreamOptions) {
+		o.direction = direction
+	}
+}
+
+// newStreamOptions creates the StreamOptions from the given options.
+func newStreamOptions(opts ...options.Option[StreamOptions]) *StreamOptions {
+	return options.Apply(&StreamOptions{
+		prefix:    kvstore.EmptyPrefix,
+		direction: kvstore.IterDirectionForward,
+	}, opts)
+}
+
+// keySeekingStore is implemented by the KVStores that can start an iteration at a given key (like the mapdb and rocksdb
+// stores of the kvstore package).
+type keySeekingStore interface {
+	IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc, iterDirection ...kvstore.IterDirection) error
+}
+
+// iterateKeys iterates over the keys of the given store that are part of the stream. It seeks to the cursor if the
+// store supports it (and skips the keys before the cursor otherwise).
+func (o *StreamOptions) iterateKeys(store kvstore.KVStore, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	if seekingStore, isSeekingStore := store.(keySeekingStore); isSeekingStore && o.cursor != nil {
+		return seekingStore.IterateKeysFrom(o.prefix, o.cursor, consumerFunc, o.direction)
+	}
+
+	return store.IterateKeys(o.prefix, func(key kvstore.Key) bool {
+		return o.isBeforeCursor(key) || consumerFunc(key)
+	}, o.direction)
+}
+
+// isBeforeCursor returns true if the given serialized key comes before the cursor in the direction of the stream.
+func (o *StreamOptions) isBeforeCursor(keyBytes []byte) bool {
+	return o.cursor != nil && o.isBefore(keyBytes, o.cursor)
+}
+
+// isBefore returns true if the first serialized key comes before the second one in the direction of the stream.
+func (o *StreamOptions) isBefore(keyBytes []byte, otherKeyBytes []byte) bool {
+	if o.direction == kvstore.IterDirectionBackward {
+		return bytes.Compare(keyBytes, otherKeyBytes) > 0
+	}
+
+	return bytes.Compare(keyBytes, otherKeyBytes) < 0
+}
+
+// contains returns true if the given serialized key is part of the stream (ignoring the limit).
+func (o *StreamOptions) contains(keyBytes []byte) bool {
+	return bytes.HasPrefix(keyBytes, o.prefix) && !o.isBeforeCursor(keyBytes)
+}
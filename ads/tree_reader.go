@@ -0,0 +1,54 @@
+package ads
+
+import (
+	"github.com/iotaledger/hive.go/ds/types"
+	"github.com/iotaledger/hive.go/ierrors"
+)
+
+// localTreeReader is a TreeReader for the committed nodes of an authenticatedMap.
+type localTreeReader[IdentifierType types.IdentifierType, K, V any] struct {
+	source *authenticatedMap[IdentifierType, K, V]
+
+	// lock defines whether the reader needs to acquire the lock of the map.
+	lock bool
+}
+
+// newLocalTreeReader creates a new localTreeReader.
+func newLocalTreeReader[IdentifierType types.IdentifierType, K, V any](source *authenticatedMap[IdentifierType, K, V], lock bool) *localTreeReader[IdentifierType, K, V] {
+	return &localTreeReader[IdentifierType, K, V]{
+		source: source,
+		lock:   lock,
+	}
+}
+
+// Node returns the preimage of the tree node with the given hash.
+func (l *localTreeReader[IdentifierType, K, V]) Node(hash []byte) (preimage []byte, err error) {
+	if l.lock {
+		l.source.mutex.RLock()
+		defer l.source.mutex.RUnlock()
+	}
+
+	if preimage, err = l.source.treeStore.Get(hash); err != nil {
+		return nil, ierrors.Wrapf(err, "failed to get node %x", hash)
+	}
+
+	return preimage, nil
+}
+
+// Leaf returns the key and the value bytes of the leaf with the given path and leaf data.
+func (l *localTreeReader[IdentifierType, K, V]) Leaf(path []byte, leafData []byte) (keyBytes []byte, valueBytes []byte, err error) {
+	if l.lock {
+		l.source.mutex.RLock()
+		defer l.source.mutex.RUnlock()
+	}
+
+	if keyBytes, err = l.source.keyPathsStore.Get(path); err != nil {
+		return nil, nil, ierrors.Wrapf(err, "failed to get key of path %x", path)
+	}
+
+	if valueBytes, err = l.source.resolveValue(keyBytes, leafData); err != nil {
+		return nil, nil, err
+	}
+
+	return keyBytes, valueBytes, nil
+}
@@ -0,0 +1,466 @@
+package ads
+
+import (
+	"encoding/binary"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/serializer/v2/byteutils"
+)
+
+const (
+	// changeNodeCreated marks a tree node that was written for the first time in a version.
+	changeNodeCreated byte = iota
+
+	// changeNodeOrphaned marks a tree node that was removed from the tree in a version.
+	changeNodeOrphaned
+
+	// changeNodeRevived marks an orphaned tree node that became part of the tree again in a version.
+	changeNodeRevived
+
+	// changeKeyAdded marks a raw key that was added in a version.
+	changeKeyAdded
+
+	// changeKeyRemoved marks a raw key that was removed in a version.
+	changeKeyRemoved
+
+	// changeValueCreated marks a hashed value that was written for the first time in a version.
+	changeValueCreated
+
+	// changeValueOrphaned marks a hashed value that was removed from the map in a version.
+	changeValueOrphaned
+
+	// changeValueRevived marks an orphaned hashed value that became part of the map again in a version.
+	changeValueRevived
+
+	// changeKeyPathCreated marks a key path (path -> key) that was written for the first time in a version.
+	changeKeyPathCreated
+
+	// changeKeyPathOrphaned marks a key path that was removed from the map in a version.
+	changeKeyPathOrphaned
+
+	// changeKeyPathRevived marks an orphaned key path that became part of the map again in a version.
+	changeKeyPathRevived
+)
+
+// ErrVersionNotFound is returned when a version is requested that was never committed or that was pruned already.
+var ErrVersionNotFound = ierrors.New("version not found")
+
+// versionedStore is a storage of immutable objects (tree nodes or hashed values) whose lifecycle is tracked by the
+// versionLog.
+type versionedStore struct {
+	// objects contains the stored objects (identifier -> object).
+	objects kvstore.KVStore
+
+	// orphans contains the version in which a still persisted object was removed (identifier -> version).
+	orphans kvstore.KVStore
+
+	// createdChange is the change type that marks the creation of an object.
+	createdChange byte
+
+	// orphanedChange is the change type that marks the removal of an object.
+	orphanedChange byte
+
+	// revivedChange is the change type that marks the revival of an orphaned object.
+	revivedChange byte
+}
+
+// versionLog keeps track of the changes of every committed version, so that tree nodes are only deleted once no
+// retained version needs them anymore and so that versions can be rolled back.
+type versionLog struct {
+	// nodes contains the tree nodes (hash -> preimage).
+	nodes *versionedStore
+
+	// values contains the hashed values (key | value hash -> value).
+	values *versionedStore
+
+	// keyPaths contains the keys of the leaves of the tree (path -> key).
+	keyPaths *versionedStore
+
+	// changes contains the changes of every version (version | changeType | identifier or key -> optional data).
+	changes kvstore.KVStore
+
+	// pendingVersion is the version that the current changes will be committed as.
+	pendingVersion uint64
+
+	// oldestVersion is the oldest version that is still retained.
+	oldestVersion uint64
+}
+
+// newVersionLog creates a new versionLog.
+func newVersionLog(nodes, nodeOrphans, values, valueOrphans, keyPaths, keyPathOrphans, changes kvstore.KVStore, latestVersion, oldestVersion uint64) *versionLog {
+	return &versionLog{
+		nodes: &versionedStore{
+			objects:        nodes,
+			orphans:        nodeOrphans,
+			createdChange:  changeNodeCreated,
+			orphanedChange: changeNodeOrphaned,
+			revivedChange:  changeNodeRevived,
+		},
+		values: &versionedStore{
+			objects:        values,
+			orphans:        valueOrphans,
+			createdChange:  changeValueCreated,
+			orphanedChange: changeValueOrphaned,
+			revivedChange:  changeValueRevived,
+		},
+		keyPaths: &versionedStore{
+			objects:        keyPaths,
+			orphans:        keyPathOrphans,
+			createdChange:  changeKeyPathCreated,
+			orphanedChange: changeKeyPathOrphaned,
+			revivedChange:  changeKeyPathRevived,
+		},
+		changes:        changes,
+		pendingVersion: latestVersion + 1,
+		oldestVersion:  oldestVersion,
+	}
+}
+
+// SetNode stores the given tree node and records its creation (or revival) in the pending version.
+func (v *versionLog) SetNode(hash, preimage []byte) error {
+	return v.setObject(v.nodes, hash, preimage)
+}
+
+// DeleteNode marks the given tree node as orphaned in the pending version (it is deleted once it gets pruned).
+func (v *versionLog) DeleteNode(hash []byte) error {
+	return v.deleteObject(v.nodes, hash)
+}
+
+// DiscardNode deletes a tree node that was created in the pending version and that is not needed by any version.
+func (v *versionLog) DiscardNode(hash []byte) error {
+	if err := v.nodes.objects.Delete(hash); err != nil {
+		return ierrors.Wrap(err, "failed to delete node")
+	}
+
+	return v.deleteChange(v.pendingVersion, changeNodeCreated, hash)
+}
+
+// SetValue stores the given hashed value and records its creation (or revival) in the pending version.
+func (v *versionLog) SetValue(identifier, value []byte) error {
+	return v.setObject(v.values, identifier, value)
+}
+
+// DeleteValue marks the given hashed value as orphaned in the pending version (it is deleted once it gets pruned).
+func (v *versionLog) DeleteValue(identifier []byte) error {
+	return v.deleteObject(v.values, identifier)
+}
+
+// SetKeyPath stores the key of the given path and records its creation (or revival) in the pending version.
+func (v *versionLog) SetKeyPath(path, keyBytes []byte) error {
+	return v.setObject(v.keyPaths, path, keyBytes)
+}
+
+// DeleteKeyPath marks the key of the given path as orphaned in the pending version.
+func (v *versionLog) DeleteKeyPath(path []byte) error {
+	return v.deleteObject(v.keyPaths, path)
+}
+
+// AddKey records that the given raw key was added in the pending version.
+func (v *versionLog) AddKey(keyBytes []byte) error {
+	return v.toggleKeyChange(keyBytes, changeKeyAdded, changeKeyRemoved)
+}
+
+// RemoveKey records that the given raw key was removed in the pending version.
+func (v *versionLog) RemoveKey(keyBytes []byte) error {
+	return v.toggleKeyChange(keyBytes, changeKeyRemoved, changeKeyAdded)
+}
+
+// RemovedKeys calls the callback for every raw key that was removed after the given version.
+func (v *versionLog) RemovedKeys(afterVersion uint64, callback func(keyBytes []byte) error) error {
+	for version := afterVersion + 1; version <= v.pendingVersion; version++ {
+		if err := v.forEachChange(version, func(changeType byte, key []byte, _ []byte) error {
+			if changeType != changeKeyRemoved {
+				return nil
+			}
+
+			return callback(key)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HasPendingChanges returns true if changes were recorded in the pending version.
+func (v *versionLog) HasPendingChanges() (hasChanges bool, err error) {
+	if err = v.changes.IterateKeys(versionKey(v.pendingVersion), func(kvstore.Key) bool {
+		hasChanges = true
+
+		return false
+	}); err != nil {
+		return false, ierrors.Wrapf(err, "failed to iterate over changes of version %d", v.pendingVersion)
+	}
+
+	return hasChanges, nil
+}
+
+// Rollback undoes all changes of the versions that are newer than the given version (including pending changes).
+func (v *versionLog) Rollback(targetVersion uint64, rawKeys kvstore.KVStore) error {
+	for version := v.pendingVersion; version > targetVersion; version-- {
+		if err := v.forEachChange(version, func(changeType byte, key []byte, data []byte) error {
+			return v.undoChange(changeType, key, data, rawKeys)
+		}); err != nil {
+			return ierrors.Wrapf(err, "failed to undo version %d", version)
+		}
+
+		if err := v.changes.DeletePrefix(versionKey(version)); err != nil {
+			return ierrors.Wrapf(err, "failed to delete changes of version %d", version)
+		}
+	}
+
+	v.pendingVersion = targetVersion + 1
+
+	return nil
+}
+
+// Prune deletes the objects that are not needed by the given version or any later version anymore.
+func (v *versionLog) Prune(oldestVersion uint64) error {
+	for version := v.oldestVersion + 1; version <= oldestVersion; version++ {
+		if err := v.forEachChange(version, func(changeType byte, identifier []byte, _ []byte) error {
+			var store *versionedStore
+			switch changeType {
+			case changeNodeOrphaned:
+				store = v.nodes
+			case changeValueOrphaned:
+				store = v.values
+			case changeKeyPathOrphaned:
+				store = v.keyPaths
+			default:
+				return nil
+			}
+
+			// objects that got revived in a later version are still needed
+			if orphanedVersion, isOrphaned, err := v.orphanedVersion(store, identifier); err != nil || !isOrphaned || orphanedVersion != version {
+				return err
+			}
+
+			return v.deleteObjectPermanently(store, identifier)
+		}); err != nil {
+			return ierrors.Wrapf(err, "failed to prune version %d", version)
+		}
+
+		if err := v.changes.DeletePrefix(versionKey(version)); err != nil {
+			return ierrors.Wrapf(err, "failed to delete changes of version %d", version)
+		}
+	}
+
+	v.oldestVersion = oldestVersion
+
+	return nil
+}
+
+// undoChange reverts a single change.
+func (v *versionLog) undoChange(changeType byte, key []byte, data []byte, rawKeys kvstore.KVStore) error {
+	switch changeType {
+	case changeNodeCreated:
+		return v.nodes.objects.Delete(key)
+	case changeNodeOrphaned:
+		return v.nodes.orphans.Delete(key)
+	case changeNodeRevived:
+		return v.undoRevival(v.nodes, key, data)
+	case changeValueCreated:
+		return v.values.objects.Delete(key)
+	case changeValueOrphaned:
+		return v.values.orphans.Delete(key)
+	case changeValueRevived:
+		return v.undoRevival(v.values, key, data)
+	case changeKeyPathCreated:
+		return v.keyPaths.objects.Delete(key)
+	case changeKeyPathOrphaned:
+		return v.keyPaths.orphans.Delete(key)
+	case changeKeyPathRevived:
+		return v.undoRevival(v.keyPaths, key, data)
+	case changeKeyAdded:
+		return rawKeys.Delete(key)
+	case changeKeyRemoved:
+		return rawKeys.Set(key, []byte{})
+	default:
+		return ierrors.Errorf("unknown change type %d", changeType)
+	}
+}
+
+// undoRevival marks the given object as orphaned again (or deletes it if it would have been pruned already).
+func (v *versionLog) undoRevival(store *versionedStore, identifier []byte, orphanedVersionBytes []byte) error {
+	orphanedVersion, _, err := versionFromBytes(orphanedVersionBytes)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to parse orphaned version")
+	}
+
+	if orphanedVersion <= v.oldestVersion {
+		return store.objects.Delete(identifier)
+	}
+
+	return store.orphans.Set(identifier, orphanedVersionBytes)
+}
+
+// setObject stores the given object and records its creation (or revival) in the pending version.
+func (v *versionLog) setObject(store *versionedStore, identifier, object []byte) error {
+	orphanedVersion, isOrphaned, err := v.orphanedVersion(store, identifier)
+	if err != nil {
+		return err
+	}
+
+	if isOrphaned {
+		if err = store.orphans.Delete(identifier); err != nil {
+			return ierrors.Wrap(err, "failed to delete orphan")
+		}
+
+		// the object was only orphaned in the pending version, so we can just forget about it
+		if orphanedVersion == v.pendingVersion {
+			return v.deleteChange(v.pendingVersion, store.orphanedChange, identifier)
+		}
+
+		return v.setChange(v.pendingVersion, store.revivedChange, identifier, versionKey(orphanedVersion))
+	}
+
+	if has, hasErr := store.objects.Has(identifier); hasErr != nil {
+		return ierrors.Wrap(hasErr, "failed to check if object exists")
+	} else if has {
+		return nil
+	}
+
+	if err = store.objects.Set(identifier, object); err != nil {
+		return ierrors.Wrap(err, "failed to store object")
+	}
+
+	return v.setChange(v.pendingVersion, store.createdChange, identifier, nil)
+}
+
+// deleteObject marks the given object as orphaned in the pending version.
+func (v *versionLog) deleteObject(store *versionedStore, identifier []byte) error {
+	if err := store.orphans.Set(identifier, versionKey(v.pendingVersion)); err != nil {
+		return ierrors.Wrap(err, "failed to store orphan")
+	}
+
+	return v.setChange(v.pendingVersion, store.orphanedChange, identifier, nil)
+}
+
+// toggleKeyChange records the given key change or cancels out the opposite change of the same pending version.
+func (v *versionLog) toggleKeyChange(keyBytes []byte, changeType byte, oppositeChangeType byte) error {
+	oppositeChangeKey := changeKey(v.pendingVersion, oppositeChangeType, keyBytes)
+
+	if has, err := v.changes.Has(oppositeChangeKey); err != nil {
+		return ierrors.Wrap(err, "failed to check for opposite key change")
+	} else if has {
+		return v.changes.Delete(oppositeChangeKey)
+	}
+
+	return v.setChange(v.pendingVersion, changeType, keyBytes, nil)
+}
+
+// forEachChange calls the callback for all changes of the given version.
+func (v *versionLog) forEachChange(version uint64, callback func(changeType byte, key []byte, data []byte) error) error {
+	type change struct {
+		changeType byte
+		key        []byte
+		data       []byte
+	}
+
+	// collect the changes first, so that the callback can modify the underlying storage
+	changes := make([]change, 0)
+	if err := v.changes.Iterate(versionKey(version), func(key kvstore.Key, value kvstore.Value) bool {
+		changes = append(changes, change{
+			changeType: key[8],
+			key:        byteutils.ConcatBytes(key[9:]),
+			data:       byteutils.ConcatBytes(value),
+		})
+
+		return true
+	}); err != nil {
+		return ierrors.Wrapf(err, "failed to iterate over changes of version %d", version)
+	}
+
+	for _, change := range changes {
+		if err := callback(change.changeType, change.key, change.data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orphanedVersion returns the version in which the given object was orphaned.
+func (v *versionLog) orphanedVersion(store *versionedStore, identifier []byte) (orphanedVersion uint64, isOrphaned bool, err error) {
+	orphanedVersionBytes, err := store.orphans.Get(identifier)
+	if err != nil {
+		if ierrors.Is(err, kvstore.ErrKeyNotFound) {
+			return 0, false, nil
+		}
+
+		return 0, false, ierrors.Wrap(err, "failed to retrieve orphan")
+	}
+
+	if orphanedVersion, _, err = versionFromBytes(orphanedVersionBytes); err != nil {
+		return 0, false, ierrors.Wrap(err, "failed to parse orphaned version")
+	}
+
+	return orphanedVersion, true, nil
+}
+
+// deleteObjectPermanently deletes the given object and its orphan marker.
+func (v *versionLog) deleteObjectPermanently(store *versionedStore, identifier []byte) error {
+	if err := store.objects.Delete(identifier); err != nil {
+		return ierrors.Wrap(err, "failed to delete object")
+	}
+
+	if err := store.orphans.Delete(identifier); err != nil {
+		return ierrors.Wrap(err, "failed to delete orphan")
+	}
+
+	return nil
+}
+
+// setChange stores a change.
+func (v *versionLog) setChange(version uint64, changeType byte, key []byte, data []byte) error {
+	if err := v.changes.Set(changeKey(version, changeType, key), lo.Cond(data == nil, []byte{}, data)); err != nil {
+		return ierrors.Wrap(err, "failed to store change")
+	}
+
+	return nil
+}
+
+// deleteChange deletes a change.
+func (v *versionLog) deleteChange(version uint64, changeType byte, key []byte) error {
+	if err := v.changes.Delete(changeKey(version, changeType, key)); err != nil {
+		return ierrors.Wrap(err, "failed to delete change")
+	}
+
+	return nil
+}
+
+// storedVersion returns the version stored in the given value (0 if it was not stored yet).
+func storedVersion(value *kvstore.TypedValue[uint64]) (uint64, error) {
+	version, err := value.Get()
+	if err != nil && !ierrors.Is(err, kvstore.ErrKeyNotFound) {
+		return 0, ierrors.Wrap(err, "failed to get version")
+	}
+
+	return version, nil
+}
+
+// changeKey returns the storage key of a change.
+func changeKey(version uint64, changeType byte, key []byte) []byte {
+	return byteutils.ConcatBytes(versionKey(version), []byte{changeType}, key)
+}
+
+// versionKey encodes a version in big endian, so that versions are iterated in order.
+func versionKey(version uint64) []byte {
+	return binary.BigEndian.AppendUint64(make([]byte, 0, 8), version)
+}
+
+// versionToBytes encodes a version using versionKey.
+func versionToBytes(version uint64) ([]byte, error) {
+	return versionKey(version), nil
+}
+
+// versionFromBytes decodes a version that was encoded with versionToBytes.
+func versionFromBytes(bytes []byte) (version uint64, consumed int, err error) {
+	if len(bytes) < 8 {
+		return 0, 0, ierrors.New("not enough bytes to decode version")
+	}
+
+	return binary.BigEndian.Uint64(bytes), 8, nil
+}
@@ -4,13 +4,15 @@ import (
 	"sync"
 
 	"github.com/iotaledger/hive.go/core/index"
+	"github.com/iotaledger/hive.go/ds/reactive"
 	"github.com/iotaledger/hive.go/ds/shrinkingmap"
 )
 
 // IndexedStorage is an evictable storage that stores storages for indexes.
 type IndexedStorage[IndexType index.Type, K comparable, V any] struct {
-	cache *shrinkingmap.ShrinkingMap[IndexType, *shrinkingmap.ShrinkingMap[K, V]]
-	mutex sync.Mutex
+	cache         *shrinkingmap.ShrinkingMap[IndexType, *shrinkingmap.ShrinkingMap[K, V]]
+	evictionState reactive.EvictionState[IndexType]
+	mutex         sync.Mutex
 }
 
 // NewIndexedStorage creates a new indexed storage.
@@ -20,6 +22,38 @@ func NewIndexedStorage[IndexType index.Type, K comparable, V any]() *IndexedStor
 	}
 }
 
+// AttachTo attaches the storage to the given EvictionState so that the storages of evicted indexes are dropped
+// automatically (and no longer created). Already evicted indexes are dropped immediately.
+func (e *IndexedStorage[IndexType, K, V]) AttachTo(evictionState reactive.EvictionState[IndexType]) (detach func()) {
+	e.mutex.Lock()
+	e.evictionState = evictionState
+	e.mutex.Unlock()
+
+	unsubscribe := evictionState.LastEvictedSlot().OnUpdate(func(_ IndexType, lastEvictedIndex IndexType) {
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+
+		e.cache.ForEach(func(index IndexType, _ *shrinkingmap.ShrinkingMap[K, V]) bool {
+			if isEvicted(index, lastEvictedIndex) {
+				e.cache.Delete(index)
+			}
+
+			return true
+		})
+	})
+
+	return func() {
+		unsubscribe()
+
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+
+		if e.evictionState == evictionState {
+			e.evictionState = nil
+		}
+	}
+}
+
 // Evict evicts the storage for the given index.
 func (e *IndexedStorage[IndexType, K, V]) Evict(index IndexType) (evictedStorage *shrinkingmap.ShrinkingMap[K, V]) {
 	e.mutex.Lock()
@@ -34,7 +68,8 @@ func (e *IndexedStorage[IndexType, K, V]) Evict(index IndexType) (evictedStorage
 	return
 }
 
-// Get returns the storage for the given index.
+// Get returns the storage for the given index (storages of evicted indexes are not created if the storage is attached
+// to an EvictionState).
 func (e *IndexedStorage[IndexType, K, V]) Get(index IndexType, createIfMissing ...bool) (storage *shrinkingmap.ShrinkingMap[K, V]) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
@@ -44,7 +79,7 @@ func (e *IndexedStorage[IndexType, K, V]) Get(index IndexType, createIfMissing .
 		return storage
 	}
 
-	if len(createIfMissing) == 0 || !createIfMissing[0] {
+	if len(createIfMissing) == 0 || !createIfMissing[0] || e.evictionState != nil && isEvicted(index, e.evictionState.LastEvictedSlot().Get()) {
 		return nil
 	}
 
@@ -81,3 +116,9 @@ func (e *IndexedStorage[IndexType, K, V]) Clear() (clearedKeys []IndexType, clea
 
 	return clearedKeys, clearedStorages
 }
+
+// isEvicted returns true if the given index is evicted when the given index was the last evicted one (following the
+// semantics of the EvictionState, where the zero index is only evicted once a later index was evicted).
+func isEvicted[IndexType index.Type](index IndexType, lastEvictedIndex IndexType) bool {
+	return lastEvictedIndex != 0 && index <= lastEvictedIndex
+}
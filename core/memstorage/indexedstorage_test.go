@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotaledger/hive.go/core/memstorage"
+	"github.com/iotaledger/hive.go/ds/reactive"
 	"github.com/iotaledger/hive.go/ds/shrinkingmap"
 )
 
@@ -51,3 +52,34 @@ func TestIndexedStorage(t *testing.T) {
 	// Test Get on the evicted index.
 	require.Nil(t, storage.Get(1, false))
 }
+
+func TestIndexedStorageAttachTo(t *testing.T) {
+	storage := memstorage.NewIndexedStorage[index, string, int]()
+	storage.Get(index(1), true)
+	storage.Get(index(3), true)
+	storage.Get(index(5), true)
+
+	evictionState := reactive.NewEvictionState[index]()
+	evictionState.Evict(1)
+
+	detach := storage.AttachTo(evictionState)
+
+	// Already evicted storages are dropped immediately.
+	require.Nil(t, storage.Get(index(1)))
+	require.NotNil(t, storage.Get(index(3)))
+
+	// Storages are dropped when their index is evicted.
+	evictionState.Evict(4)
+	require.Nil(t, storage.Get(index(3)))
+	require.NotNil(t, storage.Get(index(5)))
+
+	// Storages of evicted indexes are not created anymore.
+	require.Nil(t, storage.Get(index(2), true))
+	require.NotNil(t, storage.Get(index(6), true))
+
+	// Detached storages are no longer affected by the EvictionState.
+	detach()
+	evictionState.Evict(6)
+	require.NotNil(t, storage.Get(index(5)))
+	require.NotNil(t, storage.Get(index(2), true))
+}
@@ -0,0 +1,307 @@
+package bitset
+
+import (
+	"slices"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// Bitset is a compressed set of uint32 indexes with a dynamic length. It is organized like a roaring bitmap: the indexes
+// are grouped by their upper 16 bits into containers that store the lower 16 bits either as a sorted array (sparse
+// containers) or as a bitmap (dense containers). When it is encoded, consecutive runs of indexes are additionally
+// compressed into run containers.
+//
+// A Bitset is not safe for concurrent use.
+type Bitset struct {
+	// keys contains the upper 16 bits of the indexes of the containers in ascending order.
+	keys []uint16
+
+	// containers contains the containers that belong to the keys.
+	containers []container
+}
+
+// New creates a new Bitset that contains the given indexes.
+func New(indexes ...uint32) *Bitset {
+	b := new(Bitset)
+	for _, index := range indexes {
+		b.Set(index)
+	}
+
+	return b
+}
+
+// Set adds the given index to the Bitset and returns true if it was not set before.
+func (b *Bitset) Set(index uint32) (added bool) {
+	key, value := split(index)
+
+	position, exists := slices.BinarySearch(b.keys, key)
+	if !exists {
+		b.keys = slices.Insert(b.keys, position, key)
+		b.containers = slices.Insert[[]container, container](b.containers, position, newArrayContainer([]uint16{value}))
+
+		return true
+	}
+
+	b.containers[position], added = b.containers[position].add(value)
+
+	return added
+}
+
+// Clear removes the given index from the Bitset and returns true if it was set before.
+func (b *Bitset) Clear(index uint32) (removed bool) {
+	key, value := split(index)
+
+	position, exists := slices.BinarySearch(b.keys, key)
+	if !exists {
+		return false
+	}
+
+	if b.containers[position], removed = b.containers[position].remove(value); b.containers[position].cardinality() == 0 {
+		b.keys = slices.Delete(b.keys, position, position+1)
+		b.containers = slices.Delete(b.containers, position, position+1)
+	}
+
+	return removed
+}
+
+// Modify sets or clears the given index, given the supplied state.
+func (b *Bitset) Modify(index uint32, state bool) (modified bool) {
+	if state {
+		return b.Set(index)
+	}
+
+	return b.Clear(index)
+}
+
+// Has returns true if the given index is set.
+func (b *Bitset) Has(index uint32) bool {
+	key, value := split(index)
+
+	position, exists := slices.BinarySearch(b.keys, key)
+
+	return exists && b.containers[position].contains(value)
+}
+
+// Count returns the number of set indexes (population count).
+func (b *Bitset) Count() (count int) {
+	for _, c := range b.containers {
+		count += c.cardinality()
+	}
+
+	return count
+}
+
+// IsEmpty returns true if no index is set.
+func (b *Bitset) IsEmpty() bool {
+	return len(b.containers) == 0
+}
+
+// Min returns the smallest set index.
+func (b *Bitset) Min() (index uint32, exists bool) {
+	if b.IsEmpty() {
+		return 0, false
+	}
+
+	return join(b.keys[0], b.containers[0].minimum()), true
+}
+
+// Max returns the largest set index.
+func (b *Bitset) Max() (index uint32, exists bool) {
+	if b.IsEmpty() {
+		return 0, false
+	}
+
+	lastPosition := len(b.keys) - 1
+
+	return join(b.keys[lastPosition], b.containers[lastPosition].maximum()), true
+}
+
+// Reset removes all indexes from the Bitset.
+func (b *Bitset) Reset() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// ForEach calls the consumer for every set index in ascending order.
+// The iteration can be aborted by returning false in the consumer (in which case ForEach returns false).
+func (b *Bitset) ForEach(consumer func(index uint32) bool) bool {
+	for i, c := range b.containers {
+		key := b.keys[i]
+
+		if !c.forEach(func(value uint16) bool { return consumer(join(key, value)) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Indexes returns all set indexes in ascending order.
+func (b *Bitset) Indexes() []uint32 {
+	indexes := make([]uint32, 0, b.Count())
+	b.ForEach(func(index uint32) bool {
+		indexes = append(indexes, index)
+
+		return true
+	})
+
+	return indexes
+}
+
+// Equal returns true if both Bitsets contain the same indexes.
+func (b *Bitset) Equal(other *Bitset) bool {
+	if !slices.Equal(b.keys, other.keys) {
+		return false
+	}
+
+	for i, c := range b.containers {
+		if c.cardinality() != other.containers[i].cardinality() || xor.apply(c, other.containers[i]) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Bitset.
+func (b *Bitset) Clone() *Bitset {
+	cloned := &Bitset{
+		keys:       slices.Clone(b.keys),
+		containers: make([]container, len(b.containers)),
+	}
+
+	for i, c := range b.containers {
+		cloned.containers[i] = c.clone()
+	}
+
+	return cloned
+}
+
+// And returns a new Bitset that contains the indexes that are set in both Bitsets (intersection).
+func (b *Bitset) And(other *Bitset) *Bitset {
+	return b.combine(other, and)
+}
+
+// Or returns a new Bitset that contains the indexes that are set in any of the Bitsets (union).
+func (b *Bitset) Or(other *Bitset) *Bitset {
+	return b.combine(other, or)
+}
+
+// Xor returns a new Bitset that contains the indexes that are set in exactly one of the Bitsets (symmetric
+// difference).
+func (b *Bitset) Xor(other *Bitset) *Bitset {
+	return b.combine(other, xor)
+}
+
+// AndNot returns a new Bitset that contains the indexes that are set in this Bitset but not in the other one
+// (difference).
+func (b *Bitset) AndNot(other *Bitset) *Bitset {
+	return b.combine(other, andNot)
+}
+
+// Encode returns a serialized byte slice of the Bitset. The encoding is prefixed with the number of containers, and
+// every container is prefixed with its key, its kind and its length.
+func (b *Bitset) Encode() ([]byte, error) {
+	seri := serializer.NewSerializer()
+
+	seri.WriteNum(uint32(len(b.containers)), func(err error) error {
+		return ierrors.Wrap(err, "failed to write container count")
+	})
+
+	for i, c := range b.containers {
+		seri.WriteNum(b.keys[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to write container key")
+		})
+
+		encodeContainer(seri, c)
+	}
+
+	return seri.Serialize()
+}
+
+// Decode deserializes the given bytes into the Bitset.
+func (b *Bitset) Decode(bytes []byte) (bytesRead int, err error) {
+	deseri := serializer.NewDeserializer(bytes)
+
+	var containerCount uint32
+	deseri.ReadNum(&containerCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read container count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		// make sure that we do not allocate more memory than the remaining bytes can fill
+		if containerCount > uint32(len(deseri.RemainingBytes())/minEncodedContainerSize) {
+			return ierrors.Wrap(serializer.ErrDeserializationNotEnoughData, "failed to read containers")
+		}
+
+		return nil
+	})
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode Bitset")
+	}
+
+	keys, containers := make([]uint16, containerCount), make([]container, containerCount)
+	for i := range containers {
+		deseri.ReadNum(&keys[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to read container key")
+		})
+		deseri.AbortIf(func(_ error) error {
+			if i > 0 && keys[i] <= keys[i-1] {
+				return ierrors.Errorf("container keys are not in ascending order: %d <= %d", keys[i], keys[i-1])
+			}
+
+			return nil
+		})
+
+		containers[i] = decodeContainer(deseri)
+	}
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode Bitset")
+	}
+
+	b.keys, b.containers = keys, containers
+
+	return bytesRead, nil
+}
+
+// combine applies the given operation to both Bitsets and returns the result as a new Bitset.
+func (b *Bitset) combine(other *Bitset, op operation) *Bitset {
+	result := new(Bitset)
+
+	for i, j := 0, 0; i < len(b.keys) || j < len(other.keys); {
+		var key uint16
+		var left, right container
+
+		switch {
+		case j == len(other.keys) || (i < len(b.keys) && b.keys[i] < other.keys[j]):
+			key, left = b.keys[i], b.containers[i]
+			i++
+		case i == len(b.keys) || other.keys[j] < b.keys[i]:
+			key, right = other.keys[j], other.containers[j]
+			j++
+		default:
+			key, left, right = b.keys[i], b.containers[i], other.containers[j]
+			i++
+			j++
+		}
+
+		if combined := op.apply(left, right); combined != nil {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, combined)
+		}
+	}
+
+	return result
+}
+
+// split splits the given index into the key of its container and its value within the container.
+func split(index uint32) (key uint16, value uint16) {
+	return uint16(index >> 16), uint16(index)
+}
+
+// join joins the given container key and value into an index.
+func join(key uint16, value uint16) uint32 {
+	return uint32(key)<<16 | uint32(value)
+}
@@ -0,0 +1,197 @@
+package bitset_test
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/bitset"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+func TestBitset(t *testing.T) {
+	b := bitset.New()
+	require.True(t, b.IsEmpty())
+
+	_, exists := b.Min()
+	require.False(t, exists)
+
+	require.True(t, b.Set(3))
+	require.False(t, b.Set(3))
+	require.True(t, b.Set(1<<20))
+	require.True(t, b.Modify(70000, true))
+
+	require.True(t, b.Has(3))
+	require.True(t, b.Has(1<<20))
+	require.False(t, b.Has(4))
+	require.Equal(t, 3, b.Count())
+	require.Equal(t, []uint32{3, 70000, 1 << 20}, b.Indexes())
+
+	minIndex, _ := b.Min()
+	require.EqualValues(t, 3, minIndex)
+	maxIndex, _ := b.Max()
+	require.EqualValues(t, 1<<20, maxIndex)
+
+	require.True(t, b.Clear(70000))
+	require.False(t, b.Clear(70000))
+	require.False(t, b.Modify(70001, false))
+	require.Equal(t, []uint32{3, 1 << 20}, b.Indexes())
+
+	cloned := b.Clone()
+	require.True(t, cloned.Equal(b))
+	cloned.Set(5)
+	require.False(t, cloned.Equal(b))
+	require.False(t, b.Has(5))
+
+	b.Reset()
+	require.True(t, b.IsEmpty())
+	require.Zero(t, b.Count())
+}
+
+func TestBitsetContainerConversion(t *testing.T) {
+	b := bitset.New()
+
+	// fill a container beyond the array limit (so that it is converted to a bitmap) and back
+	for i := uint32(0); i < 10000; i++ {
+		require.True(t, b.Set(i*2))
+	}
+	require.Equal(t, 10000, b.Count())
+	require.True(t, b.Has(19998))
+	require.False(t, b.Has(19999))
+
+	maxIndex, _ := b.Max()
+	require.EqualValues(t, 19998, maxIndex)
+
+	for i := uint32(0); i < 9000; i++ {
+		require.True(t, b.Clear(i*2))
+	}
+	require.Equal(t, 1000, b.Count())
+
+	minIndex, _ := b.Min()
+	require.EqualValues(t, 18000, minIndex)
+
+	for i := uint32(9000); i < 10000; i++ {
+		require.True(t, b.Clear(i*2))
+	}
+	require.True(t, b.IsEmpty())
+}
+
+func TestBitsetOperations(t *testing.T) {
+	for _, density := range []int{100, 5000, 60000} {
+		left, leftIndexes := randomBitset(density)
+		right, rightIndexes := randomBitset(density)
+
+		require.Equal(t, expectedIndexes(leftIndexes, rightIndexes, func(inLeft, inRight bool) bool { return inLeft && inRight }), left.And(right).Indexes())
+		require.Equal(t, expectedIndexes(leftIndexes, rightIndexes, func(inLeft, inRight bool) bool { return inLeft || inRight }), left.Or(right).Indexes())
+		require.Equal(t, expectedIndexes(leftIndexes, rightIndexes, func(inLeft, inRight bool) bool { return inLeft != inRight }), left.Xor(right).Indexes())
+		require.Equal(t, expectedIndexes(leftIndexes, rightIndexes, func(inLeft, inRight bool) bool { return inLeft && !inRight }), left.AndNot(right).Indexes())
+
+		require.True(t, left.Xor(left).IsEmpty())
+		require.True(t, left.Or(left).Equal(left))
+		require.Equal(t, left.Count(), len(leftIndexes))
+	}
+}
+
+func TestBitsetForEach(t *testing.T) {
+	b := bitset.New(1, 2, 3, 100000)
+
+	visited := make([]uint32, 0)
+	require.False(t, b.ForEach(func(index uint32) bool {
+		visited = append(visited, index)
+
+		return index < 2
+	}))
+	require.Equal(t, []uint32{1, 2}, visited)
+
+	require.True(t, b.ForEach(func(uint32) bool { return true }))
+}
+
+func TestBitsetSerialization(t *testing.T) {
+	sparse, _ := randomBitset(100)
+	dense, _ := randomBitset(60000)
+
+	contiguous := bitset.New()
+	for i := uint32(0); i < 100000; i++ {
+		contiguous.Set(i)
+	}
+
+	for _, b := range []*bitset.Bitset{bitset.New(), sparse, dense, contiguous} {
+		bytes, err := b.Encode()
+		require.NoError(t, err)
+
+		decoded := new(bitset.Bitset)
+		bytesRead, err := decoded.Decode(bytes)
+		require.NoError(t, err)
+		require.Equal(t, len(bytes), bytesRead)
+		require.True(t, b.Equal(decoded))
+
+		serixBytes, err := serix.NewAPI().Encode(context.Background(), b)
+		require.NoError(t, err)
+		require.Equal(t, bytes, serixBytes)
+
+		serixDecoded := new(bitset.Bitset)
+		_, err = serix.NewAPI().Decode(context.Background(), serixBytes, serixDecoded)
+		require.NoError(t, err)
+		require.True(t, b.Equal(serixDecoded))
+
+		// truncated data is rejected
+		if len(bytes) > 4 {
+			_, err = new(bitset.Bitset).Decode(bytes[:len(bytes)-1])
+			require.Error(t, err)
+		}
+	}
+
+	// runs of consecutive indexes are compressed
+	bytes, err := contiguous.Encode()
+	require.NoError(t, err)
+	require.Less(t, len(bytes), 32)
+}
+
+func TestBitsetDecodeInvalid(t *testing.T) {
+	for _, invalidBytes := range [][]byte{
+		// more containers than the remaining bytes can hold
+		{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 1, 1, 0, 0, 0},
+		// unknown container kind
+		{1, 0, 0, 0, 0, 0, 9, 1, 0, 0, 0},
+		// unsorted values
+		{1, 0, 0, 0, 0, 0, 1, 2, 0, 2, 0, 1, 0},
+		// overlapping runs
+		{1, 0, 0, 0, 0, 0, 3, 2, 0, 0, 0, 5, 0, 3, 0, 1, 0},
+	} {
+		_, err := new(bitset.Bitset).Decode(invalidBytes)
+		require.Error(t, err)
+	}
+}
+
+func randomBitset(count int) (*bitset.Bitset, map[uint32]bool) {
+	b, indexes := bitset.New(), make(map[uint32]bool)
+	for i := 0; i < count; i++ {
+		//nolint:gosec // we do not care about weak random numbers here
+		index := uint32(rand.Intn(3 << 16))
+
+		b.Set(index)
+		indexes[index] = true
+	}
+
+	return b, indexes
+}
+
+func expectedIndexes(left, right map[uint32]bool, keep func(inLeft, inRight bool) bool) []uint32 {
+	expected := make([]uint32, 0)
+	for index := range left {
+		if keep(true, right[index]) {
+			expected = append(expected, index)
+		}
+	}
+	for index := range right {
+		if !left[index] && keep(false, true) {
+			expected = append(expected, index)
+		}
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+
+	return expected
+}
@@ -0,0 +1,373 @@
+package bitset
+
+import (
+	"math/bits"
+	"slices"
+)
+
+const (
+	// arrayContainerMaxSize is the maximum cardinality of an arrayContainer (larger containers use a bitmap).
+	arrayContainerMaxSize = 4096
+
+	// bitmapContainerWords is the number of words of a bitmapContainer (one bit for each of the 2^16 values).
+	bitmapContainerWords = (1 << 16) / 64
+)
+
+// container stores the lower 16 bits of the indexes that share the same upper 16 bits.
+type container interface {
+	// add adds the given value and returns the (potentially converted) container.
+	add(value uint16) (updated container, added bool)
+
+	// remove removes the given value and returns the (potentially converted) container.
+	remove(value uint16) (updated container, removed bool)
+
+	// contains returns true if the given value is part of the container.
+	contains(value uint16) bool
+
+	// cardinality returns the number of values in the container.
+	cardinality() int
+
+	// minimum returns the smallest value in the container.
+	minimum() uint16
+
+	// maximum returns the largest value in the container.
+	maximum() uint16
+
+	// forEach calls the consumer for every value in ascending order until it returns false.
+	forEach(consumer func(value uint16) bool) bool
+
+	// asBitmap returns the container as a bitmapContainer (that must not be modified).
+	asBitmap() *bitmapContainer
+
+	// clone returns a deep copy of the container.
+	clone() container
+}
+
+// region arrayContainer ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// arrayContainer is a container that stores its values in a sorted slice (used for sparse containers).
+type arrayContainer struct {
+	values []uint16
+}
+
+// newArrayContainer creates a new arrayContainer from the given sorted values.
+func newArrayContainer(values []uint16) *arrayContainer {
+	return &arrayContainer{values: values}
+}
+
+// add adds the given value and returns the (potentially converted) container.
+func (a *arrayContainer) add(value uint16) (updated container, added bool) {
+	position, exists := slices.BinarySearch(a.values, value)
+	if exists {
+		return a, false
+	}
+
+	if len(a.values) == arrayContainerMaxSize {
+		bitmap := a.toBitmap()
+		bitmap.add(value)
+
+		return bitmap, true
+	}
+
+	a.values = slices.Insert(a.values, position, value)
+
+	return a, true
+}
+
+// remove removes the given value and returns the container.
+func (a *arrayContainer) remove(value uint16) (updated container, removed bool) {
+	position, exists := slices.BinarySearch(a.values, value)
+	if !exists {
+		return a, false
+	}
+
+	a.values = slices.Delete(a.values, position, position+1)
+
+	return a, true
+}
+
+// contains returns true if the given value is part of the container.
+func (a *arrayContainer) contains(value uint16) bool {
+	_, exists := slices.BinarySearch(a.values, value)
+
+	return exists
+}
+
+// cardinality returns the number of values in the container.
+func (a *arrayContainer) cardinality() int {
+	return len(a.values)
+}
+
+// minimum returns the smallest value in the container.
+func (a *arrayContainer) minimum() uint16 {
+	return a.values[0]
+}
+
+// maximum returns the largest value in the container.
+func (a *arrayContainer) maximum() uint16 {
+	return a.values[len(a.values)-1]
+}
+
+// forEach calls the consumer for every value in ascending order until it returns false.
+func (a *arrayContainer) forEach(consumer func(value uint16) bool) bool {
+	for _, value := range a.values {
+		if !consumer(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// asBitmap returns the container as a bitmapContainer.
+func (a *arrayContainer) asBitmap() *bitmapContainer {
+	return a.toBitmap()
+}
+
+// clone returns a deep copy of the container.
+func (a *arrayContainer) clone() container {
+	return newArrayContainer(slices.Clone(a.values))
+}
+
+// toBitmap converts the container to a bitmapContainer.
+func (a *arrayContainer) toBitmap() *bitmapContainer {
+	bitmap := new(bitmapContainer)
+	for _, value := range a.values {
+		bitmap.add(value)
+	}
+
+	return bitmap
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region bitmapContainer //////////////////////////////////////////////////////////////////////////////////////////////
+
+// bitmapContainer is a container that stores its values as a fixed-size bitmap (used for dense containers).
+type bitmapContainer struct {
+	words [bitmapContainerWords]uint64
+	count int
+}
+
+// add adds the given value and returns the container.
+func (b *bitmapContainer) add(value uint16) (updated container, added bool) {
+	word, mask := value/64, uint64(1)<<(value%64)
+	if b.words[word]&mask != 0 {
+		return b, false
+	}
+
+	b.words[word] |= mask
+	b.count++
+
+	return b, true
+}
+
+// remove removes the given value and returns the (potentially converted) container.
+func (b *bitmapContainer) remove(value uint16) (updated container, removed bool) {
+	word, mask := value/64, uint64(1)<<(value%64)
+	if b.words[word]&mask == 0 {
+		return b, false
+	}
+
+	b.words[word] &^= mask
+	b.count--
+
+	if b.count <= arrayContainerMaxSize {
+		return b.toArray(), true
+	}
+
+	return b, true
+}
+
+// contains returns true if the given value is part of the container.
+func (b *bitmapContainer) contains(value uint16) bool {
+	return b.words[value/64]&(uint64(1)<<(value%64)) != 0
+}
+
+// cardinality returns the number of values in the container.
+func (b *bitmapContainer) cardinality() int {
+	return b.count
+}
+
+// minimum returns the smallest value in the container.
+func (b *bitmapContainer) minimum() uint16 {
+	for i, word := range b.words {
+		if word != 0 {
+			return uint16(i*64 + bits.TrailingZeros64(word))
+		}
+	}
+
+	return 0
+}
+
+// maximum returns the largest value in the container.
+func (b *bitmapContainer) maximum() uint16 {
+	for i := len(b.words) - 1; i >= 0; i-- {
+		if word := b.words[i]; word != 0 {
+			return uint16(i*64 + 63 - bits.LeadingZeros64(word))
+		}
+	}
+
+	return 0
+}
+
+// forEach calls the consumer for every value in ascending order until it returns false.
+func (b *bitmapContainer) forEach(consumer func(value uint16) bool) bool {
+	for i, word := range b.words {
+		for ; word != 0; word &= word - 1 {
+			if !consumer(uint16(i*64 + bits.TrailingZeros64(word))) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// asBitmap returns the container itself.
+func (b *bitmapContainer) asBitmap() *bitmapContainer {
+	return b
+}
+
+// clone returns a deep copy of the container.
+func (b *bitmapContainer) clone() container {
+	clonedBitmap := *b
+
+	return &clonedBitmap
+}
+
+// toArray converts the container to an arrayContainer.
+func (b *bitmapContainer) toArray() *arrayContainer {
+	values := make([]uint16, 0, b.count)
+	b.forEach(func(value uint16) bool {
+		values = append(values, value)
+
+		return true
+	})
+
+	return newArrayContainer(values)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region operations ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// operation is a set operation that can be applied to two containers.
+type operation struct {
+	// keep returns true if a value that is (or is not) part of the two operands is part of the result.
+	keep func(inLeft, inRight bool) bool
+
+	// combineWords applies the operation to two words of a bitmap.
+	combineWords func(left, right uint64) uint64
+}
+
+var (
+	// and is the intersection of two sets.
+	and = operation{
+		keep:         func(inLeft, inRight bool) bool { return inLeft && inRight },
+		combineWords: func(left, right uint64) uint64 { return left & right },
+	}
+
+	// or is the union of two sets.
+	or = operation{
+		keep:         func(inLeft, inRight bool) bool { return inLeft || inRight },
+		combineWords: func(left, right uint64) uint64 { return left | right },
+	}
+
+	// xor is the symmetric difference of two sets.
+	xor = operation{
+		keep:         func(inLeft, inRight bool) bool { return inLeft != inRight },
+		combineWords: func(left, right uint64) uint64 { return left ^ right },
+	}
+
+	// andNot is the difference of two sets.
+	andNot = operation{
+		keep:         func(inLeft, inRight bool) bool { return inLeft && !inRight },
+		combineWords: func(left, right uint64) uint64 { return left &^ right },
+	}
+)
+
+// apply applies the operation to the given containers (nil represents an empty container) and returns the resulting
+// container (nil if it is empty).
+func (o operation) apply(left, right container) container {
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case right == nil:
+		return cloneIf(o.keep(true, false), left)
+	case left == nil:
+		return cloneIf(o.keep(false, true), right)
+	}
+
+	leftArray, leftIsArray := left.(*arrayContainer)
+	rightArray, rightIsArray := right.(*arrayContainer)
+	if leftIsArray && rightIsArray {
+		return o.applyArrays(leftArray.values, rightArray.values)
+	}
+
+	leftBitmap, rightBitmap, result := left.asBitmap(), right.asBitmap(), new(bitmapContainer)
+	for i := range result.words {
+		result.words[i] = o.combineWords(leftBitmap.words[i], rightBitmap.words[i])
+		result.count += bits.OnesCount64(result.words[i])
+	}
+
+	return compact(result)
+}
+
+// applyArrays applies the operation to the values of two arrayContainers by merging them.
+func (o operation) applyArrays(left, right []uint16) container {
+	values := make([]uint16, 0)
+	for i, j := 0, 0; i < len(left) || j < len(right); {
+		switch {
+		case j == len(right) || (i < len(left) && left[i] < right[j]):
+			if o.keep(true, false) {
+				values = append(values, left[i])
+			}
+			i++
+		case i == len(left) || right[j] < left[i]:
+			if o.keep(false, true) {
+				values = append(values, right[j])
+			}
+			j++
+		default:
+			if o.keep(true, true) {
+				values = append(values, left[i])
+			}
+			i++
+			j++
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if len(values) > arrayContainerMaxSize {
+		return newArrayContainer(values).toBitmap()
+	}
+
+	return newArrayContainer(values)
+}
+
+// cloneIf returns a copy of the given container if the condition is true (and nil otherwise).
+func cloneIf(condition bool, c container) container {
+	if !condition {
+		return nil
+	}
+
+	return c.clone()
+}
+
+// compact returns the given bitmapContainer in its most compact representation (nil if it is empty).
+func compact(bitmap *bitmapContainer) container {
+	switch {
+	case bitmap.count == 0:
+		return nil
+	case bitmap.count <= arrayContainerMaxSize:
+		return bitmap.toArray()
+	default:
+		return bitmap
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,241 @@
+package bitset
+
+import (
+	"math/bits"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// containerKind is the kind of the encoded representation of a container.
+type containerKind = byte
+
+const (
+	// containerKindArray encodes a container as a length-prefixed list of its values.
+	containerKindArray containerKind = iota + 1
+
+	// containerKindBitmap encodes a container as a fixed-size bitmap.
+	containerKindBitmap
+
+	// containerKindRuns encodes a container as a length-prefixed list of runs of consecutive values.
+	containerKindRuns
+)
+
+const (
+	// minEncodedContainerSize is the size of the smallest encoded container (key, kind, length and a single value).
+	minEncodedContainerSize = 2 + 1 + 2 + 2
+
+	// encodedBitmapSize is the size of an encoded bitmap.
+	encodedBitmapSize = bitmapContainerWords * 8
+)
+
+// run is a sequence of consecutive values of a container.
+type run struct {
+	// start is the first value of the run.
+	start uint16
+
+	// lengthMinusOne is the number of values of the run minus one (so that a full container fits into an uint16).
+	lengthMinusOne uint16
+}
+
+// encodeContainer writes the given container to the Serializer using its most compact representation.
+func encodeContainer(seri *serializer.Serializer, c container) {
+	runs := containerRuns(c)
+
+	switch cardinality := c.cardinality(); {
+	case 4*len(runs) < encodedBitmapSize && 4*len(runs) < 2*cardinality:
+		seri.WriteByte(containerKindRuns, func(err error) error {
+			return ierrors.Wrap(err, "failed to write container kind")
+		})
+		seri.WriteNum(uint16(len(runs)), func(err error) error {
+			return ierrors.Wrap(err, "failed to write run count")
+		})
+
+		for _, r := range runs {
+			seri.WriteNum(r.start, func(err error) error {
+				return ierrors.Wrap(err, "failed to write run start")
+			})
+			seri.WriteNum(r.lengthMinusOne, func(err error) error {
+				return ierrors.Wrap(err, "failed to write run length")
+			})
+		}
+	case cardinality <= arrayContainerMaxSize:
+		seri.WriteByte(containerKindArray, func(err error) error {
+			return ierrors.Wrap(err, "failed to write container kind")
+		})
+		seri.WriteNum(uint16(cardinality), func(err error) error {
+			return ierrors.Wrap(err, "failed to write value count")
+		})
+
+		c.forEach(func(value uint16) bool {
+			seri.WriteNum(value, func(err error) error {
+				return ierrors.Wrap(err, "failed to write value")
+			})
+
+			return true
+		})
+	default:
+		seri.WriteByte(containerKindBitmap, func(err error) error {
+			return ierrors.Wrap(err, "failed to write container kind")
+		})
+
+		for _, word := range c.asBitmap().words {
+			seri.WriteNum(word, func(err error) error {
+				return ierrors.Wrap(err, "failed to write bitmap")
+			})
+		}
+	}
+}
+
+// decodeContainer reads a container from the given Deserializer.
+func decodeContainer(deseri *serializer.Deserializer) container {
+	var kind containerKind
+	deseri.ReadByte(&kind, func(err error) error {
+		return ierrors.Wrap(err, "failed to read container kind")
+	})
+
+	if _, err := deseri.Done(); err != nil {
+		return nil
+	}
+
+	switch kind {
+	case containerKindArray:
+		return decodeArray(deseri)
+	case containerKindBitmap:
+		return decodeBitmap(deseri)
+	case containerKindRuns:
+		return decodeRuns(deseri)
+	default:
+		deseri.AbortIf(func(_ error) error {
+			return ierrors.Errorf("unknown container kind %d", kind)
+		})
+
+		return nil
+	}
+}
+
+// decodeArray reads the values of an array container from the given Deserializer.
+func decodeArray(deseri *serializer.Deserializer) container {
+	var valueCount uint16
+	deseri.ReadNum(&valueCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read value count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		if valueCount == 0 || valueCount > arrayContainerMaxSize {
+			return ierrors.Errorf("invalid value count %d", valueCount)
+		}
+
+		return nil
+	})
+
+	if _, err := deseri.Done(); err != nil {
+		return nil
+	}
+
+	values := make([]uint16, valueCount)
+	for i := range values {
+		deseri.ReadNum(&values[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to read value")
+		})
+		deseri.AbortIf(func(_ error) error {
+			if i > 0 && values[i] <= values[i-1] {
+				return ierrors.Errorf("values are not in ascending order: %d <= %d", values[i], values[i-1])
+			}
+
+			return nil
+		})
+	}
+
+	return newArrayContainer(values)
+}
+
+// decodeBitmap reads the words of a bitmap container from the given Deserializer.
+func decodeBitmap(deseri *serializer.Deserializer) container {
+	bitmap := new(bitmapContainer)
+	for i := range bitmap.words {
+		deseri.ReadNum(&bitmap.words[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to read bitmap")
+		})
+	}
+
+	if _, err := deseri.Done(); err != nil {
+		return nil
+	}
+
+	for _, word := range bitmap.words {
+		bitmap.count += bits.OnesCount64(word)
+	}
+
+	deseri.AbortIf(func(_ error) error {
+		if bitmap.count == 0 {
+			return ierrors.New("empty bitmap")
+		}
+
+		return nil
+	})
+
+	return compact(bitmap)
+}
+
+// decodeRuns reads the runs of a run container from the given Deserializer.
+func decodeRuns(deseri *serializer.Deserializer) container {
+	var runCount uint16
+	deseri.ReadNum(&runCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read run count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		if runCount == 0 {
+			return ierrors.New("invalid run count 0")
+		}
+
+		return nil
+	})
+
+	bitmap := new(bitmapContainer)
+	nextStart := 0
+	for i := 0; i < int(runCount); i++ {
+		var r run
+		deseri.ReadNum(&r.start, func(err error) error {
+			return ierrors.Wrap(err, "failed to read run start")
+		})
+		deseri.ReadNum(&r.lengthMinusOne, func(err error) error {
+			return ierrors.Wrap(err, "failed to read run length")
+		})
+		deseri.AbortIf(func(_ error) error {
+			if int(r.start) < nextStart || int(r.start)+int(r.lengthMinusOne) > 0xFFFF {
+				return ierrors.Errorf("invalid run [%d, %d]", r.start, int(r.start)+int(r.lengthMinusOne))
+			}
+
+			return nil
+		})
+
+		if _, err := deseri.Done(); err != nil {
+			return nil
+		}
+
+		for value := int(r.start); value <= int(r.start)+int(r.lengthMinusOne); value++ {
+			bitmap.add(uint16(value))
+		}
+
+		// runs must be separated by at least one value (otherwise they would form a single run)
+		nextStart = int(r.start) + int(r.lengthMinusOne) + 2
+	}
+
+	return compact(bitmap)
+}
+
+// containerRuns returns the runs of consecutive values of the given container.
+func containerRuns(c container) []run {
+	runs := make([]run, 0)
+	c.forEach(func(value uint16) bool {
+		if lastRun := len(runs) - 1; lastRun >= 0 && int(runs[lastRun].start)+int(runs[lastRun].lengthMinusOne)+1 == int(value) {
+			runs[lastRun].lengthMinusOne++
+		} else {
+			runs = append(runs, run{start: value})
+		}
+
+		return true
+	})
+
+	return runs
+}
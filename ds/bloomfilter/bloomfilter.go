@@ -0,0 +1,291 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// region BloomFilter //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// BloomFilter is a thread-safe probabilistic set with a fixed capacity that tells if an item was definitely not added
+// or probably added before (with the configured false-positive rate).
+type BloomFilter struct {
+	// filter is the underlying (unsynchronized) filter.
+	filter *filter
+
+	// mutex is used to synchronize access to the filter.
+	mutex sync.RWMutex
+}
+
+// New creates a new BloomFilter that holds up to capacity items with the given false-positive rate.
+func New(capacity uint64, falsePositiveRate float64) *BloomFilter {
+	return &BloomFilter{
+		filter: newFilter(capacity, falsePositiveRate),
+	}
+}
+
+// Add adds the given item to the BloomFilter and returns true if it was not (probably) contained before.
+func (b *BloomFilter) Add(item []byte) (added bool) {
+	h1, h2 := hashItem(item)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.filter.contains(h1, h2) {
+		return false
+	}
+
+	b.filter.add(h1, h2)
+
+	return true
+}
+
+// Contains returns true if the given item was (probably) added to the BloomFilter.
+func (b *BloomFilter) Contains(item []byte) bool {
+	h1, h2 := hashItem(item)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.filter.contains(h1, h2)
+}
+
+// Count returns the number of items that were added to the BloomFilter.
+func (b *BloomFilter) Count() uint64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.filter.count
+}
+
+// Capacity returns the number of items that the BloomFilter can hold with the configured false-positive rate.
+func (b *BloomFilter) Capacity() uint64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.filter.capacity
+}
+
+// Reset removes all items from the BloomFilter.
+func (b *BloomFilter) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.filter.reset()
+}
+
+// Encode returns a serialized byte slice of the BloomFilter.
+func (b *BloomFilter) Encode() ([]byte, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	seri := serializer.NewSerializer()
+	b.filter.encode(seri)
+
+	return seri.Serialize()
+}
+
+// Decode deserializes the given bytes into the BloomFilter.
+func (b *BloomFilter) Decode(bytes []byte) (bytesRead int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	deseri := serializer.NewDeserializer(bytes)
+	decodedFilter := decodeFilter(deseri)
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode BloomFilter")
+	}
+
+	b.filter = decodedFilter
+
+	return bytesRead, nil
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region filter ///////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// filter is the unsynchronized Bloom filter that is used by all filter types of this package.
+type filter struct {
+	// capacity is the number of items that the filter can hold with the configured false-positive rate.
+	capacity uint64
+
+	// falsePositiveRate is the false-positive rate the filter was configured for.
+	falsePositiveRate float64
+
+	// count is the number of items that were added to the filter.
+	count uint64
+
+	// hashCount is the number of bits that are set for every item.
+	hashCount uint64
+
+	// bitCount is the number of bits of the filter.
+	bitCount uint64
+
+	// bits contains the bits of the filter.
+	bits []uint64
+}
+
+// newFilter creates a new filter that holds up to capacity items with the given false-positive rate.
+func newFilter(capacity uint64, falsePositiveRate float64) *filter {
+	if capacity == 0 {
+		panic("capacity must be greater than 0")
+	}
+
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic(fmt.Sprintf("false-positive rate must be in (0, 1) but is %f", falsePositiveRate))
+	}
+
+	bitCount := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+
+	return &filter{
+		capacity:          capacity,
+		falsePositiveRate: falsePositiveRate,
+		hashCount:         max(1, uint64(math.Round(float64(bitCount)/float64(capacity)*math.Ln2))),
+		bitCount:          bitCount,
+		bits:              make([]uint64, (bitCount+63)/64),
+	}
+}
+
+// add adds the item with the given hashes to the filter.
+func (f *filter) add(h1, h2 uint64) {
+	for i := uint64(0); i < f.hashCount; i++ {
+		bit := (h1 + i*h2) % f.bitCount
+
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+
+	f.count++
+}
+
+// contains returns true if the item with the given hashes was (probably) added to the filter.
+func (f *filter) contains(h1, h2 uint64) bool {
+	for i := uint64(0); i < f.hashCount; i++ {
+		if bit := (h1 + i*h2) % f.bitCount; f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isFull returns true if the filter reached its capacity.
+func (f *filter) isFull() bool {
+	return f.count >= f.capacity
+}
+
+// reset removes all items from the filter.
+func (f *filter) reset() {
+	clear(f.bits)
+
+	f.count = 0
+}
+
+// encode writes the filter to the given Serializer.
+func (f *filter) encode(seri *serializer.Serializer) {
+	seri.WriteNum(f.capacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to write capacity")
+	})
+	seri.WriteNum(f.falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to write false-positive rate")
+	})
+	seri.WriteNum(f.count, func(err error) error {
+		return ierrors.Wrap(err, "failed to write count")
+	})
+	seri.WriteNum(f.hashCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to write hash count")
+	})
+	seri.WriteNum(f.bitCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to write bit count")
+	})
+
+	for _, word := range f.bits {
+		seri.WriteNum(word, func(err error) error {
+			return ierrors.Wrap(err, "failed to write bits")
+		})
+	}
+}
+
+// decodeFilter reads a filter from the given Deserializer.
+func decodeFilter(deseri *serializer.Deserializer) *filter {
+	f := new(filter)
+
+	deseri.ReadNum(&f.capacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to read capacity")
+	})
+	deseri.ReadNum(&f.falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to read false-positive rate")
+	})
+	deseri.ReadNum(&f.count, func(err error) error {
+		return ierrors.Wrap(err, "failed to read count")
+	})
+	deseri.ReadNum(&f.hashCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read hash count")
+	})
+	deseri.ReadNum(&f.bitCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read bit count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		if f.capacity == 0 || f.hashCount == 0 || f.bitCount == 0 {
+			return ierrors.New("invalid filter parameters")
+		}
+
+		// make sure that we do not allocate more memory than the remaining bytes can fill
+		if wordCount := (f.bitCount + 63) / 64; wordCount > uint64(len(deseri.RemainingBytes())/8) {
+			return ierrors.Wrap(serializer.ErrDeserializationNotEnoughData, "failed to read bits")
+		}
+
+		return nil
+	})
+
+	if _, err := deseri.Done(); err != nil {
+		return f
+	}
+
+	f.bits = make([]uint64, (f.bitCount+63)/64)
+	for i := range f.bits {
+		deseri.ReadNum(&f.bits[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to read bits")
+		})
+	}
+
+	return f
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region utils ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// hashItem returns the two (stable) hashes of the given item that are used to derive its positions in a filter.
+func hashItem(item []byte) (h1, h2 uint64) {
+	hash := fnv.New128a()
+	_, _ = hash.Write(item)
+
+	sum := hash.Sum(nil)
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[8+i])
+	}
+
+	// make sure that the second hash is odd so the derived positions do not collapse
+	return mix(h1), mix(h2) | 1
+}
+
+// mix spreads the entropy of the given hash over all of its bits (the FNV hash mixes its lower bits poorly).
+func mix(hash uint64) uint64 {
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+
+	return hash
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
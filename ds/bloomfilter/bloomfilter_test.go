@@ -0,0 +1,79 @@
+package bloomfilter_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/bloomfilter"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+func TestBloomFilter(t *testing.T) {
+	filter := bloomfilter.New(10000, 0.01)
+	require.Equal(t, uint64(10000), filter.Capacity())
+
+	// items that are falsely reported as contained are not added
+	added := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Add(item(i)) {
+			added++
+		}
+	}
+	require.Equal(t, uint64(added), filter.Count())
+	require.Greater(t, added, 9900)
+
+	// there are no false negatives
+	for i := 0; i < 10000; i++ {
+		require.True(t, filter.Contains(item(i)))
+		require.False(t, filter.Add(item(i)))
+	}
+
+	require.InDelta(t, 0.01, falsePositiveRate(filter.Contains), 0.01)
+
+	filter.Reset()
+	require.Equal(t, uint64(0), filter.Count())
+	require.False(t, filter.Contains(item(1)))
+}
+
+func TestBloomFilterSerialization(t *testing.T) {
+	filter := bloomfilter.New(1000, 0.01)
+	for i := 0; i < 500; i++ {
+		filter.Add(item(i))
+	}
+
+	bytes, err := serix.NewAPI().Encode(context.Background(), filter)
+	require.NoError(t, err)
+
+	var decoded *bloomfilter.BloomFilter
+	bytesRead, err := serix.NewAPI().Decode(context.Background(), bytes, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, len(bytes), bytesRead)
+
+	require.Equal(t, filter.Count(), decoded.Count())
+	for i := 0; i < 500; i++ {
+		require.True(t, decoded.Contains(item(i)))
+	}
+
+	_, err = bloomfilter.New(1, 0.5).Decode(bytes[:len(bytes)-1])
+	require.Error(t, err)
+}
+
+// item returns the bytes of the item with the given index.
+func item(index int) []byte {
+	return binary.LittleEndian.AppendUint64(nil, uint64(index))
+}
+
+// falsePositiveRate measures the false-positive rate of the given contains function for items that were not added.
+func falsePositiveRate(contains func([]byte) bool) float64 {
+	falsePositives := 0
+	for i := 1000000; i < 1100000; i++ {
+		if contains(item(i)) {
+			falsePositives++
+		}
+	}
+
+	return float64(falsePositives) / 100000
+}
@@ -0,0 +1,366 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// ErrFilterFull is returned when an item can not be added because the filter reached its capacity.
+var ErrFilterFull = ierrors.New("filter is full")
+
+const (
+	// bucketSize is the number of fingerprints that are stored in every bucket of a CuckooFilter.
+	bucketSize = 4
+
+	// maxLoadFactor is the load factor that a CuckooFilter can reliably reach with the chosen bucket size.
+	maxLoadFactor = 0.95
+
+	// maxKicks is the number of fingerprints that are relocated before an insertion into a CuckooFilter gives up.
+	maxKicks = 500
+)
+
+// CuckooFilter is a thread-safe probabilistic set that (unlike a Bloom filter) supports the deletion of items. It
+// stores a fingerprint of every item in one of two candidate buckets, so the size of the fingerprints determines the
+// false-positive rate.
+type CuckooFilter struct {
+	// buckets contains the fingerprints of all buckets (bucketSize consecutive entries per bucket, 0 marks an empty
+	// entry).
+	buckets []uint32
+
+	// bucketMask is the mask that maps a hash to a bucket index (the number of buckets is a power of 2).
+	bucketMask uint64
+
+	// fingerprintBits is the number of bits of every fingerprint.
+	fingerprintBits uint8
+
+	// count is the number of items that are contained in the CuckooFilter.
+	count uint64
+
+	// victim holds the fingerprint that could not be relocated during the last insertion (if the filter is full).
+	victim *victim
+
+	// mutex is used to synchronize access to the buckets.
+	mutex sync.RWMutex
+}
+
+// NewCuckoo creates a new CuckooFilter that holds up to capacity items with the given false-positive rate.
+func NewCuckoo(capacity uint64, falsePositiveRate float64) *CuckooFilter {
+	if capacity == 0 {
+		panic("capacity must be greater than 0")
+	}
+
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic(fmt.Sprintf("false-positive rate must be in (0, 1) but is %f", falsePositiveRate))
+	}
+
+	// a lookup compares 2 * bucketSize fingerprints, so every fingerprint needs log2(2 * bucketSize / rate) bits
+	fingerprintBits := uint8(min(32, math.Ceil(math.Log2(2*bucketSize/falsePositiveRate))))
+
+	bucketCount := uint64(1) << bits.Len64(uint64(math.Ceil(float64(capacity)/(bucketSize*maxLoadFactor)))-1)
+
+	return &CuckooFilter{
+		buckets:         make([]uint32, bucketCount*bucketSize),
+		bucketMask:      bucketCount - 1,
+		fingerprintBits: fingerprintBits,
+	}
+}
+
+// Add adds the given item to the CuckooFilter and returns true if it was not (probably) contained before. It returns
+// ErrFilterFull if the filter reached its capacity.
+//
+// Note: Items that are falsely reported as contained are not added, so deleting them later might delete a different
+// item with the same fingerprint.
+func (c *CuckooFilter) Add(item []byte) (added bool, err error) {
+	h1, h2 := hashItem(item)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fingerprint, index1, index2 := c.locate(h1, h2)
+	if c.contains(fingerprint, index1, index2) {
+		return false, nil
+	}
+
+	if c.victim != nil {
+		return false, ErrFilterFull
+	}
+
+	if !c.insertIntoBucket(index1, fingerprint) && !c.insertIntoBucket(index2, fingerprint) {
+		if rand.Intn(2) == 0 {
+			c.relocate(index1, fingerprint)
+		} else {
+			c.relocate(index2, fingerprint)
+		}
+	}
+
+	c.count++
+
+	return true, nil
+}
+
+// Contains returns true if the given item was (probably) added to the CuckooFilter.
+func (c *CuckooFilter) Contains(item []byte) bool {
+	h1, h2 := hashItem(item)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.contains(c.locate(h1, h2))
+}
+
+// Delete removes the given item from the CuckooFilter and returns true if it was (probably) contained.
+func (c *CuckooFilter) Delete(item []byte) (deleted bool) {
+	h1, h2 := hashItem(item)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fingerprint, index1, index2 := c.locate(h1, h2)
+	if c.deleteFromBucket(index1, fingerprint) || c.deleteFromBucket(index2, fingerprint) {
+		// the freed entry allows to store the victim again
+		if c.victim != nil {
+			homelessVictim := c.victim
+			c.victim = nil
+
+			c.relocate(homelessVictim.index, homelessVictim.fingerprint)
+		}
+	} else if c.victim != nil && c.victim.fingerprint == fingerprint && (c.victim.index == index1 || c.victim.index == index2) {
+		c.victim = nil
+	} else {
+		return false
+	}
+
+	c.count--
+
+	return true
+}
+
+// Count returns the number of items that are contained in the CuckooFilter.
+func (c *CuckooFilter) Count() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.count
+}
+
+// Reset removes all items from the CuckooFilter.
+func (c *CuckooFilter) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	clear(c.buckets)
+	c.count = 0
+	c.victim = nil
+}
+
+// Encode returns a serialized byte slice of the CuckooFilter.
+func (c *CuckooFilter) Encode() ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	seri := serializer.NewSerializer()
+	seri.WriteNum(c.fingerprintBits, func(err error) error {
+		return ierrors.Wrap(err, "failed to write fingerprint bits")
+	})
+	seri.WriteNum(uint64(len(c.buckets)/bucketSize), func(err error) error {
+		return ierrors.Wrap(err, "failed to write bucket count")
+	})
+	seri.WriteNum(c.count, func(err error) error {
+		return ierrors.Wrap(err, "failed to write count")
+	})
+	seri.WriteBool(c.victim != nil, func(err error) error {
+		return ierrors.Wrap(err, "failed to write victim flag")
+	})
+
+	if c.victim != nil {
+		seri.WriteNum(c.victim.index, func(err error) error {
+			return ierrors.Wrap(err, "failed to write victim index")
+		})
+		seri.WriteNum(c.victim.fingerprint, func(err error) error {
+			return ierrors.Wrap(err, "failed to write victim fingerprint")
+		})
+	}
+
+	for _, fingerprint := range c.buckets {
+		seri.WriteNum(fingerprint, func(err error) error {
+			return ierrors.Wrap(err, "failed to write buckets")
+		})
+	}
+
+	return seri.Serialize()
+}
+
+// Decode deserializes the given bytes into the CuckooFilter.
+func (c *CuckooFilter) Decode(bytes []byte) (bytesRead int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var fingerprintBits uint8
+	var bucketCount, count uint64
+	var hasVictim bool
+	var decodedVictim *victim
+
+	deseri := serializer.NewDeserializer(bytes)
+	deseri.ReadNum(&fingerprintBits, func(err error) error {
+		return ierrors.Wrap(err, "failed to read fingerprint bits")
+	})
+	deseri.ReadNum(&bucketCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read bucket count")
+	})
+	deseri.ReadNum(&count, func(err error) error {
+		return ierrors.Wrap(err, "failed to read count")
+	})
+	deseri.ReadBool(&hasVictim, func(err error) error {
+		return ierrors.Wrap(err, "failed to read victim flag")
+	})
+
+	if hasVictim {
+		decodedVictim = new(victim)
+
+		deseri.ReadNum(&decodedVictim.index, func(err error) error {
+			return ierrors.Wrap(err, "failed to read victim index")
+		})
+		deseri.ReadNum(&decodedVictim.fingerprint, func(err error) error {
+			return ierrors.Wrap(err, "failed to read victim fingerprint")
+		})
+	}
+
+	deseri.AbortIf(func(_ error) error {
+		if fingerprintBits == 0 || fingerprintBits > 32 || bucketCount == 0 || bucketCount&(bucketCount-1) != 0 {
+			return ierrors.New("invalid CuckooFilter parameters")
+		}
+
+		if decodedVictim != nil && (decodedVictim.index >= bucketCount || decodedVictim.fingerprint == 0) {
+			return ierrors.New("invalid CuckooFilter victim")
+		}
+
+		// make sure that we do not allocate more memory than the remaining bytes can fill
+		if bucketCount > uint64(len(deseri.RemainingBytes())/(4*bucketSize)) {
+			return ierrors.Wrap(serializer.ErrDeserializationNotEnoughData, "failed to read buckets")
+		}
+
+		return nil
+	})
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode CuckooFilter")
+	}
+
+	buckets := make([]uint32, bucketCount*bucketSize)
+	for i := range buckets {
+		deseri.ReadNum(&buckets[i], func(err error) error {
+			return ierrors.Wrap(err, "failed to read buckets")
+		})
+	}
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode CuckooFilter")
+	}
+
+	c.buckets = buckets
+	c.bucketMask = bucketCount - 1
+	c.fingerprintBits = fingerprintBits
+	c.count = count
+	c.victim = decodedVictim
+
+	return bytesRead, nil
+}
+
+// locate returns the fingerprint and the candidate bucket indexes of the item with the given hashes.
+func (c *CuckooFilter) locate(h1, h2 uint64) (fingerprint uint32, index1, index2 uint64) {
+	// the fingerprint 0 marks an empty entry
+	if fingerprint = uint32(h2 >> (64 - c.fingerprintBits)); fingerprint == 0 {
+		fingerprint = 1
+	}
+
+	index1 = h1 & c.bucketMask
+
+	return fingerprint, index1, c.alternativeIndex(index1, fingerprint)
+}
+
+// contains returns true if the given fingerprint is stored in one of the given buckets.
+func (c *CuckooFilter) contains(fingerprint uint32, index1, index2 uint64) bool {
+	if c.victim != nil && c.victim.fingerprint == fingerprint && (c.victim.index == index1 || c.victim.index == index2) {
+		return true
+	}
+
+	for _, index := range [2]uint64{index1, index2} {
+		for _, storedFingerprint := range c.bucket(index) {
+			if storedFingerprint == fingerprint {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// relocate inserts the given fingerprint into the given bucket by relocating other fingerprints to their alternative
+// bucket (the last homeless fingerprint becomes the victim if the relocation fails).
+func (c *CuckooFilter) relocate(index uint64, fingerprint uint32) {
+	for i := 0; i < maxKicks; i++ {
+		bucket := c.bucket(index)
+
+		slot := rand.Intn(bucketSize)
+		fingerprint, bucket[slot] = bucket[slot], fingerprint
+
+		if index = c.alternativeIndex(index, fingerprint); c.insertIntoBucket(index, fingerprint) {
+			return
+		}
+	}
+
+	c.victim = &victim{index: index, fingerprint: fingerprint}
+}
+
+// insertIntoBucket stores the given fingerprint in the given bucket and returns true if it had a free entry.
+func (c *CuckooFilter) insertIntoBucket(index uint64, fingerprint uint32) bool {
+	bucket := c.bucket(index)
+	for i, storedFingerprint := range bucket {
+		if storedFingerprint == 0 {
+			bucket[i] = fingerprint
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteFromBucket removes the given fingerprint from the given bucket and returns true if it was found.
+func (c *CuckooFilter) deleteFromBucket(index uint64, fingerprint uint32) bool {
+	bucket := c.bucket(index)
+	for i, storedFingerprint := range bucket {
+		if storedFingerprint == fingerprint {
+			bucket[i] = 0
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// bucket returns the entries of the bucket with the given index.
+func (c *CuckooFilter) bucket(index uint64) []uint32 {
+	return c.buckets[index*bucketSize : (index+1)*bucketSize]
+}
+
+// alternativeIndex returns the other candidate bucket of a fingerprint that is stored in the given bucket.
+func (c *CuckooFilter) alternativeIndex(index uint64, fingerprint uint32) uint64 {
+	return (index ^ (uint64(fingerprint) * 0x5bd1e995)) & c.bucketMask
+}
+
+// victim is a fingerprint that could not be stored in its buckets.
+type victim struct {
+	// index is the bucket that the fingerprint belongs to.
+	index uint64
+
+	// fingerprint is the fingerprint of the item.
+	fingerprint uint32
+}
@@ -0,0 +1,92 @@
+package bloomfilter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/bloomfilter"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+func TestCuckooFilter(t *testing.T) {
+	filter := bloomfilter.NewCuckoo(10000, 0.001)
+
+	addedItems := make([]int, 0)
+	for i := 0; i < 10000; i++ {
+		added, err := filter.Add(item(i))
+		require.NoError(t, err)
+
+		if added {
+			addedItems = append(addedItems, i)
+		}
+	}
+	require.Equal(t, uint64(len(addedItems)), filter.Count())
+	require.Greater(t, len(addedItems), 9980)
+
+	for i := 0; i < 10000; i++ {
+		require.True(t, filter.Contains(item(i)))
+	}
+
+	require.Less(t, falsePositiveRate(filter.Contains), 0.002)
+
+	// deleted items are no longer contained
+	for _, i := range addedItems[:5000] {
+		require.True(t, filter.Delete(item(i)))
+	}
+	require.Equal(t, uint64(len(addedItems)-5000), filter.Count())
+
+	for _, i := range addedItems[5000:] {
+		require.True(t, filter.Contains(item(i)))
+	}
+	require.Less(t, falsePositiveRate(filter.Contains), 0.002)
+
+	filter.Reset()
+	require.Equal(t, uint64(0), filter.Count())
+	require.False(t, filter.Contains(item(9999)))
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	filter := bloomfilter.NewCuckoo(10, 0.01)
+
+	var err error
+	added := 0
+	for i := 0; err == nil; i++ {
+		var wasAdded bool
+		if wasAdded, err = filter.Add(item(i)); wasAdded {
+			added++
+		}
+	}
+	require.ErrorIs(t, err, bloomfilter.ErrFilterFull)
+	require.Equal(t, uint64(added), filter.Count())
+
+	// deleting an item makes room again
+	require.True(t, filter.Delete(item(0)))
+	wasAdded, err := filter.Add(item(0))
+	require.NoError(t, err)
+	require.True(t, wasAdded)
+}
+
+func TestCuckooFilterSerialization(t *testing.T) {
+	filter := bloomfilter.NewCuckoo(1000, 0.01)
+	for i := 0; i < 500; i++ {
+		_, err := filter.Add(item(i))
+		require.NoError(t, err)
+	}
+
+	bytes, err := serix.NewAPI().Encode(context.Background(), filter)
+	require.NoError(t, err)
+
+	var decoded *bloomfilter.CuckooFilter
+	_, err = serix.NewAPI().Decode(context.Background(), bytes, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, filter.Count(), decoded.Count())
+
+	for i := 0; i < 500; i++ {
+		require.True(t, decoded.Contains(item(i)))
+	}
+
+	require.True(t, decoded.Delete(item(0)))
+	require.False(t, decoded.Contains(item(0)))
+}
@@ -0,0 +1,276 @@
+package bloomfilter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// RotatingBloomFilter is a thread-safe Bloom filter that forgets items over time. It consists of multiple generations
+// of filters, and a new generation replaces the oldest one whenever the rotation interval elapsed (or the current
+// generation is full). Items are therefore remembered for at least (generations - 1) * rotationInterval.
+type RotatingBloomFilter struct {
+	// generations contains the filters of all generations (the last one receives new items).
+	generations []*filter
+
+	// capacity is the capacity of every generation.
+	capacity uint64
+
+	// falsePositiveRate is the target false-positive rate of the RotatingBloomFilter.
+	falsePositiveRate float64
+
+	// rotationInterval is the interval after which a new generation is started.
+	rotationInterval time.Duration
+
+	// lastRotation is the time at which the current generation was started.
+	lastRotation time.Time
+
+	// optsGenerationCount is the number of generations that are kept.
+	optsGenerationCount int
+
+	// optsClock is the function that returns the current time.
+	optsClock func() time.Time
+
+	// mutex is used to synchronize access to the generations.
+	mutex sync.RWMutex
+}
+
+// NewRotating creates a new RotatingBloomFilter whose generations hold up to capacity items each and that starts a new
+// generation every rotationInterval.
+func NewRotating(capacity uint64, falsePositiveRate float64, rotationInterval time.Duration, opts ...options.Option[RotatingBloomFilter]) *RotatingBloomFilter {
+	return options.Apply(&RotatingBloomFilter{
+		capacity:            capacity,
+		falsePositiveRate:   falsePositiveRate,
+		rotationInterval:    rotationInterval,
+		optsGenerationCount: 2,
+		optsClock:           time.Now,
+	}, opts, func(r *RotatingBloomFilter) {
+		if r.rotationInterval <= 0 {
+			panic("rotation interval must be greater than 0")
+		}
+
+		if r.optsGenerationCount < 1 {
+			panic("generation count must be greater than 0")
+		}
+
+		r.generations = make([]*filter, r.optsGenerationCount)
+		for i := range r.generations {
+			r.generations[i] = r.newGeneration()
+		}
+
+		r.lastRotation = r.optsClock()
+	})
+}
+
+// Add adds the given item to the RotatingBloomFilter and returns true if it was not (probably) contained before.
+func (r *RotatingBloomFilter) Add(item []byte) (added bool) {
+	h1, h2 := hashItem(item)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.optsClock()
+	r.rotateExpired(now)
+
+	if r.contains(h1, h2, 0) {
+		return false
+	}
+
+	if r.generations[len(r.generations)-1].isFull() {
+		r.rotate(1)
+		r.lastRotation = now
+	}
+
+	r.generations[len(r.generations)-1].add(h1, h2)
+
+	return true
+}
+
+// Contains returns true if the given item was (probably) added to the RotatingBloomFilter and not forgotten yet.
+func (r *RotatingBloomFilter) Contains(item []byte) bool {
+	h1, h2 := hashItem(item)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.contains(h1, h2, r.expiredGenerations(r.optsClock()))
+}
+
+// Rotate starts a new generation (and forgets the items of the oldest one).
+func (r *RotatingBloomFilter) Rotate() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.optsClock()
+	r.rotateExpired(now)
+	r.rotate(1)
+	r.lastRotation = now
+}
+
+// Reset removes all items from the RotatingBloomFilter.
+func (r *RotatingBloomFilter) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rotate(len(r.generations))
+	r.lastRotation = r.optsClock()
+}
+
+// Encode returns a serialized byte slice of the RotatingBloomFilter.
+func (r *RotatingBloomFilter) Encode() ([]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	seri := serializer.NewSerializer()
+	seri.WriteNum(r.capacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to write capacity")
+	})
+	seri.WriteNum(r.falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to write false-positive rate")
+	})
+	seri.WriteNum(int64(r.rotationInterval), func(err error) error {
+		return ierrors.Wrap(err, "failed to write rotation interval")
+	})
+	seri.WriteTime(r.lastRotation, func(err error) error {
+		return ierrors.Wrap(err, "failed to write last rotation")
+	})
+	seri.WriteNum(uint32(len(r.generations)), func(err error) error {
+		return ierrors.Wrap(err, "failed to write generation count")
+	})
+
+	for _, generation := range r.generations {
+		generation.encode(seri)
+	}
+
+	return seri.Serialize()
+}
+
+// Decode deserializes the given bytes into the RotatingBloomFilter (the clock is not part of the serialized state).
+func (r *RotatingBloomFilter) Decode(bytes []byte) (bytesRead int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var capacity uint64
+	var falsePositiveRate float64
+	var rotationInterval int64
+	var lastRotation time.Time
+	var generationCount uint32
+
+	deseri := serializer.NewDeserializer(bytes)
+	deseri.ReadNum(&capacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to read capacity")
+	})
+	deseri.ReadNum(&falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to read false-positive rate")
+	})
+	deseri.ReadNum(&rotationInterval, func(err error) error {
+		return ierrors.Wrap(err, "failed to read rotation interval")
+	})
+	deseri.ReadTime(&lastRotation, func(err error) error {
+		return ierrors.Wrap(err, "failed to read last rotation")
+	})
+	deseri.ReadNum(&generationCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read generation count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		if rotationInterval <= 0 || generationCount == 0 {
+			return ierrors.New("invalid RotatingBloomFilter parameters")
+		}
+
+		return nil
+	})
+
+	generations := make([]*filter, 0)
+	for i := uint32(0); i < generationCount; i++ {
+		if _, err = deseri.Done(); err != nil {
+			break
+		}
+
+		generations = append(generations, decodeFilter(deseri))
+	}
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode RotatingBloomFilter")
+	}
+
+	r.capacity = capacity
+	r.falsePositiveRate = falsePositiveRate
+	r.rotationInterval = time.Duration(rotationInterval)
+	r.lastRotation = lastRotation
+	r.generations = generations
+	r.optsGenerationCount = len(generations)
+	if r.optsClock == nil {
+		r.optsClock = time.Now
+	}
+
+	return bytesRead, nil
+}
+
+// contains returns true if the item with the given hashes is (probably) contained in one of the generations that are
+// not expired.
+func (r *RotatingBloomFilter) contains(h1, h2 uint64, expiredGenerations int) bool {
+	for _, generation := range r.generations[expiredGenerations:] {
+		if generation.contains(h1, h2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expiredGenerations returns the number of generations that expired at the given time (but were not rotated yet).
+func (r *RotatingBloomFilter) expiredGenerations(now time.Time) int {
+	elapsedRotations := now.Sub(r.lastRotation) / r.rotationInterval
+	if elapsedRotations <= 0 {
+		return 0
+	}
+
+	return int(min(elapsedRotations, time.Duration(len(r.generations))))
+}
+
+// rotateExpired replaces the generations that expired at the given time with new ones.
+func (r *RotatingBloomFilter) rotateExpired(now time.Time) {
+	elapsedRotations := now.Sub(r.lastRotation) / r.rotationInterval
+	if elapsedRotations <= 0 {
+		return
+	}
+
+	if elapsedRotations >= time.Duration(len(r.generations)) {
+		r.rotate(len(r.generations))
+		r.lastRotation = now
+
+		return
+	}
+
+	r.rotate(int(elapsedRotations))
+	r.lastRotation = r.lastRotation.Add(elapsedRotations * r.rotationInterval)
+}
+
+// rotate replaces the given number of oldest generations with new ones.
+func (r *RotatingBloomFilter) rotate(count int) {
+	for i := 0; i < count; i++ {
+		r.generations = append(r.generations[1:], r.newGeneration())
+	}
+}
+
+// newGeneration creates a new (empty) generation (the generations share the target false-positive rate).
+func (r *RotatingBloomFilter) newGeneration() *filter {
+	return newFilter(r.capacity, r.falsePositiveRate/float64(r.optsGenerationCount))
+}
+
+// WithGenerationCount sets the number of generations that are kept (defaults to 2).
+func WithGenerationCount(generationCount int) options.Option[RotatingBloomFilter] {
+	return func(r *RotatingBloomFilter) {
+		r.optsGenerationCount = generationCount
+	}
+}
+
+// WithClock sets the function that returns the current time (defaults to time.Now).
+func WithClock(clock func() time.Time) options.Option[RotatingBloomFilter] {
+	return func(r *RotatingBloomFilter) {
+		r.optsClock = clock
+	}
+}
@@ -0,0 +1,83 @@
+package bloomfilter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/bloomfilter"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+func TestRotatingBloomFilter(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	filter := bloomfilter.NewRotating(1000, 0.01, time.Minute, bloomfilter.WithGenerationCount(3), bloomfilter.WithClock(clock))
+	require.True(t, filter.Add(item(1)))
+
+	now = now.Add(time.Minute)
+	require.True(t, filter.Add(item(2)))
+
+	now = now.Add(time.Minute)
+	require.True(t, filter.Contains(item(1)))
+	require.True(t, filter.Contains(item(2)))
+
+	// the generation of the first item expires (even without a write)
+	now = now.Add(time.Minute)
+	require.False(t, filter.Contains(item(1)))
+	require.True(t, filter.Contains(item(2)))
+
+	// the item can be added again after it was forgotten
+	require.True(t, filter.Add(item(1)))
+	require.False(t, filter.Add(item(2)))
+
+	// everything is forgotten after a long pause
+	now = now.Add(time.Hour)
+	require.False(t, filter.Contains(item(1)))
+	require.False(t, filter.Contains(item(2)))
+
+	require.True(t, filter.Add(item(1)))
+	filter.Rotate()
+	filter.Rotate()
+	require.True(t, filter.Contains(item(1)))
+	filter.Rotate()
+	require.False(t, filter.Contains(item(1)))
+}
+
+func TestRotatingBloomFilterCapacity(t *testing.T) {
+	filter := bloomfilter.NewRotating(100, 0.01, time.Hour)
+
+	// a full generation is rotated immediately
+	for i := 0; i < 300; i++ {
+		filter.Add(item(i))
+	}
+
+	for i := 200; i < 300; i++ {
+		require.True(t, filter.Contains(item(i)))
+	}
+
+	require.Less(t, falsePositiveRate(filter.Contains), 0.015)
+}
+
+func TestRotatingBloomFilterSerialization(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+
+	filter := bloomfilter.NewRotating(1000, 0.01, time.Minute, bloomfilter.WithClock(clock))
+	filter.Add(item(1))
+
+	bytes, err := serix.NewAPI().Encode(context.Background(), filter)
+	require.NoError(t, err)
+
+	decoded := bloomfilter.NewRotating(1, 0.5, time.Second, bloomfilter.WithClock(clock))
+	_, err = serix.NewAPI().Decode(context.Background(), bytes, decoded)
+	require.NoError(t, err)
+
+	// the decoded filter continues the rotation schedule of the encoded one
+	require.True(t, decoded.Contains(item(1)))
+	now = now.Add(2 * time.Minute)
+	require.False(t, decoded.Contains(item(1)))
+}
@@ -0,0 +1,224 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// ScalableBloomFilter is a thread-safe Bloom filter that grows with the number of added items while keeping the overall
+// false-positive rate below the configured target (by adding filters with a growing capacity and a tightening
+// false-positive rate).
+type ScalableBloomFilter struct {
+	// filters contains the filters that were created so far (the last one receives new items).
+	filters []*filter
+
+	// initialCapacity is the capacity of the first filter.
+	initialCapacity uint64
+
+	// falsePositiveRate is the target false-positive rate of the ScalableBloomFilter.
+	falsePositiveRate float64
+
+	// optsGrowthFactor is the factor by which the capacity of every new filter grows.
+	optsGrowthFactor uint64
+
+	// optsTighteningRatio is the factor by which the false-positive rate of every new filter shrinks.
+	optsTighteningRatio float64
+
+	// mutex is used to synchronize access to the filters.
+	mutex sync.RWMutex
+}
+
+// NewScalable creates a new ScalableBloomFilter with the given initial capacity and target false-positive rate.
+func NewScalable(initialCapacity uint64, falsePositiveRate float64, opts ...options.Option[ScalableBloomFilter]) *ScalableBloomFilter {
+	return options.Apply(&ScalableBloomFilter{
+		initialCapacity:     initialCapacity,
+		falsePositiveRate:   falsePositiveRate,
+		optsGrowthFactor:    2,
+		optsTighteningRatio: 0.8,
+	}, opts, func(s *ScalableBloomFilter) {
+		if s.optsGrowthFactor == 0 {
+			panic("growth factor must be greater than 0")
+		}
+
+		if s.optsTighteningRatio <= 0 || s.optsTighteningRatio >= 1 {
+			panic(fmt.Sprintf("tightening ratio must be in (0, 1) but is %f", s.optsTighteningRatio))
+		}
+
+		s.filters = []*filter{s.newFilter(0)}
+	})
+}
+
+// Add adds the given item to the ScalableBloomFilter and returns true if it was not (probably) contained before.
+func (s *ScalableBloomFilter) Add(item []byte) (added bool) {
+	h1, h2 := hashItem(item)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.contains(h1, h2) {
+		return false
+	}
+
+	if s.filters[len(s.filters)-1].isFull() {
+		s.filters = append(s.filters, s.newFilter(len(s.filters)))
+	}
+
+	s.filters[len(s.filters)-1].add(h1, h2)
+
+	return true
+}
+
+// Contains returns true if the given item was (probably) added to the ScalableBloomFilter.
+func (s *ScalableBloomFilter) Contains(item []byte) bool {
+	h1, h2 := hashItem(item)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.contains(h1, h2)
+}
+
+// Count returns the number of items that were added to the ScalableBloomFilter.
+func (s *ScalableBloomFilter) Count() (count uint64) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, f := range s.filters {
+		count += f.count
+	}
+
+	return count
+}
+
+// Reset removes all items from the ScalableBloomFilter (and releases the memory of the additional filters).
+func (s *ScalableBloomFilter) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.filters = []*filter{s.newFilter(0)}
+}
+
+// Encode returns a serialized byte slice of the ScalableBloomFilter.
+func (s *ScalableBloomFilter) Encode() ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seri := serializer.NewSerializer()
+	seri.WriteNum(s.initialCapacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to write initial capacity")
+	})
+	seri.WriteNum(s.falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to write false-positive rate")
+	})
+	seri.WriteNum(s.optsGrowthFactor, func(err error) error {
+		return ierrors.Wrap(err, "failed to write growth factor")
+	})
+	seri.WriteNum(s.optsTighteningRatio, func(err error) error {
+		return ierrors.Wrap(err, "failed to write tightening ratio")
+	})
+	seri.WriteNum(uint32(len(s.filters)), func(err error) error {
+		return ierrors.Wrap(err, "failed to write filter count")
+	})
+
+	for _, f := range s.filters {
+		f.encode(seri)
+	}
+
+	return seri.Serialize()
+}
+
+// Decode deserializes the given bytes into the ScalableBloomFilter.
+func (s *ScalableBloomFilter) Decode(bytes []byte) (bytesRead int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var initialCapacity, growthFactor uint64
+	var falsePositiveRate, tighteningRatio float64
+	var filterCount uint32
+
+	deseri := serializer.NewDeserializer(bytes)
+	deseri.ReadNum(&initialCapacity, func(err error) error {
+		return ierrors.Wrap(err, "failed to read initial capacity")
+	})
+	deseri.ReadNum(&falsePositiveRate, func(err error) error {
+		return ierrors.Wrap(err, "failed to read false-positive rate")
+	})
+	deseri.ReadNum(&growthFactor, func(err error) error {
+		return ierrors.Wrap(err, "failed to read growth factor")
+	})
+	deseri.ReadNum(&tighteningRatio, func(err error) error {
+		return ierrors.Wrap(err, "failed to read tightening ratio")
+	})
+	deseri.ReadNum(&filterCount, func(err error) error {
+		return ierrors.Wrap(err, "failed to read filter count")
+	})
+	deseri.AbortIf(func(_ error) error {
+		if filterCount == 0 {
+			return ierrors.New("ScalableBloomFilter must contain at least one filter")
+		}
+
+		return nil
+	})
+
+	filters := make([]*filter, 0)
+	for i := uint32(0); i < filterCount; i++ {
+		if _, err = deseri.Done(); err != nil {
+			break
+		}
+
+		filters = append(filters, decodeFilter(deseri))
+	}
+
+	if bytesRead, err = deseri.Done(); err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode ScalableBloomFilter")
+	}
+
+	s.initialCapacity = initialCapacity
+	s.falsePositiveRate = falsePositiveRate
+	s.optsGrowthFactor = growthFactor
+	s.optsTighteningRatio = tighteningRatio
+	s.filters = filters
+
+	return bytesRead, nil
+}
+
+// contains returns true if the item with the given hashes is (probably) contained in one of the filters.
+func (s *ScalableBloomFilter) contains(h1, h2 uint64) bool {
+	for _, f := range s.filters {
+		if f.contains(h1, h2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newFilter creates the filter with the given index (the false-positive rates of all filters form a geometric series
+// that converges to the target false-positive rate).
+func (s *ScalableBloomFilter) newFilter(index int) *filter {
+	capacity := s.initialCapacity
+	for i := 0; i < index; i++ {
+		capacity *= s.optsGrowthFactor
+	}
+
+	return newFilter(capacity, s.falsePositiveRate*(1-s.optsTighteningRatio)*math.Pow(s.optsTighteningRatio, float64(index)))
+}
+
+// WithGrowthFactor sets the factor by which the capacity of every new filter grows (defaults to 2).
+func WithGrowthFactor(growthFactor uint64) options.Option[ScalableBloomFilter] {
+	return func(s *ScalableBloomFilter) {
+		s.optsGrowthFactor = growthFactor
+	}
+}
+
+// WithTighteningRatio sets the factor by which the false-positive rate of every new filter shrinks (defaults to 0.8).
+func WithTighteningRatio(tighteningRatio float64) options.Option[ScalableBloomFilter] {
+	return func(s *ScalableBloomFilter) {
+		s.optsTighteningRatio = tighteningRatio
+	}
+}
@@ -0,0 +1,51 @@
+package bloomfilter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/bloomfilter"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+func TestScalableBloomFilter(t *testing.T) {
+	filter := bloomfilter.NewScalable(100, 0.01, bloomfilter.WithGrowthFactor(2), bloomfilter.WithTighteningRatio(0.5))
+
+	// the filter grows far beyond its initial capacity
+	added := 0
+	for i := 0; i < 20000; i++ {
+		if filter.Add(item(i)) {
+			added++
+		}
+	}
+	require.Equal(t, uint64(added), filter.Count())
+	require.Greater(t, added, 19700)
+
+	for i := 0; i < 20000; i++ {
+		require.True(t, filter.Contains(item(i)))
+	}
+
+	// while the false-positive rate stays below the target
+	require.Less(t, falsePositiveRate(filter.Contains), 0.015)
+
+	bytes, err := serix.NewAPI().Encode(context.Background(), filter)
+	require.NoError(t, err)
+
+	decoded := new(bloomfilter.ScalableBloomFilter)
+	_, err = serix.NewAPI().Decode(context.Background(), bytes, decoded)
+	require.NoError(t, err)
+	require.Equal(t, filter.Count(), decoded.Count())
+
+	for i := 0; i < 20000; i++ {
+		require.True(t, decoded.Contains(item(i)))
+	}
+
+	// the decoded filter keeps growing
+	decoded.Add(item(20000))
+	require.True(t, decoded.Contains(item(20000)))
+
+	filter.Reset()
+	require.Equal(t, uint64(0), filter.Count())
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"github.com/iotaledger/hive.go/ds"
+)
+
+// arc is the policy that implements the Adaptive Replacement Cache algorithm. It splits the tracked keys into keys that
+// were used once (recent) and keys that were used multiple times (frequent) and remembers the recently evicted keys of
+// both lists (ghosts) to adapt the target size of the recent list to the observed access pattern.
+type arc[K comparable] struct {
+	// recent contains the keys that were used once (T1).
+	recent *arcList[K]
+
+	// frequent contains the keys that were used multiple times (T2).
+	frequent *arcList[K]
+
+	// recentGhosts contains the keys that were recently evicted from the recent list (B1).
+	recentGhosts *arcList[K]
+
+	// frequentGhosts contains the keys that were recently evicted from the frequent list (B2).
+	frequentGhosts *arcList[K]
+
+	// targetRecentSize is the adaptive target size of the recent list (p).
+	targetRecentSize int
+
+	// admittedKey is the key that was admitted last.
+	admittedKey K
+
+	// admittedToFrequent is true if the admitted key was found in one of the ghost lists.
+	admittedToFrequent bool
+
+	// lastAddWasFrequentGhost is true if the last admitted key was found in the frequent ghost list.
+	lastAddWasFrequentGhost bool
+}
+
+// newARC creates a new arc policy.
+func newARC[K comparable]() *arc[K] {
+	return &arc[K]{
+		recent:         newARCList[K](),
+		frequent:       newARCList[K](),
+		recentGhosts:   newARCList[K](),
+		frequentGhosts: newARCList[K](),
+	}
+}
+
+// admit adapts the target size of the recent list if the given key was evicted recently.
+func (a *arc[K]) admit(key K) {
+	// the capacity (c) is the number of entries that the cache holds
+	capacity := a.recent.len() + a.frequent.len() + 1
+
+	a.admittedKey = key
+	a.admittedToFrequent = false
+	a.lastAddWasFrequentGhost = false
+
+	switch {
+	case a.recentGhosts.remove(key):
+		a.targetRecentSize = min(capacity, a.targetRecentSize+max(1, a.frequentGhosts.len()/max(1, a.recentGhosts.len())))
+		a.admittedToFrequent = true
+	case a.frequentGhosts.remove(key):
+		a.targetRecentSize = max(0, a.targetRecentSize-max(1, a.recentGhosts.len()/max(1, a.frequentGhosts.len())))
+		a.admittedToFrequent = true
+		a.lastAddWasFrequentGhost = true
+	}
+}
+
+// add starts tracking the given key (keys that were evicted recently are added to the frequent list).
+func (a *arc[K]) add(key K) {
+	if a.admittedToFrequent && a.admittedKey == key {
+		a.frequent.push(key)
+	} else {
+		a.recent.push(key)
+	}
+
+	a.admittedToFrequent = false
+}
+
+// access moves the given key to the most recently used position of the frequent list.
+func (a *arc[K]) access(key K) {
+	if a.recent.remove(key) || a.frequent.remove(key) {
+		a.frequent.push(key)
+	}
+}
+
+// remove stops tracking the given key (evicted keys are remembered in the ghost lists).
+func (a *arc[K]) remove(key K, evicted bool) {
+	switch {
+	case a.recent.remove(key):
+		if evicted {
+			a.recentGhosts.push(key)
+		}
+	case a.frequent.remove(key):
+		if evicted {
+			a.frequentGhosts.push(key)
+		}
+	}
+
+	// the ghost lists remember at most as many keys as the cache holds
+	capacity := max(1, a.recent.len()+a.frequent.len())
+	for a.recentGhosts.len() > capacity {
+		a.recentGhosts.removeOldest()
+	}
+	for a.frequentGhosts.len() > capacity {
+		a.frequentGhosts.removeOldest()
+	}
+}
+
+// victim returns the least recently used key of the list that exceeds its target size.
+func (a *arc[K]) victim() (key K, exists bool) {
+	recentSize := a.recent.len()
+	if recentSize > 0 && (recentSize > a.targetRecentSize || (recentSize == a.targetRecentSize && a.lastAddWasFrequentGhost) || a.frequent.len() == 0) {
+		return a.recent.oldest()
+	}
+
+	return a.frequent.oldest()
+}
+
+// arcList is a list of keys ordered from the least to the most recently used one.
+type arcList[K comparable] struct {
+	// keys contains the keys of the list.
+	keys ds.List[K]
+
+	// elements contains the list elements of the keys.
+	elements map[K]ds.ListElement[K]
+}
+
+// newARCList creates a new arcList.
+func newARCList[K comparable]() *arcList[K] {
+	return &arcList[K]{
+		keys:     ds.NewList[K](true),
+		elements: make(map[K]ds.ListElement[K]),
+	}
+}
+
+// push adds the given key at the most recently used position.
+func (a *arcList[K]) push(key K) {
+	a.elements[key] = a.keys.PushBack(key)
+}
+
+// remove removes the given key and returns true if it was contained.
+func (a *arcList[K]) remove(key K) bool {
+	element, exists := a.elements[key]
+	if exists {
+		a.keys.Remove(element)
+
+		delete(a.elements, key)
+	}
+
+	return exists
+}
+
+// oldest returns the least recently used key.
+func (a *arcList[K]) oldest() (key K, exists bool) {
+	if front := a.keys.Front(); front != nil {
+		return front.Value(), true
+	}
+
+	return key, false
+}
+
+// removeOldest removes the least recently used key.
+func (a *arcList[K]) removeOldest() {
+	if oldestKey, exists := a.oldest(); exists {
+		a.remove(oldestKey)
+	}
+}
+
+// len returns the number of keys in the list.
+func (a *arcList[K]) len() int {
+	return a.keys.Len()
+}
@@ -0,0 +1,419 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/runtime/options"
+	"github.com/iotaledger/hive.go/runtime/timed"
+)
+
+// Cache is a thread-safe key-value cache that is bounded by the total cost of its entries and that evicts entries
+// according to its Policy (and optionally after a time-to-live).
+type Cache[K comparable, V any] struct {
+	// capacity is the maximum total cost of the entries.
+	capacity int64
+
+	// cost is the current total cost of the entries.
+	cost int64
+
+	// entries contains the cached entries.
+	entries map[K]*entry[V]
+
+	// policy tracks the entries according to the configured Policy.
+	policy policy[K]
+
+	// loads contains the pending loads of GetOrCompute (to de-duplicate concurrent loads of the same key).
+	loads map[K]*load[V]
+
+	// stats contains the statistics of the Cache.
+	stats Stats
+
+	// optsPolicy is the eviction policy of the Cache.
+	optsPolicy Policy
+
+	// optsTTL is the default time-to-live of the entries (0 means that entries do not expire).
+	optsTTL time.Duration
+
+	// optsCostFunc is the function that determines the cost of an entry.
+	optsCostFunc func(key K, value V) int64
+
+	// optsEvictionCallback is the callback that is triggered when an entry is evicted.
+	optsEvictionCallback func(key K, value V, reason EvictionReason)
+
+	// optsClock is the function that returns the current time.
+	optsClock func() time.Time
+
+	// optsTimedExecutor is the executor that actively removes expired entries (if set).
+	optsTimedExecutor *timed.Executor
+
+	// mutex is used to synchronize access to the entries.
+	mutex sync.Mutex
+}
+
+// New creates a new Cache that holds entries up to the given total cost (every entry has a cost of 1 unless a
+// different cost function is configured). Entries whose cost exceeds the capacity are evicted immediately.
+func New[K comparable, V any](capacity int64, opts ...options.Option[Cache[K, V]]) *Cache[K, V] {
+	return options.Apply(&Cache[K, V]{
+		capacity:     capacity,
+		entries:      make(map[K]*entry[V]),
+		loads:        make(map[K]*load[V]),
+		optsPolicy:   LRU,
+		optsCostFunc: func(K, V) int64 { return 1 },
+		optsClock:    time.Now,
+	}, opts, func(c *Cache[K, V]) {
+		c.policy = newPolicy[K](c.optsPolicy)
+	})
+}
+
+// Get returns the value of the given key (and records the access of the entry).
+func (c *Cache[K, V]) Get(key K) (value V, exists bool) {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.get(key, &evictedEntries)
+}
+
+// Peek returns the value of the given key without recording an access (or updating the statistics).
+func (c *Cache[K, V]) Peek(key K) (value V, exists bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cachedEntry, exists := c.entries[key]
+	if !exists || cachedEntry.isExpired(c.optsClock()) {
+		return value, false
+	}
+
+	return cachedEntry.value, true
+}
+
+// Has returns true if the given key is cached (without recording an access).
+func (c *Cache[K, V]) Has(key K) bool {
+	_, exists := c.Peek(key)
+
+	return exists
+}
+
+// Set caches the given value for the given key (with the default time-to-live).
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.optsTTL)
+}
+
+// SetWithTTL caches the given value for the given key with the given time-to-live (0 means that the entry does not
+// expire).
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.set(key, value, ttl, &evictedEntries)
+}
+
+// GetOrCompute returns the value of the given key or computes (and caches) it if it is missing. Concurrent calls for
+// the same missing key wait for a single computation and share its result. Errors are returned to all waiting callers
+// and are not cached. The result is also not cached (but still returned) if the key is set or deleted (or the Cache is
+// cleared) during the computation, so that the computed value never overwrites a more recent update.
+func (c *Cache[K, V]) GetOrCompute(key K, compute func() (V, error)) (value V, err error) {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	if value, exists := c.get(key, &evictedEntries); exists {
+		c.mutex.Unlock()
+
+		return value, nil
+	}
+
+	if pendingLoad, exists := c.loads[key]; exists {
+		c.mutex.Unlock()
+
+		<-pendingLoad.done
+
+		return pendingLoad.value, pendingLoad.err
+	}
+
+	pendingLoad := &load[V]{done: make(chan struct{})}
+	c.loads[key] = pendingLoad
+	c.mutex.Unlock()
+
+	// clean up the pending load even if the computation panics (so waiting callers do not block forever)
+	defer func() {
+		c.mutex.Lock()
+		delete(c.loads, key)
+		c.mutex.Unlock()
+
+		close(pendingLoad.done)
+	}()
+
+	pendingLoad.err = ErrComputationPanicked
+	pendingLoad.value, pendingLoad.err = compute()
+	if pendingLoad.err == nil {
+		c.mutex.Lock()
+		if !pendingLoad.invalidated {
+			c.set(key, pendingLoad.value, c.optsTTL, &evictedEntries)
+		}
+		c.mutex.Unlock()
+	}
+
+	return pendingLoad.value, pendingLoad.err
+}
+
+// Delete removes the given key from the Cache and returns true if it was cached.
+func (c *Cache[K, V]) Delete(key K) (deleted bool) {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.invalidateLoad(key)
+
+	cachedEntry, exists := c.entries[key]
+	if !exists {
+		return false
+	}
+
+	c.remove(key, cachedEntry, EvictionReasonDeleted, &evictedEntries)
+
+	return !cachedEntry.isExpired(c.optsClock())
+}
+
+// EvictExpired removes all expired entries from the Cache (expired entries are otherwise removed when they are
+// accessed or when the configured timed.Executor removes them).
+func (c *Cache[K, V]) EvictExpired() {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.optsClock()
+	for key, cachedEntry := range c.entries {
+		if cachedEntry.isExpired(now) {
+			c.remove(key, cachedEntry, EvictionReasonExpired, &evictedEntries)
+		}
+	}
+}
+
+// Clear removes all entries from the Cache.
+func (c *Cache[K, V]) Clear() {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, cachedEntry := range c.entries {
+		c.remove(key, cachedEntry, EvictionReasonDeleted, &evictedEntries)
+	}
+
+	for _, pendingLoad := range c.loads {
+		pendingLoad.invalidated = true
+	}
+}
+
+// Len returns the number of entries in the Cache (including expired entries that were not removed yet).
+func (c *Cache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.entries)
+}
+
+// Cost returns the total cost of the entries in the Cache.
+func (c *Cache[K, V]) Cost() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.cost
+}
+
+// Capacity returns the maximum total cost of the entries in the Cache.
+func (c *Cache[K, V]) Capacity() int64 {
+	return c.capacity
+}
+
+// Stats returns the statistics of the Cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+// get returns the value of the given key and records the access (expects the mutex to be locked).
+func (c *Cache[K, V]) get(key K, evictedEntries *[]*evictedEntry[K, V]) (value V, exists bool) {
+	cachedEntry, exists := c.entries[key]
+	if exists && cachedEntry.isExpired(c.optsClock()) {
+		c.remove(key, cachedEntry, EvictionReasonExpired, evictedEntries)
+
+		exists = false
+	}
+
+	if !exists {
+		c.stats.Misses++
+
+		return value, false
+	}
+
+	c.stats.Hits++
+	c.policy.access(key)
+
+	return cachedEntry.value, true
+}
+
+// set caches the given value for the given key (expects the mutex to be locked).
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration, evictedEntries *[]*evictedEntry[K, V]) {
+	newEntry := &entry[V]{
+		value: value,
+		cost:  c.optsCostFunc(key, value),
+	}
+
+	c.invalidateLoad(key)
+
+	// entries that can never fit are rejected without evicting any other entries (a replaced entry is evicted as well,
+	// since it is outdated)
+	if newEntry.cost > c.capacity {
+		if replacedEntry, exists := c.entries[key]; exists {
+			c.remove(key, replacedEntry, EvictionReasonCapacity, evictedEntries)
+		}
+
+		c.stats.Evictions++
+
+		if c.optsEvictionCallback != nil {
+			*evictedEntries = append(*evictedEntries, &evictedEntry[K, V]{key: key, value: value, reason: EvictionReasonCapacity})
+		}
+
+		return
+	}
+
+	// a replaced entry is dropped silently (the new value starts with a fresh usage history)
+	if replacedEntry, exists := c.entries[key]; exists {
+		replacedEntry.cancelExpiry()
+
+		delete(c.entries, key)
+		c.cost -= replacedEntry.cost
+		c.policy.remove(key, false)
+	}
+
+	c.policy.admit(key)
+
+	for c.cost+newEntry.cost > c.capacity {
+		victimKey, victimExists := c.policy.victim()
+		if !victimExists {
+			break
+		}
+
+		c.remove(victimKey, c.entries[victimKey], EvictionReasonCapacity, evictedEntries)
+	}
+
+	c.entries[key] = newEntry
+	c.cost += newEntry.cost
+	c.policy.add(key)
+
+	if ttl > 0 {
+		newEntry.expiresAt = c.optsClock().Add(ttl)
+
+		if c.optsTimedExecutor != nil {
+			newEntry.scheduledExpiry = c.optsTimedExecutor.ExecuteAfter(func() { c.expire(key, newEntry) }, ttl)
+		}
+	}
+}
+
+// invalidateLoad prevents that the result of a pending load of the given key is cached (expects the mutex to be
+// locked).
+func (c *Cache[K, V]) invalidateLoad(key K) {
+	if pendingLoad, exists := c.loads[key]; exists {
+		pendingLoad.invalidated = true
+	}
+}
+
+// expire removes the given entry of the given key if it is still cached (called by the timed.Executor).
+func (c *Cache[K, V]) expire(key K, expiredEntry *entry[V]) {
+	evictedEntries := make([]*evictedEntry[K, V], 0)
+	defer func() { c.triggerEvictionCallbacks(evictedEntries) }()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cachedEntry, exists := c.entries[key]; exists && cachedEntry == expiredEntry {
+		c.remove(key, cachedEntry, EvictionReasonExpired, &evictedEntries)
+	}
+}
+
+// remove removes the given entry of the given key and collects it for the eviction callback (expects the mutex to be
+// locked).
+func (c *Cache[K, V]) remove(key K, removedEntry *entry[V], reason EvictionReason, evictedEntries *[]*evictedEntry[K, V]) {
+	removedEntry.cancelExpiry()
+
+	delete(c.entries, key)
+	c.cost -= removedEntry.cost
+	c.policy.remove(key, reason == EvictionReasonCapacity)
+
+	switch reason {
+	case EvictionReasonCapacity:
+		c.stats.Evictions++
+	case EvictionReasonExpired:
+		c.stats.Expirations++
+	}
+
+	if c.optsEvictionCallback != nil {
+		*evictedEntries = append(*evictedEntries, &evictedEntry[K, V]{key: key, value: removedEntry.value, reason: reason})
+	}
+}
+
+// triggerEvictionCallbacks triggers the eviction callback for the given entries (outside the lock).
+func (c *Cache[K, V]) triggerEvictionCallbacks(evictedEntries []*evictedEntry[K, V]) {
+	for _, evicted := range evictedEntries {
+		c.optsEvictionCallback(evicted.key, evicted.value, evicted.reason)
+	}
+}
+
+// WithPolicy sets the eviction policy of the Cache (defaults to LRU).
+func WithPolicy[K comparable, V any](policy Policy) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsPolicy = policy
+	}
+}
+
+// WithTTL sets the default time-to-live of the entries (defaults to 0 which means that entries do not expire).
+func WithTTL[K comparable, V any](ttl time.Duration) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsTTL = ttl
+	}
+}
+
+// WithCostFunc sets the function that determines the cost of an entry (defaults to 1 for every entry).
+func WithCostFunc[K comparable, V any](costFunc func(key K, value V) int64) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsCostFunc = costFunc
+	}
+}
+
+// WithEvictionCallback sets the callback that is triggered when an entry is evicted, expired or deleted.
+func WithEvictionCallback[K comparable, V any](callback func(key K, value V, reason EvictionReason)) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsEvictionCallback = callback
+	}
+}
+
+// WithClock sets the function that returns the current time which is used to determine the expiry of the entries
+// (defaults to time.Now).
+func WithClock[K comparable, V any](clock func() time.Time) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsClock = clock
+	}
+}
+
+// WithTimedExecutor sets the timed.Executor that actively removes the entries once their time-to-live elapsed
+// (otherwise expired entries are removed lazily).
+func WithTimedExecutor[K comparable, V any](executor *timed.Executor) options.Option[Cache[K, V]] {
+	return func(c *Cache[K, V]) {
+		c.optsTimedExecutor = executor
+	}
+}
@@ -0,0 +1,317 @@
+package cache_test
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/cache"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/timed"
+)
+
+func TestCacheLRU(t *testing.T) {
+	evicted := make([]int, 0)
+	c := cache.New[int, string](3, cache.WithEvictionCallback(func(key int, _ string, reason cache.EvictionReason) {
+		require.Equal(t, cache.EvictionReasonCapacity, reason)
+
+		evicted = append(evicted, key)
+	}))
+
+	c.Set(1, "1")
+	c.Set(2, "2")
+	c.Set(3, "3")
+
+	value, exists := c.Get(1)
+	require.True(t, exists)
+	require.Equal(t, "1", value)
+
+	c.Set(4, "4")
+	require.Equal(t, []int{2}, evicted)
+	require.False(t, c.Has(2))
+	require.Equal(t, 3, c.Len())
+
+	// peeking does not count as a use
+	c.Peek(3)
+	c.Set(5, "5")
+	require.Equal(t, []int{2, 3}, evicted)
+
+	require.Equal(t, cache.Stats{Hits: 1, Misses: 0, Evictions: 2}, c.Stats())
+}
+
+func TestCacheLFU(t *testing.T) {
+	c := cache.New[int, int](3, cache.WithPolicy[int, int](cache.LFU))
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+
+	c.Get(1)
+	c.Get(1)
+	c.Get(2)
+	c.Get(3)
+
+	// 2 and 3 are equally frequent, but 2 was used less recently
+	c.Set(4, 4)
+	require.False(t, c.Has(2))
+	require.True(t, c.Has(1))
+	require.True(t, c.Has(3))
+
+	// the new entry is the least frequently used one
+	c.Set(5, 5)
+	require.False(t, c.Has(4))
+	require.True(t, c.Has(5))
+}
+
+func TestCacheARC(t *testing.T) {
+	c := cache.New[int, int](100, cache.WithPolicy[int, int](cache.ARC))
+
+	// establish a frequently used working set
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 50; i++ {
+			if _, exists := c.Get(i); !exists {
+				c.Set(i, i)
+			}
+		}
+	}
+
+	// a scan of keys that are used only once does not flush the frequently used ones
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i)
+	}
+
+	for i := 0; i < 50; i++ {
+		require.True(t, c.Has(i), "key %d should have survived the scan", i)
+	}
+	require.Equal(t, 100, c.Len())
+}
+
+func TestCacheCost(t *testing.T) {
+	c := cache.New[string, string](10, cache.WithCostFunc(func(_ string, value string) int64 {
+		return int64(len(value))
+	}))
+
+	c.Set("a", "aaaa")
+	c.Set("b", "bbbb")
+	require.Equal(t, int64(8), c.Cost())
+
+	c.Set("c", "cccc")
+	require.False(t, c.Has("a"))
+	require.Equal(t, int64(8), c.Cost())
+
+	// replacing a value updates the cost
+	c.Set("b", "b")
+	require.Equal(t, int64(5), c.Cost())
+
+	// entries that exceed the capacity are not kept and do not evict other entries
+	c.Set("d", "ddddddddddd")
+	require.False(t, c.Has("d"))
+	require.True(t, c.Has("b"))
+	require.True(t, c.Has("c"))
+	require.Equal(t, int64(5), c.Cost())
+}
+
+func TestCacheOversizedEntry(t *testing.T) {
+	for _, policy := range []cache.Policy{cache.LRU, cache.LFU, cache.ARC} {
+		evicted := make([]int, 0)
+		c := cache.New[int, int64](10, cache.WithPolicy[int, int64](policy), cache.WithCostFunc(func(_ int, cost int64) int64 {
+			return cost
+		}), cache.WithEvictionCallback(func(key int, _ int64, _ cache.EvictionReason) {
+			evicted = append(evicted, key)
+		}))
+
+		c.Set(1, 3)
+		c.Set(2, 3)
+		c.Set(3, 100)
+
+		require.True(t, c.Has(1))
+		require.True(t, c.Has(2))
+		require.False(t, c.Has(3))
+		require.Equal(t, int64(6), c.Cost())
+		require.Equal(t, []int{3}, evicted)
+
+		// replacing an entry with an oversized value evicts the outdated value as well
+		c.Set(1, 100)
+		require.False(t, c.Has(1))
+		require.True(t, c.Has(2))
+		require.Equal(t, int64(3), c.Cost())
+		require.Equal(t, []int{3, 1, 1}, evicted)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	reasons := make(map[int]cache.EvictionReason)
+	c := cache.New[int, int](10, cache.WithTTL[int, int](time.Minute), cache.WithClock[int, int](clock), cache.WithEvictionCallback(func(key int, _ int, reason cache.EvictionReason) {
+		reasons[key] = reason
+	}))
+
+	c.Set(1, 1)
+	c.SetWithTTL(2, 2, time.Hour)
+	c.SetWithTTL(3, 3, 0)
+
+	now = now.Add(time.Minute)
+	_, exists := c.Get(1)
+	require.False(t, exists)
+	require.True(t, c.Has(2))
+	require.True(t, c.Has(3))
+
+	now = now.Add(time.Hour)
+	c.EvictExpired()
+	require.Equal(t, 1, c.Len())
+	require.True(t, c.Delete(3))
+
+	require.Equal(t, map[int]cache.EvictionReason{
+		1: cache.EvictionReasonExpired,
+		2: cache.EvictionReasonExpired,
+		3: cache.EvictionReasonDeleted,
+	}, reasons)
+	require.Equal(t, uint64(2), c.Stats().Expirations)
+}
+
+func TestCacheTimedExecutor(t *testing.T) {
+	executor := timed.NewExecutor(1)
+	defer executor.Shutdown(timed.CancelPendingElements)
+
+	var expired atomic.Int32
+	c := cache.New[int, int](10, cache.WithTTL[int, int](10*time.Millisecond), cache.WithTimedExecutor[int, int](executor), cache.WithEvictionCallback(func(int, int, cache.EvictionReason) {
+		expired.Add(1)
+	}))
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.SetWithTTL(3, 3, time.Hour)
+
+	// expired entries are removed without being accessed
+	require.Eventually(t, func() bool { return c.Len() == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, int32(2), expired.Load())
+	require.True(t, c.Has(3))
+}
+
+func TestCacheGetOrCompute(t *testing.T) {
+	c := cache.New[int, int](10)
+
+	var computations atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := c.GetOrCompute(1, func() (int, error) {
+				computations.Add(1)
+				<-release
+
+				return 42, nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 42, value)
+		}()
+	}
+
+	// all concurrent callers share a single computation
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), computations.Load())
+	require.True(t, c.Has(1))
+
+	// errors are returned but not cached
+	computeErr := ierrors.New("failed")
+	_, err := c.GetOrCompute(2, func() (int, error) { return 0, computeErr })
+	require.ErrorIs(t, err, computeErr)
+	require.False(t, c.Has(2))
+
+	value, err := c.GetOrCompute(2, func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(12), stats.Hits+stats.Misses)
+}
+
+func TestCacheGetOrComputeInvalidation(t *testing.T) {
+	for name, update := range map[string]func(c *cache.Cache[int, int]){
+		"Set":    func(c *cache.Cache[int, int]) { c.Set(1, 2) },
+		"Delete": func(c *cache.Cache[int, int]) { c.Delete(1) },
+		"Clear":  func(c *cache.Cache[int, int]) { c.Clear() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := cache.New[int, int](10)
+
+			started, release := make(chan struct{}), make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				// the computed value is still returned to the caller
+				value, err := c.GetOrCompute(1, func() (int, error) {
+					close(started)
+					<-release
+
+					return 1, nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, 1, value)
+			}()
+
+			// updates during the computation are not overwritten with its outdated result
+			<-started
+			update(c)
+			expectedValue, expectedExists := c.Peek(1)
+
+			close(release)
+			<-done
+
+			value, exists := c.Peek(1)
+			require.Equal(t, expectedExists, exists)
+			require.Equal(t, expectedValue, value)
+		})
+	}
+
+	// loads that were not invalidated are cached
+	c := cache.New[int, int](10)
+	_, err := c.GetOrCompute(1, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	require.True(t, c.Has(1))
+}
+
+func TestCachePoliciesRandomized(t *testing.T) {
+	for _, policy := range []cache.Policy{cache.LRU, cache.LFU, cache.ARC} {
+		t.Run(policy.String(), func(t *testing.T) {
+			c := cache.New[int, int](50, cache.WithPolicy[int, int](policy), cache.WithCostFunc(func(key int, _ int) int64 {
+				return int64(key%3 + 1)
+			}))
+
+			for i := 0; i < 10000; i++ {
+				key := rand.Intn(200)
+
+				switch rand.Intn(4) {
+				case 0:
+					c.Delete(key)
+				case 1:
+					c.Set(key, key)
+				default:
+					if value, exists := c.Get(key); exists {
+						require.Equal(t, key, value)
+					} else {
+						c.Set(key, key)
+					}
+				}
+
+				require.LessOrEqual(t, c.Cost(), c.Capacity())
+			}
+
+			require.Greater(t, c.Stats().Hits, uint64(0))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/timed"
+)
+
+// ErrComputationPanicked is returned to the callers of GetOrCompute that waited for a computation that panicked.
+var ErrComputationPanicked = ierrors.New("computation panicked")
+
+// EvictionReason is the reason why an entry was removed from a Cache.
+type EvictionReason uint8
+
+const (
+	// EvictionReasonCapacity means that the entry was evicted because the Cache exceeded its capacity.
+	EvictionReasonCapacity EvictionReason = iota
+
+	// EvictionReasonExpired means that the time-to-live of the entry elapsed.
+	EvictionReasonExpired
+
+	// EvictionReasonDeleted means that the entry was deleted explicitly.
+	EvictionReasonDeleted
+)
+
+// String returns a human-readable representation of the EvictionReason.
+func (e EvictionReason) String() string {
+	switch e {
+	case EvictionReasonCapacity:
+		return "Capacity"
+	case EvictionReasonExpired:
+		return "Expired"
+	case EvictionReasonDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+// Stats contains the statistics of a Cache.
+type Stats struct {
+	// Hits is the number of lookups that found a cached value.
+	Hits uint64
+
+	// Misses is the number of lookups that did not find a cached value.
+	Misses uint64
+
+	// Evictions is the number of entries that were evicted because the Cache exceeded its capacity.
+	Evictions uint64
+
+	// Expirations is the number of entries that were removed because their time-to-live elapsed.
+	Expirations uint64
+}
+
+// HitRatio returns the ratio of lookups that found a cached value.
+func (s Stats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups != 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+
+	return 0
+}
+
+// entry is a cached value.
+type entry[V any] struct {
+	// value is the cached value.
+	value V
+
+	// cost is the cost of the entry.
+	cost int64
+
+	// expiresAt is the time at which the entry expires (zero if it does not expire).
+	expiresAt time.Time
+
+	// scheduledExpiry is the task that removes the entry once it expired (if a timed.Executor is used).
+	scheduledExpiry *timed.ScheduledTask
+}
+
+// isExpired returns true if the entry expired at the given time.
+func (e *entry[V]) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// cancelExpiry cancels the scheduled removal of the entry.
+func (e *entry[V]) cancelExpiry() {
+	if e.scheduledExpiry != nil {
+		e.scheduledExpiry.Cancel()
+		e.scheduledExpiry = nil
+	}
+}
+
+// evictedEntry is an entry that was removed and that is passed to the eviction callback.
+type evictedEntry[K comparable, V any] struct {
+	// key is the key of the entry.
+	key K
+
+	// value is the value of the entry.
+	value V
+
+	// reason is the reason why the entry was removed.
+	reason EvictionReason
+}
+
+// load is a pending computation of GetOrCompute.
+type load[V any] struct {
+	// done is closed once the computation finished.
+	done chan struct{}
+
+	// value is the computed value.
+	value V
+
+	// err is the error of the computation.
+	err error
+
+	// invalidated is true if the key was set or deleted during the computation (its result is then not cached).
+	invalidated bool
+}
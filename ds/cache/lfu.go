@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"github.com/iotaledger/hive.go/ds"
+)
+
+// lfu is the policy that evicts the least frequently used key (in O(1) by grouping the keys by their frequency).
+type lfu[K comparable] struct {
+	// buckets contains the non-empty frequency buckets ordered by ascending frequency.
+	buckets ds.List[*frequencyBucket[K]]
+
+	// entries contains the positions of the tracked keys.
+	entries map[K]*lfuEntry[K]
+}
+
+// newLFU creates a new lfu policy.
+func newLFU[K comparable]() *lfu[K] {
+	return &lfu[K]{
+		buckets: ds.NewList[*frequencyBucket[K]](true),
+		entries: make(map[K]*lfuEntry[K]),
+	}
+}
+
+// admit prepares the admission of the given key.
+func (l *lfu[K]) admit(K) {}
+
+// add starts tracking the given key (with a frequency of 1).
+func (l *lfu[K]) add(key K) {
+	bucketElement := l.buckets.Front()
+	if bucketElement == nil || bucketElement.Value().frequency != 1 {
+		bucketElement = l.buckets.PushFront(newFrequencyBucket[K](1))
+	}
+
+	l.entries[key] = &lfuEntry[K]{
+		bucketElement: bucketElement,
+		keyElement:    bucketElement.Value().keys.PushBack(key),
+	}
+}
+
+// access increases the frequency of the given key.
+func (l *lfu[K]) access(key K) {
+	entry, exists := l.entries[key]
+	if !exists {
+		return
+	}
+
+	currentBucket := entry.bucketElement.Value()
+
+	nextBucketElement := entry.bucketElement.Next()
+	if nextBucketElement == nil || nextBucketElement.Value().frequency != currentBucket.frequency+1 {
+		nextBucketElement = l.buckets.InsertAfter(newFrequencyBucket[K](currentBucket.frequency+1), entry.bucketElement)
+	}
+
+	l.removeFromBucket(entry)
+
+	entry.bucketElement = nextBucketElement
+	entry.keyElement = nextBucketElement.Value().keys.PushBack(key)
+}
+
+// remove stops tracking the given key.
+func (l *lfu[K]) remove(key K, _ bool) {
+	if entry, exists := l.entries[key]; exists {
+		l.removeFromBucket(entry)
+
+		delete(l.entries, key)
+	}
+}
+
+// victim returns the least recently used key among the least frequently used ones.
+func (l *lfu[K]) victim() (key K, exists bool) {
+	if bucketElement := l.buckets.Front(); bucketElement != nil {
+		return bucketElement.Value().keys.Front().Value(), true
+	}
+
+	return key, false
+}
+
+// removeFromBucket removes the given entry from its bucket (and removes the bucket if it becomes empty).
+func (l *lfu[K]) removeFromBucket(entry *lfuEntry[K]) {
+	bucket := entry.bucketElement.Value()
+	bucket.keys.Remove(entry.keyElement)
+
+	if bucket.keys.Len() == 0 {
+		l.buckets.Remove(entry.bucketElement)
+	}
+}
+
+// frequencyBucket contains the keys that were accessed with the same frequency.
+type frequencyBucket[K comparable] struct {
+	// frequency is the number of accesses of the keys in the bucket.
+	frequency uint64
+
+	// keys contains the keys ordered from the least to the most recently used one.
+	keys ds.List[K]
+}
+
+// newFrequencyBucket creates a new frequencyBucket for the given frequency.
+func newFrequencyBucket[K comparable](frequency uint64) *frequencyBucket[K] {
+	return &frequencyBucket[K]{
+		frequency: frequency,
+		keys:      ds.NewList[K](true),
+	}
+}
+
+// lfuEntry contains the position of a tracked key.
+type lfuEntry[K comparable] struct {
+	// bucketElement is the element of the bucket that contains the key.
+	bucketElement ds.ListElement[*frequencyBucket[K]]
+
+	// keyElement is the element of the key within its bucket.
+	keyElement ds.ListElement[K]
+}
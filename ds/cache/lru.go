@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"github.com/iotaledger/hive.go/ds"
+)
+
+// lru is the policy that evicts the least recently used key.
+type lru[K comparable] struct {
+	// keys contains the tracked keys ordered from the least to the most recently used one.
+	keys ds.List[K]
+
+	// elements contains the list elements of the tracked keys.
+	elements map[K]ds.ListElement[K]
+}
+
+// newLRU creates a new lru policy.
+func newLRU[K comparable]() *lru[K] {
+	return &lru[K]{
+		keys:     ds.NewList[K](true),
+		elements: make(map[K]ds.ListElement[K]),
+	}
+}
+
+// admit prepares the admission of the given key.
+func (l *lru[K]) admit(K) {}
+
+// add starts tracking the given key.
+func (l *lru[K]) add(key K) {
+	l.elements[key] = l.keys.PushBack(key)
+}
+
+// access records an access of the given key.
+func (l *lru[K]) access(key K) {
+	if element, exists := l.elements[key]; exists {
+		l.keys.MoveToBack(element)
+	}
+}
+
+// remove stops tracking the given key.
+func (l *lru[K]) remove(key K, _ bool) {
+	if element, exists := l.elements[key]; exists {
+		l.keys.Remove(element)
+
+		delete(l.elements, key)
+	}
+}
+
+// victim returns the least recently used key.
+func (l *lru[K]) victim() (key K, exists bool) {
+	if front := l.keys.Front(); front != nil {
+		return front.Value(), true
+	}
+
+	return key, false
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"fmt"
+)
+
+// Policy is the eviction policy of a Cache that decides which entry is evicted when the Cache exceeds its capacity.
+type Policy uint8
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+
+	// LFU evicts the least frequently used entry (and the least recently used one among equally frequent entries).
+	LFU
+
+	// ARC evicts entries according to the Adaptive Replacement Cache algorithm that balances between recency and
+	// frequency based on the observed access pattern.
+	ARC
+)
+
+// String returns a human-readable representation of the Policy.
+func (p Policy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case ARC:
+		return "ARC"
+	default:
+		return fmt.Sprintf("Policy(%d)", uint8(p))
+	}
+}
+
+// policy is the interface of the data structures that track the entries of a Cache according to a Policy.
+type policy[K comparable] interface {
+	// admit prepares the admission of the given key (it is called before the cache makes room for the key).
+	admit(key K)
+
+	// add starts tracking the given key.
+	add(key K)
+
+	// access records an access of the given key.
+	access(key K)
+
+	// remove stops tracking the given key (evicted is true if the key was evicted because of the capacity).
+	remove(key K, evicted bool)
+
+	// victim returns the key that shall be evicted next.
+	victim() (key K, exists bool)
+}
+
+// newPolicy creates the policy data structure for the given Policy.
+func newPolicy[K comparable](p Policy) policy[K] {
+	switch p {
+	case LRU:
+		return newLRU[K]()
+	case LFU:
+		return newLFU[K]()
+	case ARC:
+		return newARC[K]()
+	default:
+		panic(fmt.Sprintf("unknown cache policy %s", p))
+	}
+}
@@ -0,0 +1,292 @@
+package dag
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/iotaledger/hive.go/constraints"
+	"github.com/iotaledger/hive.go/ds/walker"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/syncutils"
+)
+
+var (
+	// ErrNodeExists is returned when a Node with the same identifier is attached twice.
+	ErrNodeExists = ierrors.New("node exists already")
+
+	// ErrNodeEvicted is returned when a Node is attached to an index that was evicted already.
+	ErrNodeEvicted = ierrors.New("node index was evicted")
+
+	// ErrCycleDetected is returned when attaching a Node would introduce a cycle.
+	ErrCycleDetected = ierrors.New("cycle detected")
+)
+
+// DAG is a directed acyclic graph of Nodes that reference their parents. Nodes can be attached before their parents
+// (the references are resolved once the parents are attached), and they are evicted by their index (e.g. a slot).
+//
+// The structure of the DAG is safe for concurrent readers, and the values of the Nodes are protected by a DAGMutex, so
+// a Node can be updated while its parents are read consistently (see ComputeValue).
+type DAG[ID comparable, Index constraints.Ordered, Value any] struct {
+	// nodes contains all attached Nodes.
+	nodes map[ID]*Node[ID, Index, Value]
+
+	// nodesByIndex contains the attached Nodes grouped by their index.
+	nodesByIndex map[Index]map[ID]*Node[ID, Index, Value]
+
+	// pendingChildren contains the attached Nodes that reference a parent that is not attached yet.
+	pendingChildren map[ID][]*Node[ID, Index, Value]
+
+	// attachedCount is the number of Nodes that were attached so far (used to order the Nodes by their attachment).
+	attachedCount uint64
+
+	// lastEvictedIndex is the highest index that was evicted.
+	lastEvictedIndex Index
+
+	// evicted is true if any index was evicted.
+	evicted bool
+
+	// valueMutex protects the values of the Nodes.
+	valueMutex *syncutils.DAGMutex[ID]
+
+	// mutex protects the structure of the DAG.
+	mutex sync.RWMutex
+}
+
+// New creates a new (empty) DAG.
+func New[ID comparable, Index constraints.Ordered, Value any]() *DAG[ID, Index, Value] {
+	return &DAG[ID, Index, Value]{
+		nodes:           make(map[ID]*Node[ID, Index, Value]),
+		nodesByIndex:    make(map[Index]map[ID]*Node[ID, Index, Value]),
+		pendingChildren: make(map[ID][]*Node[ID, Index, Value]),
+		valueMutex:      syncutils.NewDAGMutex[ID](),
+	}
+}
+
+// Attach adds a new Node with the given identifier, index, value and parents to the DAG. Parents that are not attached
+// yet are linked once they are attached. It returns an error if the Node exists already, if its index was evicted or if
+// it would introduce a cycle.
+func (d *DAG[ID, Index, Value]) Attach(id ID, index Index, value Value, parentIDs ...ID) (node *Node[ID, Index, Value], err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.evicted && index <= d.lastEvictedIndex {
+		return nil, ierrors.Wrapf(ErrNodeEvicted, "failed to attach node %v with index %v", id, index)
+	}
+
+	if _, exists := d.nodes[id]; exists {
+		return nil, ierrors.Wrapf(ErrNodeExists, "failed to attach node %v", id)
+	}
+
+	node = newNode(d, d.attachedCount, id, index, value, uniqueIDs(parentIDs))
+
+	missingParentIDs := make([]ID, 0)
+	for _, parentID := range node.parentIDs {
+		if parentID == id {
+			return nil, ierrors.Wrapf(ErrCycleDetected, "node %v references itself", id)
+		}
+
+		if parent, exists := d.nodes[parentID]; exists {
+			node.parents = append(node.parents, parent)
+		} else {
+			missingParentIDs = append(missingParentIDs, parentID)
+		}
+	}
+
+	pendingChildren := d.pendingChildren[id]
+	if cycleNode, cycleDetected := d.findInPastCone(node.parents, pendingChildren); cycleDetected {
+		return nil, ierrors.Wrapf(ErrCycleDetected, "node %v is both in the past and the future cone of node %v", cycleNode.id, id)
+	}
+
+	d.attachedCount++
+	d.nodes[id] = node
+	d.indexNodes(index)[id] = node
+
+	for _, parent := range node.parents {
+		parent.children = append(parent.children, node)
+	}
+
+	for _, pendingChild := range pendingChildren {
+		pendingChild.parents = append(pendingChild.parents, node)
+		node.children = append(node.children, pendingChild)
+	}
+	delete(d.pendingChildren, id)
+
+	for _, missingParentID := range missingParentIDs {
+		d.pendingChildren[missingParentID] = append(d.pendingChildren[missingParentID], node)
+	}
+
+	return node, nil
+}
+
+// Get returns the Node with the given identifier.
+func (d *DAG[ID, Index, Value]) Get(id ID) (node *Node[ID, Index, Value], exists bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	node, exists = d.nodes[id]
+
+	return node, exists
+}
+
+// Has returns true if a Node with the given identifier is attached.
+func (d *DAG[ID, Index, Value]) Has(id ID) bool {
+	return lo.Return2(d.Get(id))
+}
+
+// Size returns the number of attached Nodes.
+func (d *DAG[ID, Index, Value]) Size() int {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return len(d.nodes)
+}
+
+// MissingParents returns the identifiers of the parents that are referenced but not attached yet.
+func (d *DAG[ID, Index, Value]) MissingParents() []ID {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	missingParents := make([]ID, 0, len(d.pendingChildren))
+	for missingParent := range d.pendingChildren {
+		missingParents = append(missingParents, missingParent)
+	}
+
+	return missingParents
+}
+
+// ComputeValue updates the value of the given Node based on its current value and the values of its attached parents.
+// The Node is locked for writing and its parents for reading, so the parents can not change during the computation.
+func (d *DAG[ID, Index, Value]) ComputeValue(id ID, compute func(currentValue Value, parentValues []Value) Value) (updated bool) {
+	node, exists := d.Get(id)
+	if !exists {
+		return false
+	}
+
+	parents := node.Parents()
+	parentIDs := make([]ID, len(parents))
+	for i, parent := range parents {
+		parentIDs[i] = parent.id
+	}
+
+	d.valueMutex.Lock(id)
+	defer d.valueMutex.Unlock(id)
+
+	d.valueMutex.RLock(parentIDs...)
+	defer d.valueMutex.RUnlock(parentIDs...)
+
+	parentValues := make([]Value, len(parents))
+	for i, parent := range parents {
+		parentValues[i] = parent.value
+	}
+
+	node.value = compute(node.value, parentValues)
+
+	return true
+}
+
+// Evict removes all Nodes with an index lower than or equal to the given index and returns them (ordered by their
+// index). Nodes with an evicted index can not be attached anymore.
+func (d *DAG[ID, Index, Value]) Evict(index Index) (evictedNodes []*Node[ID, Index, Value]) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.evicted || index > d.lastEvictedIndex {
+		d.lastEvictedIndex = index
+		d.evicted = true
+	}
+
+	evictedIndexes := make([]Index, 0)
+	for candidateIndex := range d.nodesByIndex {
+		if candidateIndex <= index {
+			evictedIndexes = append(evictedIndexes, candidateIndex)
+		}
+	}
+	sort.Slice(evictedIndexes, func(i, j int) bool { return evictedIndexes[i] < evictedIndexes[j] })
+
+	evictedNodes = make([]*Node[ID, Index, Value], 0)
+	for _, evictedIndex := range evictedIndexes {
+		for _, evictedNode := range d.nodesByIndex[evictedIndex] {
+			d.detach(evictedNode)
+
+			evictedNodes = append(evictedNodes, evictedNode)
+		}
+
+		delete(d.nodesByIndex, evictedIndex)
+	}
+
+	return evictedNodes
+}
+
+// detach removes the given Node from the DAG (expects the mutex to be locked).
+func (d *DAG[ID, Index, Value]) detach(node *Node[ID, Index, Value]) {
+	delete(d.nodes, node.id)
+
+	for _, parent := range node.parents {
+		parent.removeChild(node)
+	}
+
+	for _, child := range node.children {
+		child.removeParent(node)
+	}
+
+	// the references of the evicted Node to missing parents are no longer pending
+	for _, parentID := range node.parentIDs {
+		if pendingChildren, exists := d.pendingChildren[parentID]; exists {
+			if pendingChildren = removeNode(pendingChildren, node); len(pendingChildren) == 0 {
+				delete(d.pendingChildren, parentID)
+			} else {
+				d.pendingChildren[parentID] = pendingChildren
+			}
+		}
+	}
+}
+
+// indexNodes returns the Nodes of the given index (expects the mutex to be locked).
+func (d *DAG[ID, Index, Value]) indexNodes(index Index) map[ID]*Node[ID, Index, Value] {
+	nodes, exists := d.nodesByIndex[index]
+	if !exists {
+		nodes = make(map[ID]*Node[ID, Index, Value])
+		d.nodesByIndex[index] = nodes
+	}
+
+	return nodes
+}
+
+// findInPastCone returns the first of the given targets that is part of the past cone of (or equal to) the given
+// start nodes (expects the mutex to be locked).
+func (d *DAG[ID, Index, Value]) findInPastCone(startNodes []*Node[ID, Index, Value], targets []*Node[ID, Index, Value]) (target *Node[ID, Index, Value], found bool) {
+	if len(startNodes) == 0 || len(targets) == 0 {
+		return nil, false
+	}
+
+	targetSet := make(map[*Node[ID, Index, Value]]bool, len(targets))
+	for _, target := range targets {
+		targetSet[target] = true
+	}
+
+	for nodeWalker := walker.New[*Node[ID, Index, Value]]().PushAll(startNodes...); nodeWalker.HasNext(); {
+		currentNode := nodeWalker.Next()
+		if targetSet[currentNode] {
+			return currentNode, true
+		}
+
+		nodeWalker.PushAll(currentNode.parents...)
+	}
+
+	return nil, false
+}
+
+// uniqueIDs returns the given identifiers without duplicates (while preserving their order).
+func uniqueIDs[ID comparable](ids []ID) []ID {
+	seen := make(map[ID]bool, len(ids))
+	unique := make([]ID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
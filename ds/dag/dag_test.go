@@ -0,0 +1,231 @@
+package dag_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/dag"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+)
+
+func TestDAGAttach(t *testing.T) {
+	d := dag.New[string, int, int]()
+
+	genesis := attach(t, d, "genesis", 1, 0)
+	a := attach(t, d, "a", 2, 1, "genesis")
+
+	// attach a child before its parent
+	c := attach(t, d, "c", 3, 3, "a", "b")
+	require.ElementsMatch(t, []string{"b"}, d.MissingParents())
+	require.Equal(t, []string{"a", "b"}, c.ParentIDs())
+	require.Equal(t, []string{"a"}, ids(c.Parents()))
+
+	b := attach(t, d, "b", 2, 2, "genesis")
+	require.Empty(t, d.MissingParents())
+	require.Equal(t, []string{"a", "b"}, ids(c.Parents()))
+	require.Equal(t, []string{"c"}, ids(b.Children()))
+	require.Equal(t, []string{"a", "b"}, ids(genesis.Children()))
+	require.Equal(t, []string{"c"}, ids(a.Children()))
+
+	require.Equal(t, 4, d.Size())
+	require.True(t, d.Has("b"))
+	require.False(t, d.Has("d"))
+
+	_, err := d.Attach("a", 2, 1, "genesis")
+	require.True(t, ierrors.Is(err, dag.ErrNodeExists))
+}
+
+func TestDAGCycleDetection(t *testing.T) {
+	d := dag.New[string, int, int]()
+
+	_, err := d.Attach("a", 1, 0, "a")
+	require.True(t, ierrors.Is(err, dag.ErrCycleDetected))
+
+	// a -> b -> c (where c references a that is attached last)
+	attach(t, d, "b", 1, 0, "a")
+	attach(t, d, "c", 1, 0, "b")
+
+	_, err = d.Attach("a", 1, 0, "c")
+	require.True(t, ierrors.Is(err, dag.ErrCycleDetected))
+	require.False(t, d.Has("a"))
+	require.Equal(t, []string{"a"}, d.MissingParents())
+
+	attach(t, d, "a", 1, 0)
+	require.Equal(t, []string{"b"}, ids(lo.Return1(d.Get("a")).Children()))
+}
+
+func TestDAGWalks(t *testing.T) {
+	d := newDiamond(t)
+
+	require.Equal(t, []string{"b", "c", "a"}, walk(d.PastCone, "d", ""))
+	require.Equal(t, []string{"b", "c", "d"}, walk(d.FutureCone, "a", ""))
+	require.Equal(t, []string{"d"}, walk(d.FutureCone, "c", "e"))
+	require.Empty(t, walk(d.PastCone, "a", ""))
+	require.Empty(t, walk(d.PastCone, "unknown", ""))
+
+	// pruning b still reaches a via c
+	prunedVisits := make([]string, 0)
+	d.PastCone(func(node *dag.Node[string, int, int]) dag.WalkDecision {
+		prunedVisits = append(prunedVisits, node.ID())
+		if node.ID() == "b" || node.ID() == "c" {
+			return dag.Prune
+		}
+
+		return dag.Continue
+	}, "d")
+	require.Equal(t, []string{"b", "c"}, prunedVisits)
+
+	stoppedVisits := make([]string, 0)
+	d.FutureCone(func(node *dag.Node[string, int, int]) dag.WalkDecision {
+		stoppedVisits = append(stoppedVisits, node.ID())
+
+		return dag.Stop
+	}, "a")
+	require.Equal(t, []string{"b"}, stoppedVisits)
+}
+
+func TestDAGTopologicalOrder(t *testing.T) {
+	d := dag.New[string, int, int]()
+
+	attach(t, d, "d", 3, 0, "b", "c")
+	attach(t, d, "c", 2, 0, "a")
+	attach(t, d, "b", 2, 0, "a")
+	attach(t, d, "e", 1, 0)
+	attach(t, d, "a", 1, 0)
+
+	order := ids(d.TopologicalOrder())
+	require.Equal(t, []string{"e", "a", "c", "b", "d"}, order)
+}
+
+func TestDAGEvict(t *testing.T) {
+	d := newDiamond(t)
+
+	attach(t, d, "f", 4, 0, "d", "missing")
+	require.Equal(t, []string{"missing"}, d.MissingParents())
+
+	evicted := d.Evict(2)
+	require.Len(t, evicted, 3)
+	require.Equal(t, "a", evicted[0].ID())
+	require.ElementsMatch(t, []string{"b", "c"}, ids(evicted[1:]))
+
+	require.Equal(t, 2, d.Size())
+	require.Empty(t, lo.Return1(d.Get("d")).Parents())
+	require.Equal(t, []string{"b", "c"}, lo.Return1(d.Get("d")).ParentIDs())
+
+	_, err := d.Attach("g", 2, 0)
+	require.True(t, ierrors.Is(err, dag.ErrNodeEvicted))
+
+	// a lower index does not reset the eviction
+	require.Empty(t, d.Evict(1))
+	_, err = d.Attach("g", 2, 0)
+	require.True(t, ierrors.Is(err, dag.ErrNodeEvicted))
+
+	require.Len(t, d.Evict(4), 2)
+	require.Empty(t, d.MissingParents())
+	require.Zero(t, d.Size())
+}
+
+func TestDAGComputeValue(t *testing.T) {
+	d := newDiamond(t)
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		require.True(t, d.ComputeValue(id, func(currentValue int, parentValues []int) int {
+			for _, parentValue := range parentValues {
+				currentValue += parentValue
+			}
+
+			return currentValue
+		}))
+	}
+
+	// a = 1, b = 2 + 1, c = 3 + 1, d = 4 + 3 + 4
+	require.Equal(t, 11, lo.Return1(d.Get("d")).Value())
+	require.False(t, d.ComputeValue("unknown", func(int, []int) int { return 0 }))
+}
+
+func TestDAGConcurrency(t *testing.T) {
+	d := dag.New[int, int, int]()
+
+	attach(t, d, 0, 0, 0)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		i := i
+
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+
+			_, err := d.Attach(i, i, i, i-1)
+			require.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+
+			d.PastCone(func(node *dag.Node[int, int, int]) dag.WalkDecision {
+				_ = node.Value()
+
+				return dag.Continue
+			}, i)
+			_ = d.TopologicalOrder()
+		}()
+		go func() {
+			defer wg.Done()
+
+			d.ComputeValue(i, func(currentValue int, parentValues []int) int {
+				return currentValue + len(parentValues)
+			})
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 101, d.Size())
+	require.Len(t, d.TopologicalOrder(), 101)
+	require.Len(t, walk(d.PastCone, 100, -1), 100)
+}
+
+// newDiamond creates a DAG with a single root (a), two children (b, c) and a common grandchild (d).
+func newDiamond(t *testing.T) *dag.DAG[string, int, int] {
+	d := dag.New[string, int, int]()
+
+	attach(t, d, "a", 1, 1)
+	attach(t, d, "b", 2, 2, "a")
+	attach(t, d, "c", 2, 3, "a")
+	attach(t, d, "d", 3, 4, "b", "c")
+
+	return d
+}
+
+func attach[ID comparable](t *testing.T, d *dag.DAG[ID, int, int], id ID, index int, value int, parentIDs ...ID) *dag.Node[ID, int, int] {
+	node, err := d.Attach(id, index, value, parentIDs...)
+	require.NoError(t, err)
+
+	return node
+}
+
+// walk returns the identifiers of the visited Nodes of the given walk (pruning the walk at the given identifier).
+func walk[ID comparable](walkFunc func(func(*dag.Node[ID, int, int]) dag.WalkDecision, ...ID), start ID, pruneAt ID) []ID {
+	visited := make([]ID, 0)
+	walkFunc(func(node *dag.Node[ID, int, int]) dag.WalkDecision {
+		visited = append(visited, node.ID())
+		if node.ID() == pruneAt {
+			return dag.Prune
+		}
+
+		return dag.Continue
+	}, start)
+
+	return visited
+}
+
+func ids[ID comparable](nodes []*dag.Node[ID, int, int]) []ID {
+	result := make([]ID, len(nodes))
+	for i, node := range nodes {
+		result[i] = node.ID()
+	}
+
+	return result
+}
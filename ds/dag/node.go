@@ -0,0 +1,114 @@
+package dag
+
+import (
+	"github.com/iotaledger/hive.go/constraints"
+)
+
+// Node is a node of a DAG that holds a value and references its parents.
+type Node[ID comparable, Index constraints.Ordered, Value any] struct {
+	// id is the identifier of the Node.
+	id ID
+
+	// index is the index of the Node that is used for eviction (e.g. a slot).
+	index Index
+
+	// sequence is the position of the Node in the order of attachment.
+	sequence uint64
+
+	// value is the value of the Node (protected by the DAGMutex of the DAG).
+	value Value
+
+	// parentIDs contains the identifiers of all parents (including the ones that are missing or evicted).
+	parentIDs []ID
+
+	// parents contains the parents that are attached to the DAG.
+	parents []*Node[ID, Index, Value]
+
+	// children contains the children that are attached to the DAG.
+	children []*Node[ID, Index, Value]
+
+	// dag is the DAG that the Node belongs to.
+	dag *DAG[ID, Index, Value]
+}
+
+// newNode creates a new Node.
+func newNode[ID comparable, Index constraints.Ordered, Value any](dag *DAG[ID, Index, Value], sequence uint64, id ID, index Index, value Value, parentIDs []ID) *Node[ID, Index, Value] {
+	return &Node[ID, Index, Value]{
+		id:        id,
+		index:     index,
+		sequence:  sequence,
+		value:     value,
+		parentIDs: parentIDs,
+		parents:   make([]*Node[ID, Index, Value], 0, len(parentIDs)),
+		children:  make([]*Node[ID, Index, Value], 0),
+		dag:       dag,
+	}
+}
+
+// ID returns the identifier of the Node.
+func (n *Node[ID, Index, Value]) ID() ID {
+	return n.id
+}
+
+// Index returns the index of the Node.
+func (n *Node[ID, Index, Value]) Index() Index {
+	return n.index
+}
+
+// ParentIDs returns the identifiers of all parents of the Node (including the ones that are missing or evicted).
+func (n *Node[ID, Index, Value]) ParentIDs() []ID {
+	return append(make([]ID, 0, len(n.parentIDs)), n.parentIDs...)
+}
+
+// Parents returns the parents of the Node that are attached to the DAG.
+func (n *Node[ID, Index, Value]) Parents() []*Node[ID, Index, Value] {
+	n.dag.mutex.RLock()
+	defer n.dag.mutex.RUnlock()
+
+	return append(make([]*Node[ID, Index, Value], 0, len(n.parents)), n.parents...)
+}
+
+// Children returns the children of the Node that are attached to the DAG.
+func (n *Node[ID, Index, Value]) Children() []*Node[ID, Index, Value] {
+	n.dag.mutex.RLock()
+	defer n.dag.mutex.RUnlock()
+
+	return append(make([]*Node[ID, Index, Value], 0, len(n.children)), n.children...)
+}
+
+// Value returns the value of the Node.
+func (n *Node[ID, Index, Value]) Value() Value {
+	n.dag.valueMutex.RLock(n.id)
+	defer n.dag.valueMutex.RUnlock(n.id)
+
+	return n.value
+}
+
+// SetValue sets the value of the Node.
+func (n *Node[ID, Index, Value]) SetValue(value Value) {
+	n.dag.valueMutex.Lock(n.id)
+	defer n.dag.valueMutex.Unlock(n.id)
+
+	n.value = value
+}
+
+// removeParent removes the given parent from the attached parents.
+func (n *Node[ID, Index, Value]) removeParent(parent *Node[ID, Index, Value]) {
+	n.parents = removeNode(n.parents, parent)
+}
+
+// removeChild removes the given child from the attached children.
+func (n *Node[ID, Index, Value]) removeChild(child *Node[ID, Index, Value]) {
+	n.children = removeNode(n.children, child)
+}
+
+// removeNode removes the given node from the given slice (while preserving the order of the remaining nodes).
+func removeNode[ID comparable, Index constraints.Ordered, Value any](nodes []*Node[ID, Index, Value], node *Node[ID, Index, Value]) []*Node[ID, Index, Value] {
+	for i, candidate := range nodes {
+		if candidate == node {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,87 @@
+package dag
+
+import (
+	"sort"
+
+	"github.com/iotaledger/hive.go/ds/walker"
+)
+
+// WalkDecision is returned by the visitors of a walk to control how the walk continues.
+type WalkDecision uint8
+
+const (
+	// Continue continues the walk with the neighbors of the visited Node.
+	Continue WalkDecision = iota
+
+	// Prune continues the walk without the neighbors of the visited Node.
+	Prune
+
+	// Stop aborts the walk.
+	Stop
+)
+
+// PastCone walks the past cone of the given Nodes (all of their direct and indirect parents) in breadth-first order
+// and calls the visitor for every Node exactly once. The given Nodes themselves are not visited.
+func (d *DAG[ID, Index, Value]) PastCone(visitor func(node *Node[ID, Index, Value]) WalkDecision, ids ...ID) {
+	d.walk(visitor, func(node *Node[ID, Index, Value]) []*Node[ID, Index, Value] { return node.parents }, ids)
+}
+
+// FutureCone walks the future cone of the given Nodes (all of their direct and indirect children) in breadth-first
+// order and calls the visitor for every Node exactly once. The given Nodes themselves are not visited.
+func (d *DAG[ID, Index, Value]) FutureCone(visitor func(node *Node[ID, Index, Value]) WalkDecision, ids ...ID) {
+	d.walk(visitor, func(node *Node[ID, Index, Value]) []*Node[ID, Index, Value] { return node.children }, ids)
+}
+
+// TopologicalOrder returns all attached Nodes ordered so that every Node appears after all of its attached parents
+// (Nodes without an ordering constraint are ordered by their attachment).
+func (d *DAG[ID, Index, Value]) TopologicalOrder() []*Node[ID, Index, Value] {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	orderedNodes := make([]*Node[ID, Index, Value], 0, len(d.nodes))
+	pendingParents := make(map[*Node[ID, Index, Value]]int, len(d.nodes))
+	for _, node := range d.nodes {
+		if pendingParents[node] = len(node.parents); pendingParents[node] == 0 {
+			orderedNodes = append(orderedNodes, node)
+		}
+	}
+	sort.Slice(orderedNodes, func(i, j int) bool { return orderedNodes[i].sequence < orderedNodes[j].sequence })
+
+	// the ordered nodes double as the queue of nodes whose parents were all ordered
+	for i := 0; i < len(orderedNodes); i++ {
+		for _, child := range orderedNodes[i].children {
+			if pendingParents[child]--; pendingParents[child] == 0 {
+				orderedNodes = append(orderedNodes, child)
+			}
+		}
+	}
+
+	return orderedNodes
+}
+
+// walk walks the Nodes that are reachable from the given Nodes via the given neighbors function. The visitor is called
+// without holding the lock, so it can safely access the DAG.
+func (d *DAG[ID, Index, Value]) walk(visitor func(node *Node[ID, Index, Value]) WalkDecision, neighbors func(node *Node[ID, Index, Value]) []*Node[ID, Index, Value], ids []ID) {
+	nodeWalker := walker.New[*Node[ID, Index, Value]]()
+
+	d.mutex.RLock()
+	for _, id := range ids {
+		if node, exists := d.nodes[id]; exists {
+			nodeWalker.PushAll(neighbors(node)...)
+		}
+	}
+	d.mutex.RUnlock()
+
+	for nodeWalker.HasNext() {
+		currentNode := nodeWalker.Next()
+
+		switch visitor(currentNode) {
+		case Stop:
+			return
+		case Continue:
+			d.mutex.RLock()
+			nodeWalker.PushAll(neighbors(currentNode)...)
+			d.mutex.RUnlock()
+		}
+	}
+}
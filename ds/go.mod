@@ -1,6 +1,6 @@
 module github.com/iotaledger/hive.go/ds
 
-go 1.21
+go 1.23
 
 require (
 	github.com/iotaledger/hive.go/constraints v0.0.0-20240124155826-defd9fcfcd4a
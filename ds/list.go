@@ -1,5 +1,9 @@
 package ds
 
+import (
+	"iter"
+)
+
 // region List /////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // List represents an interface for a doubly linked list.
@@ -60,9 +64,21 @@ type List[T any] interface {
 	// RangeReverse executes the given callback for the value of each element in the List in reverse order.
 	RangeReverse(callback func(value T))
 
+	// All returns a sequence of the values of all elements in the List. Every iteration works on a snapshot of the
+	// values that is taken when the iteration starts, so the List can be modified while iterating.
+	All() iter.Seq[T]
+
+	// Backward returns a sequence of the values of all elements in the List in reverse order (with the same snapshot
+	// semantics as All).
+	Backward() iter.Seq[T]
+
 	// Values returns a slice of all values in the List.
 	Values() []T
 
+	// ValuesSeq returns a sequence of the values of all elements in the List (with the same snapshot semantics as All,
+	// but named after Values which returns a slice).
+	ValuesSeq() iter.Seq[T]
+
 	// Len returns the number of elements in the List.
 	Len() int
 }
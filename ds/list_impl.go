@@ -1,6 +1,8 @@
 package ds
 
 import (
+	"iter"
+	"slices"
 	"sync"
 	"sync/atomic"
 )
@@ -239,6 +241,34 @@ func (l *list[T]) RangeReverse(callback func(value T)) {
 	}
 }
 
+// All returns a sequence of the values of all elements in the List (iterating over a snapshot of the values).
+func (l *list[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range l.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a sequence of the values of all elements in the List in reverse order (iterating over a snapshot
+// of the values).
+func (l *list[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range slices.Backward(l.Values()) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns a sequence of the values of all elements in the List (iterating over a snapshot of the values).
+func (l *list[T]) ValuesSeq() iter.Seq[T] {
+	return l.All()
+}
+
 // Values returns a slice of all values in the List.
 func (l *list[T]) Values() []T {
 	values := make([]T, 0)
@@ -470,6 +500,34 @@ func (t *threadSafeList[T]) RangeReverse(callback func(value T)) {
 	t.list.RangeReverse(callback)
 }
 
+// All returns a sequence of the values of all elements in the List (iterating over a snapshot of the values).
+func (t *threadSafeList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range t.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a sequence of the values of all elements in the List in reverse order (iterating over a snapshot
+// of the values).
+func (t *threadSafeList[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range slices.Backward(t.Values()) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns a sequence of the values of all elements in the List (iterating over a snapshot of the values).
+func (t *threadSafeList[T]) ValuesSeq() iter.Seq[T] {
+	return t.All()
+}
+
 // Values returns a slice of all values in the List.
 func (t *threadSafeList[T]) Values() []T {
 	t.mutex.RLock()
@@ -1,6 +1,7 @@
 package ds
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,32 @@ func TestList(t *testing.T) {
 	requireListElements(t, testList, []int{2, 3, 4})
 }
 
+func TestList_Iterators(t *testing.T) {
+	for _, testList := range []List[int]{NewList[int](), NewList[int](true)} {
+		for i := 1; i <= 5; i++ {
+			testList.PushBack(i)
+		}
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(testList.All()))
+		require.Equal(t, []int{5, 4, 3, 2, 1}, slices.Collect(testList.Backward()))
+		require.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(testList.ValuesSeq()))
+
+		// the list can be modified during the iteration (without affecting the running iteration)
+		iteratedValues := make([]int, 0)
+		for value := range testList.All() {
+			if value == 3 {
+				break
+			}
+
+			testList.PushBack(value * 10)
+			iteratedValues = append(iteratedValues, value)
+		}
+
+		require.Equal(t, []int{1, 2}, iteratedValues)
+		require.Equal(t, []int{1, 2, 3, 4, 5, 10, 20}, testList.Values())
+	}
+}
+
 func requireListElements[T any](t *testing.T, testList List[T], expectedValues []T) {
 	require.Equal(t, len(expectedValues), testList.Len())
 
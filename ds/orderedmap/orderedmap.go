@@ -1,9 +1,11 @@
 package orderedmap
 
 import (
+	"iter"
 	"sync"
 
 	"github.com/iotaledger/hive.go/ds/shrinkingmap"
+	"github.com/iotaledger/hive.go/lo"
 )
 
 // OrderedMap provides a concurrent-safe ordered map.
@@ -150,6 +152,56 @@ func (o *OrderedMap[K, V]) ForEachReverse(consumer func(key K, value V) bool) bo
 	return true
 }
 
+// All returns a sequence of all entries in insertion order. Every iteration works on a snapshot of the entries that is
+// taken when the iteration starts, so the map can be modified while iterating (without affecting the running
+// iteration).
+func (o *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := o.snapshot(false)
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a sequence of all entries in reverse insertion order (with the same snapshot semantics as All).
+func (o *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := o.snapshot(true)
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns a sequence of all keys in insertion order (with the same snapshot semantics as All).
+func (o *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys, _ := o.snapshot(false)
+		for _, key := range keys {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns a sequence of all values in insertion order (with the same snapshot semantics as All).
+func (o *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		_, values := o.snapshot(false)
+		for _, value := range values {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
 // Clear removes all elements from the OrderedMap.
 func (o *OrderedMap[K, V]) Clear() {
 	if o == nil {
@@ -232,3 +284,21 @@ func (o *OrderedMap[K, V]) Clone() *OrderedMap[K, V] {
 
 	return cloned
 }
+
+// snapshot returns a copy of the keys and values in insertion order (or in reverse order if reverse is true).
+func (o *OrderedMap[K, V]) snapshot(reverse bool) (keys []K, values []V) {
+	if o == nil {
+		return nil, nil
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	keys, values = make([]K, 0, o.size), make([]V, 0, o.size)
+	for currentEntry := lo.Cond(reverse, o.tail, o.head); currentEntry != nil; currentEntry = lo.Cond(reverse, currentEntry.prev, currentEntry.next) {
+		keys = append(keys, currentEntry.key)
+		values = append(values, currentEntry.value)
+	}
+
+	return keys, values
+}
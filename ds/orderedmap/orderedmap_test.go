@@ -2,6 +2,7 @@ package orderedmap_test
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
 
@@ -165,6 +166,43 @@ func TestForEach(t *testing.T) {
 	require.ElementsMatch(t, values, revValues)
 }
 
+func TestIterators(t *testing.T) {
+	orderedMap := orderedmap.New[string, int]()
+	orderedMap.Set("a", 1)
+	orderedMap.Set("b", 2)
+	orderedMap.Set("c", 3)
+
+	keys, values := make([]string, 0), make([]int, 0)
+	for key, value := range orderedMap.All() {
+		keys, values = append(keys, key), append(values, value)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, keys)
+	require.Equal(t, []int{1, 2, 3}, values)
+
+	backwardKeys := make([]string, 0)
+	for key := range orderedMap.Backward() {
+		backwardKeys = append(backwardKeys, key)
+	}
+	require.Equal(t, []string{"c", "b", "a"}, backwardKeys)
+
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(orderedMap.Keys()))
+	require.Equal(t, []int{1, 2, 3}, slices.Collect(orderedMap.Values()))
+
+	// the map can be modified during the iteration (without affecting the running iteration)
+	for key := range orderedMap.Keys() {
+		if key == "b" {
+			break
+		}
+
+		orderedMap.Delete("c")
+		orderedMap.Set("d", 4)
+	}
+	require.Equal(t, []string{"a", "b", "d"}, slices.Collect(orderedMap.Keys()))
+
+	var nilMap *orderedmap.OrderedMap[string, int]
+	require.Empty(t, slices.Collect(nilMap.Keys()))
+}
+
 func TestConcurrencySafe(t *testing.T) {
 	orderedMap := orderedmap.New[string, int]()
 	require.NotNil(t, orderedMap)
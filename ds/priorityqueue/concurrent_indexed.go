@@ -0,0 +1,152 @@
+package priorityqueue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ds/generalheap"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// ConcurrentIndexed is the thread-safe variant of the Indexed queue that additionally supports blocking pops.
+type ConcurrentIndexed[Element comparable, Priority generalheap.Comparable[Priority]] struct {
+	// queue is the underlying Indexed queue.
+	queue *Indexed[Element, Priority]
+
+	// elementAdded is closed (and replaced) whenever an element is added (to wake up waiting pops).
+	elementAdded chan struct{}
+
+	// mutex is used to synchronize access to the queue.
+	mutex sync.Mutex
+}
+
+// NewConcurrentIndexed creates a new ConcurrentIndexed queue.
+func NewConcurrentIndexed[Element comparable, Priority generalheap.Comparable[Priority]](opts ...options.Option[Indexed[Element, Priority]]) *ConcurrentIndexed[Element, Priority] {
+	return &ConcurrentIndexed[Element, Priority]{
+		queue:        NewIndexed[Element, Priority](opts...),
+		elementAdded: make(chan struct{}),
+	}
+}
+
+// Push adds the given element with the given priority (or updates the priority if the element is queued already).
+// If the queue is bounded and full, the element that would be popped last is evicted. It returns false if that is the
+// pushed element itself.
+func (c *ConcurrentIndexed[Element, Priority]) Push(element Element, priority Priority) (added bool) {
+	added, evictedEntry := c.push(element, priority)
+	if evictedEntry != nil && c.queue.optsEvictionCallback != nil {
+		c.queue.optsEvictionCallback(evictedEntry.element, evictedEntry.priority)
+	}
+
+	return added
+}
+
+// Update updates the priority of the given element and returns false if the element is not queued.
+func (c *ConcurrentIndexed[Element, Priority]) Update(element Element, priority Priority) (updated bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Update(element, priority)
+}
+
+// Remove removes the given element and returns its priority.
+func (c *ConcurrentIndexed[Element, Priority]) Remove(element Element) (priority Priority, removed bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Remove(element)
+}
+
+// Contains returns true if the given element is queued.
+func (c *ConcurrentIndexed[Element, Priority]) Contains(element Element) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Contains(element)
+}
+
+// Priority returns the priority of the given element.
+func (c *ConcurrentIndexed[Element, Priority]) Priority(element Element) (priority Priority, exists bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Priority(element)
+}
+
+// Peek returns the element that is popped next (without removing it).
+func (c *ConcurrentIndexed[Element, Priority]) Peek() (element Element, priority Priority, exists bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Peek()
+}
+
+// Pop removes and returns the element that is next in the order of the queue.
+func (c *ConcurrentIndexed[Element, Priority]) Pop() (element Element, priority Priority, exists bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Pop()
+}
+
+// PopWait removes and returns the element that is next in the order of the queue. If the queue is empty, it blocks
+// until an element is added or the context is done (in which case it returns the error of the context).
+func (c *ConcurrentIndexed[Element, Priority]) PopWait(ctx context.Context) (element Element, priority Priority, err error) {
+	for {
+		c.mutex.Lock()
+		element, priority, exists := c.queue.Pop()
+		elementAdded := c.elementAdded
+		c.mutex.Unlock()
+
+		if exists {
+			return element, priority, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return element, priority, ctx.Err()
+		case <-elementAdded:
+		}
+	}
+}
+
+// Size returns the number of queued elements.
+func (c *ConcurrentIndexed[Element, Priority]) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.Size()
+}
+
+// IsEmpty returns true if no element is queued.
+func (c *ConcurrentIndexed[Element, Priority]) IsEmpty() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.queue.IsEmpty()
+}
+
+// Capacity returns the maximum number of queued elements (0 means unbounded).
+func (c *ConcurrentIndexed[Element, Priority]) Capacity() int {
+	return c.queue.Capacity()
+}
+
+// Clear removes all elements from the queue.
+func (c *ConcurrentIndexed[Element, Priority]) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queue.Clear()
+}
+
+// push adds the given element to the queue and wakes up the waiting pops.
+func (c *ConcurrentIndexed[Element, Priority]) push(element Element, priority Priority) (added bool, evictedEntry *indexedEntry[Element, Priority]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if added, evictedEntry = c.queue.push(element, priority); added {
+		close(c.elementAdded)
+		c.elementAdded = make(chan struct{})
+	}
+
+	return added, evictedEntry
+}
@@ -0,0 +1,234 @@
+package priorityqueue
+
+import (
+	"github.com/iotaledger/hive.go/ds/generalheap"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// Order defines which elements of an Indexed queue are popped first.
+type Order uint8
+
+const (
+	// Ascending pops the element with the lowest priority value first.
+	Ascending Order = iota
+
+	// Descending pops the element with the highest priority value first.
+	Descending
+)
+
+// Indexed is a priority queue that indexes its elements, so their priorities can be updated (and elements can be
+// removed) in O(log n). It can optionally be bounded, in which case the element that would be popped last is evicted
+// once the capacity is exceeded.
+//
+// An Indexed queue is not safe for concurrent use (see ConcurrentIndexed for a thread-safe variant).
+type Indexed[Element comparable, Priority generalheap.Comparable[Priority]] struct {
+	// entries contains the entries of the queued elements.
+	entries map[Element]*indexedEntry[Element, Priority]
+
+	// popHeap orders the entries by the order in which they are popped.
+	popHeap *indexedHeap[Element, Priority]
+
+	// evictionHeap orders the entries by the order in which they are evicted (only maintained if the queue is bounded).
+	evictionHeap *indexedHeap[Element, Priority]
+
+	// optsOrder is the order in which the elements are popped.
+	optsOrder Order
+
+	// optsCapacity is the maximum number of elements (0 means unbounded).
+	optsCapacity int
+
+	// optsEvictionCallback is called for every element that is evicted because the capacity was exceeded.
+	optsEvictionCallback func(element Element, priority Priority)
+}
+
+// NewIndexed creates a new Indexed queue.
+func NewIndexed[Element comparable, Priority generalheap.Comparable[Priority]](opts ...options.Option[Indexed[Element, Priority]]) *Indexed[Element, Priority] {
+	return options.Apply(&Indexed[Element, Priority]{
+		entries: make(map[Element]*indexedEntry[Element, Priority]),
+	}, opts, func(i *Indexed[Element, Priority]) {
+		i.popHeap = newIndexedHeap[Element, Priority](popHeapSlot, i.popsBefore)
+
+		if i.optsCapacity > 0 {
+			i.evictionHeap = newIndexedHeap[Element, Priority](evictionHeapSlot, func(a, b *indexedEntry[Element, Priority]) bool {
+				return i.popsBefore(b, a)
+			})
+		}
+	})
+}
+
+// Push adds the given element with the given priority (or updates the priority if the element is queued already).
+// If the queue is bounded and full, the element that would be popped last is evicted. It returns false if that is the
+// pushed element itself.
+func (i *Indexed[Element, Priority]) Push(element Element, priority Priority) (added bool) {
+	added, evictedEntry := i.push(element, priority)
+	if evictedEntry != nil && i.optsEvictionCallback != nil {
+		i.optsEvictionCallback(evictedEntry.element, evictedEntry.priority)
+	}
+
+	return added
+}
+
+// Update updates the priority of the given element and returns false if the element is not queued.
+func (i *Indexed[Element, Priority]) Update(element Element, priority Priority) (updated bool) {
+	entry, exists := i.entries[element]
+	if !exists {
+		return false
+	}
+
+	entry.priority = priority
+
+	i.popHeap.fix(entry.positions[popHeapSlot])
+	if i.evictionHeap != nil {
+		i.evictionHeap.fix(entry.positions[evictionHeapSlot])
+	}
+
+	return true
+}
+
+// Remove removes the given element and returns its priority.
+func (i *Indexed[Element, Priority]) Remove(element Element) (priority Priority, removed bool) {
+	entry, exists := i.entries[element]
+	if !exists {
+		return priority, false
+	}
+
+	i.remove(entry)
+
+	return entry.priority, true
+}
+
+// Contains returns true if the given element is queued.
+func (i *Indexed[Element, Priority]) Contains(element Element) bool {
+	_, exists := i.entries[element]
+
+	return exists
+}
+
+// Priority returns the priority of the given element.
+func (i *Indexed[Element, Priority]) Priority(element Element) (priority Priority, exists bool) {
+	entry, exists := i.entries[element]
+	if !exists {
+		return priority, false
+	}
+
+	return entry.priority, true
+}
+
+// Peek returns the element that is popped next (without removing it).
+func (i *Indexed[Element, Priority]) Peek() (element Element, priority Priority, exists bool) {
+	if top := i.popHeap.top(); top != nil {
+		return top.element, top.priority, true
+	}
+
+	return element, priority, false
+}
+
+// Pop removes and returns the element that is next in the order of the queue.
+func (i *Indexed[Element, Priority]) Pop() (element Element, priority Priority, exists bool) {
+	top := i.popHeap.top()
+	if top == nil {
+		return element, priority, false
+	}
+
+	i.remove(top)
+
+	return top.element, top.priority, true
+}
+
+// Size returns the number of queued elements.
+func (i *Indexed[Element, Priority]) Size() int {
+	return len(i.entries)
+}
+
+// IsEmpty returns true if no element is queued.
+func (i *Indexed[Element, Priority]) IsEmpty() bool {
+	return len(i.entries) == 0
+}
+
+// Capacity returns the maximum number of queued elements (0 means unbounded).
+func (i *Indexed[Element, Priority]) Capacity() int {
+	return i.optsCapacity
+}
+
+// Clear removes all elements from the queue.
+func (i *Indexed[Element, Priority]) Clear() {
+	i.entries = make(map[Element]*indexedEntry[Element, Priority])
+
+	i.popHeap.clear()
+	if i.evictionHeap != nil {
+		i.evictionHeap.clear()
+	}
+}
+
+// push adds the given element with the given priority and returns the entry that was evicted (if any).
+func (i *Indexed[Element, Priority]) push(element Element, priority Priority) (added bool, evictedEntry *indexedEntry[Element, Priority]) {
+	if i.Update(element, priority) {
+		return true, nil
+	}
+
+	newEntry := &indexedEntry[Element, Priority]{
+		element:  element,
+		priority: priority,
+	}
+
+	if i.optsCapacity > 0 && len(i.entries) >= i.optsCapacity {
+		// the new element is rejected if it would be evicted first (ties are resolved in favor of the queued elements)
+		evictionCandidate := i.evictionHeap.top()
+		if !i.popsBefore(newEntry, evictionCandidate) {
+			return false, newEntry
+		}
+
+		i.remove(evictionCandidate)
+		evictedEntry = evictionCandidate
+	}
+
+	i.entries[element] = newEntry
+	i.popHeap.push(newEntry)
+	if i.evictionHeap != nil {
+		i.evictionHeap.push(newEntry)
+	}
+
+	return true, evictedEntry
+}
+
+// remove removes the given entry from the queue.
+func (i *Indexed[Element, Priority]) remove(entry *indexedEntry[Element, Priority]) {
+	delete(i.entries, entry.element)
+
+	i.popHeap.remove(entry)
+	if i.evictionHeap != nil {
+		i.evictionHeap.remove(entry)
+	}
+}
+
+// popsBefore returns true if the first entry is popped before the second one.
+func (i *Indexed[Element, Priority]) popsBefore(a, b *indexedEntry[Element, Priority]) bool {
+	if i.optsOrder == Descending {
+		return a.priority.CompareTo(b.priority) > 0
+	}
+
+	return a.priority.CompareTo(b.priority) < 0
+}
+
+// WithOrder sets the order in which the elements are popped (defaults to Ascending).
+func WithOrder[Element comparable, Priority generalheap.Comparable[Priority]](order Order) options.Option[Indexed[Element, Priority]] {
+	return func(i *Indexed[Element, Priority]) {
+		i.optsOrder = order
+	}
+}
+
+// WithCapacity bounds the number of queued elements (the element that would be popped last is evicted once the
+// capacity is exceeded).
+func WithCapacity[Element comparable, Priority generalheap.Comparable[Priority]](capacity int) options.Option[Indexed[Element, Priority]] {
+	return func(i *Indexed[Element, Priority]) {
+		i.optsCapacity = capacity
+	}
+}
+
+// WithEvictionCallback sets the callback that is called for every element that is evicted because the capacity was
+// exceeded (including pushed elements that are rejected right away).
+func WithEvictionCallback[Element comparable, Priority generalheap.Comparable[Priority]](callback func(element Element, priority Priority)) options.Option[Indexed[Element, Priority]] {
+	return func(i *Indexed[Element, Priority]) {
+		i.optsEvictionCallback = callback
+	}
+}
@@ -0,0 +1,135 @@
+package priorityqueue
+
+import (
+	"github.com/iotaledger/hive.go/ds/generalheap"
+)
+
+// indexedEntry is an element of an Indexed queue that knows its position in the heaps that contain it.
+type indexedEntry[Element comparable, Priority generalheap.Comparable[Priority]] struct {
+	// element is the queued element.
+	element Element
+
+	// priority is the priority of the element.
+	priority Priority
+
+	// positions contains the positions of the entry in the pop heap and the eviction heap.
+	positions [2]int
+}
+
+const (
+	// popHeapSlot is the slot of the positions of an indexedEntry that belongs to the pop heap.
+	popHeapSlot = iota
+
+	// evictionHeapSlot is the slot of the positions of an indexedEntry that belongs to the eviction heap.
+	evictionHeapSlot
+)
+
+// indexedHeap is a binary heap of indexedEntries that keeps track of the positions of its entries (so that they can be
+// fixed or removed in O(log n)).
+type indexedHeap[Element comparable, Priority generalheap.Comparable[Priority]] struct {
+	// entries contains the entries of the heap.
+	entries []*indexedEntry[Element, Priority]
+
+	// less returns true if the first entry should be at the top of the heap before the second one.
+	less func(a, b *indexedEntry[Element, Priority]) bool
+
+	// slot is the slot of the positions of the entries that is maintained by this heap.
+	slot int
+}
+
+// newIndexedHeap creates a new indexedHeap.
+func newIndexedHeap[Element comparable, Priority generalheap.Comparable[Priority]](slot int, less func(a, b *indexedEntry[Element, Priority]) bool) *indexedHeap[Element, Priority] {
+	return &indexedHeap[Element, Priority]{
+		entries: make([]*indexedEntry[Element, Priority], 0),
+		less:    less,
+		slot:    slot,
+	}
+}
+
+// push adds the given entry to the heap.
+func (h *indexedHeap[Element, Priority]) push(entry *indexedEntry[Element, Priority]) {
+	entry.positions[h.slot] = len(h.entries)
+	h.entries = append(h.entries, entry)
+
+	h.up(len(h.entries) - 1)
+}
+
+// top returns the entry at the top of the heap.
+func (h *indexedHeap[Element, Priority]) top() *indexedEntry[Element, Priority] {
+	if len(h.entries) == 0 {
+		return nil
+	}
+
+	return h.entries[0]
+}
+
+// remove removes the given entry from the heap.
+func (h *indexedHeap[Element, Priority]) remove(entry *indexedEntry[Element, Priority]) {
+	position, lastPosition := entry.positions[h.slot], len(h.entries)-1
+	if position != lastPosition {
+		h.swap(position, lastPosition)
+	}
+
+	h.entries[lastPosition] = nil
+	h.entries = h.entries[:lastPosition]
+	entry.positions[h.slot] = -1
+
+	if position != lastPosition {
+		h.fix(position)
+	}
+}
+
+// fix restores the heap order after the priority of the entry at the given position changed.
+func (h *indexedHeap[Element, Priority]) fix(position int) {
+	if !h.down(position) {
+		h.up(position)
+	}
+}
+
+// clear removes all entries from the heap.
+func (h *indexedHeap[Element, Priority]) clear() {
+	h.entries = make([]*indexedEntry[Element, Priority], 0)
+}
+
+// up moves the entry at the given position up until the heap order is restored.
+func (h *indexedHeap[Element, Priority]) up(position int) {
+	for position > 0 {
+		parent := (position - 1) / 2
+		if !h.less(h.entries[position], h.entries[parent]) {
+			break
+		}
+
+		h.swap(position, parent)
+		position = parent
+	}
+}
+
+// down moves the entry at the given position down until the heap order is restored and returns true if it was moved.
+func (h *indexedHeap[Element, Priority]) down(position int) (moved bool) {
+	startPosition := position
+	for {
+		child := 2*position + 1
+		if child >= len(h.entries) {
+			break
+		}
+
+		if right := child + 1; right < len(h.entries) && h.less(h.entries[right], h.entries[child]) {
+			child = right
+		}
+
+		if !h.less(h.entries[child], h.entries[position]) {
+			break
+		}
+
+		h.swap(position, child)
+		position = child
+	}
+
+	return position != startPosition
+}
+
+// swap swaps the entries at the given positions.
+func (h *indexedHeap[Element, Priority]) swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].positions[h.slot], h.entries[j].positions[h.slot] = i, j
+}
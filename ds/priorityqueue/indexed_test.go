@@ -0,0 +1,174 @@
+package priorityqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/priorityqueue"
+)
+
+func TestIndexed(t *testing.T) {
+	queue := priorityqueue.NewIndexed[string, priority]()
+	require.True(t, queue.IsEmpty())
+
+	require.True(t, queue.Push("a", 5))
+	require.True(t, queue.Push("b", 3))
+	require.True(t, queue.Push("c", 7))
+	require.True(t, queue.Push("d", 1))
+	require.Equal(t, 4, queue.Size())
+	require.True(t, queue.Contains("c"))
+	require.False(t, queue.Contains("e"))
+
+	// decrease the key of "c" so it is popped first
+	require.True(t, queue.Update("c", 0))
+	require.False(t, queue.Update("e", 0))
+
+	currentPriority, exists := queue.Priority("c")
+	require.True(t, exists)
+	require.Equal(t, priority(0), currentPriority)
+
+	element, elementPriority, exists := queue.Peek()
+	require.True(t, exists)
+	require.Equal(t, "c", element)
+	require.Equal(t, priority(0), elementPriority)
+
+	removedPriority, removed := queue.Remove("b")
+	require.True(t, removed)
+	require.Equal(t, priority(3), removedPriority)
+	_, removed = queue.Remove("b")
+	require.False(t, removed)
+
+	// pushing a queued element updates its priority
+	require.True(t, queue.Push("a", 2))
+	require.Equal(t, 3, queue.Size())
+
+	require.Equal(t, []string{"c", "d", "a"}, popAll(queue))
+	require.True(t, queue.IsEmpty())
+
+	_, _, exists = queue.Pop()
+	require.False(t, exists)
+}
+
+func TestIndexed_Descending(t *testing.T) {
+	queue := priorityqueue.NewIndexed[string, priority](priorityqueue.WithOrder[string, priority](priorityqueue.Descending))
+
+	queue.Push("a", 5)
+	queue.Push("b", 3)
+	queue.Push("c", 7)
+	queue.Push("d", 1)
+	queue.Update("d", 10)
+
+	require.Equal(t, []string{"d", "c", "a", "b"}, popAll(queue))
+}
+
+func TestIndexed_Capacity(t *testing.T) {
+	evicted := make(map[string]priority)
+	queue := priorityqueue.NewIndexed[string, priority](
+		priorityqueue.WithCapacity[string, priority](3),
+		priorityqueue.WithEvictionCallback[string, priority](func(element string, priority priority) {
+			evicted[element] = priority
+		}),
+	)
+	require.Equal(t, 3, queue.Capacity())
+
+	require.True(t, queue.Push("a", 5))
+	require.True(t, queue.Push("b", 3))
+	require.True(t, queue.Push("c", 7))
+
+	// the element that would be popped last is evicted
+	require.True(t, queue.Push("d", 1))
+	require.Equal(t, map[string]priority{"c": 7}, evicted)
+	require.False(t, queue.Contains("c"))
+
+	// elements that would be evicted right away are rejected
+	require.False(t, queue.Push("e", 9))
+	require.False(t, queue.Push("f", 5))
+	require.Equal(t, map[string]priority{"c": 7, "e": 9, "f": 5}, evicted)
+
+	// updates of queued elements never evict
+	require.True(t, queue.Push("a", 0))
+	require.Equal(t, 3, queue.Size())
+
+	require.Equal(t, []string{"a", "d", "b"}, popAll(queue))
+}
+
+func TestIndexed_Clear(t *testing.T) {
+	queue := priorityqueue.NewIndexed[string, priority](priorityqueue.WithCapacity[string, priority](2))
+	queue.Push("a", 1)
+	queue.Push("b", 2)
+
+	queue.Clear()
+	require.True(t, queue.IsEmpty())
+	require.False(t, queue.Contains("a"))
+
+	require.True(t, queue.Push("c", 3))
+	require.True(t, queue.Push("d", 4))
+	require.Equal(t, []string{"c", "d"}, popAll(queue))
+}
+
+func TestConcurrentIndexed_PopWait(t *testing.T) {
+	queue := priorityqueue.NewConcurrentIndexed[string, priority]()
+
+	popped := make(chan string)
+	go func() {
+		element, _, err := queue.PopWait(context.Background())
+		require.NoError(t, err)
+
+		popped <- element
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, queue.Push("a", 1))
+
+	select {
+	case element := <-popped:
+		require.Equal(t, "a", element)
+	case <-time.After(time.Second):
+		require.FailNow(t, "PopWait did not return after Push")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err := queue.PopWait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestConcurrentIndexed_Concurrency(t *testing.T) {
+	const elementCount = 1000
+
+	queue := priorityqueue.NewConcurrentIndexed[int, priority]()
+
+	go func() {
+		for i := 0; i < elementCount; i++ {
+			queue.Push(i, priority(i))
+			queue.Update(i, priority(elementCount-i))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	seen := make(map[int]bool)
+	for len(seen) < elementCount {
+		element, _, err := queue.PopWait(ctx)
+		require.NoError(t, err)
+		require.False(t, seen[element])
+
+		seen[element] = true
+	}
+
+	require.True(t, queue.IsEmpty())
+}
+
+func popAll[E comparable](queue *priorityqueue.Indexed[E, priority]) []E {
+	elements := make([]E, 0, queue.Size())
+	for element, _, exists := queue.Pop(); exists; element, _, exists = queue.Pop() {
+		elements = append(elements, element)
+	}
+
+	return elements
+}
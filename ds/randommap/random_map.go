@@ -1,6 +1,7 @@
 package randommap
 
 import (
+	"iter"
 	"math/rand"
 	"sync"
 
@@ -114,6 +115,50 @@ func (r *RandomMap[K, V]) ForEach(consumer func(key K, value V) bool) {
 	r.forEach(consumer)
 }
 
+// All returns a sequence of all entries (in no particular order). Every iteration works on a snapshot of the entries
+// that is taken when the iteration starts, so the map can be modified while iterating (without affecting the running
+// iteration).
+func (r *RandomMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		r.mutex.RLock()
+		keys, values := make([]K, 0, r.rawMap.Size()), make([]V, 0, r.rawMap.Size())
+		r.forEach(func(key K, value V) bool {
+			keys, values = append(keys, key), append(values, value)
+
+			return true
+		})
+		r.mutex.RUnlock()
+
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq returns a sequence of all keys (with the same snapshot semantics as All).
+func (r *RandomMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range r.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns a sequence of all values (with the same snapshot semantics as All).
+func (r *RandomMap[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range r.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
 // RandomKey returns a random key from the map.
 func (r *RandomMap[K, V]) RandomKey() (defaultValue K, exists bool) {
 	r.mutex.RLock()
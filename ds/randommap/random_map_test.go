@@ -1,6 +1,8 @@
 package randommap_test
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -91,6 +93,23 @@ func TestRandomMap_EmptyMap(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestRandomMap_All(t *testing.T) {
+	testMap := randommap.New[string, int]()
+	testMap.Set("a", 1)
+	testMap.Set("b", 2)
+	testMap.Set("c", 3)
+
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, maps.Collect(testMap.All()))
+	require.ElementsMatch(t, []string{"a", "b", "c"}, slices.Collect(testMap.KeysSeq()))
+	require.ElementsMatch(t, []int{1, 2, 3}, slices.Collect(testMap.ValuesSeq()))
+
+	// the map can be modified during the iteration (without affecting the running iteration)
+	for key := range testMap.All() {
+		testMap.Delete(key)
+	}
+	require.Equal(t, 0, testMap.Size())
+}
+
 func containsUniqueElements[V comparable](list []V) bool {
 	return ds.NewSet[V](list...).Size() == len(list)
 }
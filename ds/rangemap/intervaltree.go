@@ -0,0 +1,397 @@
+package rangemap
+
+import (
+	"cmp"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+)
+
+// IntervalTree is a concurrent-safe collection of (potentially overlapping) Ranges with values. It is backed by an
+// augmented AVL tree, so insertions, deletions and the lookup of the k Ranges that overlap a query take O(log n + k).
+type IntervalTree[K cmp.Ordered, V comparable] struct {
+	// root is the root node of the tree.
+	root *intervalNode[K, V]
+
+	// size is the number of entries in the tree.
+	size int
+
+	// insertedCount is the number of entries that were inserted so far (used to order equal Ranges).
+	insertedCount uint64
+
+	// mutex is used to synchronize access to the tree.
+	mutex sync.RWMutex
+}
+
+// NewIntervalTree creates a new (empty) IntervalTree.
+func NewIntervalTree[K cmp.Ordered, V comparable]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{}
+}
+
+// Insert adds the given value for the Range [start, end). Empty Ranges are ignored.
+func (i *IntervalTree[K, V]) Insert(start, end K, value V) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.insert(start, end, value)
+}
+
+// Delete removes one entry with the given Range and value and returns true if it existed.
+func (i *IntervalTree[K, V]) Delete(start, end K, value V) (deleted bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	target := i.root.find(NewRange(start, end), value)
+	if target == nil {
+		return false
+	}
+
+	i.root = i.root.delete(target.key())
+	i.size--
+
+	return true
+}
+
+// Overlapping returns the entries whose Ranges overlap the Range [start, end) ordered by their Ranges.
+func (i *IntervalTree[K, V]) Overlapping(start, end K) []Entry[K, V] {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	overlapping := make([]Entry[K, V], 0)
+	if queryRange := NewRange(start, end); !queryRange.IsEmpty() {
+		i.root.collect(func(node *intervalNode[K, V]) bool {
+			return node.maxEnd > start
+		}, func(node *intervalNode[K, V]) bool {
+			return node.entry.Range.Start < end
+		}, func(node *intervalNode[K, V]) {
+			if node.entry.Range.Overlaps(queryRange) {
+				overlapping = append(overlapping, node.entry)
+			}
+		})
+	}
+
+	return overlapping
+}
+
+// Containing returns the entries whose Ranges contain the given key ordered by their Ranges.
+func (i *IntervalTree[K, V]) Containing(key K) []Entry[K, V] {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	containing := make([]Entry[K, V], 0)
+	i.root.collect(func(node *intervalNode[K, V]) bool {
+		return node.maxEnd > key
+	}, func(node *intervalNode[K, V]) bool {
+		return node.entry.Range.Start <= key
+	}, func(node *intervalNode[K, V]) {
+		if node.entry.Range.Contains(key) {
+			containing = append(containing, node.entry)
+		}
+	})
+
+	return containing
+}
+
+// Entries returns all entries ordered by their Ranges.
+func (i *IntervalTree[K, V]) Entries() []Entry[K, V] {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	entries := make([]Entry[K, V], 0, i.size)
+	i.root.collect(func(*intervalNode[K, V]) bool { return true }, func(*intervalNode[K, V]) bool { return true }, func(node *intervalNode[K, V]) {
+		entries = append(entries, node.entry)
+	})
+
+	return entries
+}
+
+// ForEach iterates through a snapshot of the entries ordered by their Ranges and calls the consumer for every entry.
+// The iteration can be aborted by returning false in the consumer (in which case ForEach returns false).
+func (i *IntervalTree[K, V]) ForEach(consumer func(keyRange Range[K], value V) bool) bool {
+	for _, entry := range i.Entries() {
+		if !consumer(entry.Range, entry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Size returns the number of entries in the tree.
+func (i *IntervalTree[K, V]) Size() int {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	return i.size
+}
+
+// Clear removes all entries from the tree.
+func (i *IntervalTree[K, V]) Clear() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.root = nil
+	i.size = 0
+}
+
+// Encode returns a serialized byte slice of the IntervalTree (the keys and values are encoded with serix.DefaultAPI, so
+// custom types need to be registered there).
+//
+// serix only supports fixed-size integers, so IntervalTrees with int, uint or uintptr keys can not be encoded.
+func (i *IntervalTree[K, V]) Encode() ([]byte, error) {
+	return encodeEntries(i.Entries())
+}
+
+// Decode deserializes the given bytes into the IntervalTree (the IntervalTree is only modified if the bytes are valid).
+//
+// Like Encode, it does not support int, uint or uintptr keys.
+func (i *IntervalTree[K, V]) Decode(bytes []byte) (bytesRead int, err error) {
+	decodedEntries, bytesRead, err := decodeEntries[K, V](bytes)
+	if err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode IntervalTree")
+	}
+
+	decodedTree := NewIntervalTree[K, V]()
+	for _, entry := range decodedEntries {
+		if entry.Range.IsEmpty() {
+			return bytesRead, ierrors.Wrapf(ErrInvalidRange, "failed to decode IntervalTree: range %s", entry.Range)
+		}
+
+		decodedTree.insert(entry.Range.Start, entry.Range.End, entry.Value)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.root, i.size, i.insertedCount = decodedTree.root, decodedTree.size, decodedTree.insertedCount
+
+	return bytesRead, nil
+}
+
+// insert adds the given value for the Range [start, end) (expects the mutex to be locked).
+func (i *IntervalTree[K, V]) insert(start, end K, value V) {
+	if start >= end {
+		return
+	}
+
+	i.root = i.root.insert(newIntervalNode(Entry[K, V]{Range: NewRange(start, end), Value: value}, i.insertedCount))
+	i.insertedCount++
+	i.size++
+}
+
+// intervalNode is a node of the AVL tree that backs the IntervalTree.
+type intervalNode[K cmp.Ordered, V comparable] struct {
+	// entry is the entry of the node.
+	entry Entry[K, V]
+
+	// sequence is the insertion order of the entry (used to order equal Ranges).
+	sequence uint64
+
+	// maxEnd is the largest end of all Ranges in the subtree of the node.
+	maxEnd K
+
+	// height is the height of the subtree of the node.
+	height int
+
+	// left is the subtree with the smaller keys.
+	left *intervalNode[K, V]
+
+	// right is the subtree with the larger keys.
+	right *intervalNode[K, V]
+}
+
+// intervalKey is the key that is used to order the nodes.
+type intervalKey[K cmp.Ordered] struct {
+	keyRange Range[K]
+	sequence uint64
+}
+
+// newIntervalNode creates a new intervalNode.
+func newIntervalNode[K cmp.Ordered, V comparable](entry Entry[K, V], sequence uint64) *intervalNode[K, V] {
+	return &intervalNode[K, V]{
+		entry:    entry,
+		sequence: sequence,
+		maxEnd:   entry.Range.End,
+		height:   1,
+	}
+}
+
+// key returns the key of the node.
+func (n *intervalNode[K, V]) key() intervalKey[K] {
+	return intervalKey[K]{keyRange: n.entry.Range, sequence: n.sequence}
+}
+
+// compare compares the key of the node with the given key.
+func (n *intervalNode[K, V]) compare(key intervalKey[K]) int {
+	if result := compareRanges(n.entry.Range, key.keyRange); result != 0 {
+		return result
+	}
+
+	return cmp.Compare(n.sequence, key.sequence)
+}
+
+// insert inserts the given node into the subtree and returns its new root.
+func (n *intervalNode[K, V]) insert(newNode *intervalNode[K, V]) *intervalNode[K, V] {
+	if n == nil {
+		return newNode
+	}
+
+	if n.compare(newNode.key()) > 0 {
+		n.left = n.left.insert(newNode)
+	} else {
+		n.right = n.right.insert(newNode)
+	}
+
+	return n.rebalance()
+}
+
+// delete removes the node with the given key from the subtree and returns its new root.
+func (n *intervalNode[K, V]) delete(key intervalKey[K]) *intervalNode[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	switch result := n.compare(key); {
+	case result > 0:
+		n.left = n.left.delete(key)
+	case result < 0:
+		n.right = n.right.delete(key)
+	case n.left == nil:
+		return n.right
+	case n.right == nil:
+		return n.left
+	default:
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+
+		successor.right = n.right.delete(successor.key())
+		successor.left = n.left
+
+		return successor.rebalance()
+	}
+
+	return n.rebalance()
+}
+
+// find returns a node of the subtree with the given Range and value.
+func (n *intervalNode[K, V]) find(keyRange Range[K], value V) *intervalNode[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	switch result := compareRanges(n.entry.Range, keyRange); {
+	case result > 0:
+		return n.left.find(keyRange, value)
+	case result < 0:
+		return n.right.find(keyRange, value)
+	case n.entry.Value == value:
+		return n
+	default:
+		// nodes with equal Ranges (but different sequences) can be located in both subtrees
+		if leftMatch := n.left.find(keyRange, value); leftMatch != nil {
+			return leftMatch
+		}
+
+		return n.right.find(keyRange, value)
+	}
+}
+
+// collect calls the collector for the nodes of the subtree in ascending order. The subtree of a node is skipped if
+// descend returns false, and the node itself and its right subtree are skipped if continueRight returns false.
+func (n *intervalNode[K, V]) collect(descend func(node *intervalNode[K, V]) bool, continueRight func(node *intervalNode[K, V]) bool, collector func(node *intervalNode[K, V])) {
+	if n == nil || !descend(n) {
+		return
+	}
+
+	n.left.collect(descend, continueRight, collector)
+
+	if continueRight(n) {
+		collector(n)
+
+		n.right.collect(descend, continueRight, collector)
+	}
+}
+
+// rebalance restores the AVL property of the node and returns the new root of its subtree.
+func (n *intervalNode[K, V]) rebalance() *intervalNode[K, V] {
+	n.update()
+
+	switch balance := n.balance(); {
+	case balance > 1:
+		if n.left.balance() < 0 {
+			n.left = n.left.rotateLeft()
+		}
+
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.balance() > 0 {
+			n.right = n.right.rotateRight()
+		}
+
+		return n.rotateLeft()
+	default:
+		return n
+	}
+}
+
+// rotateLeft rotates the subtree to the left and returns its new root.
+func (n *intervalNode[K, V]) rotateLeft() *intervalNode[K, V] {
+	newRoot := n.right
+	n.right = newRoot.left
+	newRoot.left = n
+
+	n.update()
+	newRoot.update()
+
+	return newRoot
+}
+
+// rotateRight rotates the subtree to the right and returns its new root.
+func (n *intervalNode[K, V]) rotateRight() *intervalNode[K, V] {
+	newRoot := n.left
+	n.left = newRoot.right
+	newRoot.right = n
+
+	n.update()
+	newRoot.update()
+
+	return newRoot
+}
+
+// update recomputes the height and the largest end of the subtree.
+func (n *intervalNode[K, V]) update() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+
+	n.maxEnd = n.entry.Range.End
+	if n.left != nil {
+		n.maxEnd = max(n.maxEnd, n.left.maxEnd)
+	}
+	if n.right != nil {
+		n.maxEnd = max(n.maxEnd, n.right.maxEnd)
+	}
+}
+
+// balance returns the difference between the heights of the left and the right subtree.
+func (n *intervalNode[K, V]) balance() int {
+	return n.left.getHeight() - n.right.getHeight()
+}
+
+// getHeight returns the height of the subtree (0 for an empty subtree).
+func (n *intervalNode[K, V]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+// compareRanges orders Ranges by their start and then by their end.
+func compareRanges[K cmp.Ordered](a, b Range[K]) int {
+	if result := cmp.Compare(a.Start, b.Start); result != 0 {
+		return result
+	}
+
+	return cmp.Compare(a.End, b.End)
+}
@@ -0,0 +1,127 @@
+package rangemap_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/rangemap"
+)
+
+func TestIntervalTree(t *testing.T) {
+	tree := rangemap.NewIntervalTree[int, string]()
+
+	tree.Insert(10, 20, "validator1")
+	tree.Insert(15, 30, "validator2")
+	tree.Insert(15, 30, "validator3")
+	tree.Insert(40, 50, "validator1")
+	tree.Insert(5, 5, "ignored")
+	require.Equal(t, 4, tree.Size())
+
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(10, 20, "validator1"),
+		entry(15, 30, "validator2"),
+		entry(15, 30, "validator3"),
+	}, tree.Containing(15))
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(15, 30, "validator2"),
+		entry(15, 30, "validator3"),
+	}, tree.Containing(20))
+	require.Empty(t, tree.Containing(30))
+
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(15, 30, "validator2"),
+		entry(15, 30, "validator3"),
+		entry(40, 50, "validator1"),
+	}, tree.Overlapping(25, 45))
+	require.Empty(t, tree.Overlapping(30, 40))
+
+	require.True(t, tree.Delete(15, 30, "validator3"))
+	require.False(t, tree.Delete(15, 30, "validator3"))
+	require.False(t, tree.Delete(15, 31, "validator2"))
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(10, 20, "validator1"),
+		entry(15, 30, "validator2"),
+	}, tree.Containing(15))
+
+	tree.Clear()
+	require.Zero(t, tree.Size())
+	require.Empty(t, tree.Entries())
+}
+
+func TestIntervalTreeRandomized(t *testing.T) {
+	tree := rangemap.NewIntervalTree[int, int]()
+	expected := make([]rangemap.Entry[int, int], 0)
+
+	for i := 0; i < 1000; i++ {
+		if len(expected) > 0 && rand.Intn(3) == 0 {
+			deletedIndex := rand.Intn(len(expected))
+			deleted := expected[deletedIndex]
+
+			require.True(t, tree.Delete(deleted.Range.Start, deleted.Range.End, deleted.Value))
+			expected = append(expected[:deletedIndex], expected[deletedIndex+1:]...)
+		} else {
+			start := rand.Intn(1000)
+			inserted := entry(start, start+1+rand.Intn(100), i)
+
+			tree.Insert(inserted.Range.Start, inserted.Range.End, inserted.Value)
+			expected = append(expected, inserted)
+		}
+
+		queryStart := rand.Intn(1000)
+		queryEnd := queryStart + rand.Intn(100)
+		require.ElementsMatch(t, filter(expected, func(e rangemap.Entry[int, int]) bool {
+			return e.Range.Overlaps(rangemap.NewRange(queryStart, queryEnd))
+		}), tree.Overlapping(queryStart, queryEnd))
+		require.ElementsMatch(t, filter(expected, func(e rangemap.Entry[int, int]) bool {
+			return e.Range.Contains(queryStart)
+		}), tree.Containing(queryStart))
+	}
+
+	entries := tree.Entries()
+	require.Len(t, entries, len(expected))
+	require.True(t, sort.SliceIsSorted(entries, func(i, j int) bool {
+		return entries[i].Range.Start < entries[j].Range.Start
+	}))
+}
+
+func TestIntervalTreeSerialization(t *testing.T) {
+	tree := rangemap.NewIntervalTree[int64, string]()
+	tree.Insert(1, 10, "a")
+	tree.Insert(5, 15, "b")
+	tree.Insert(5, 15, "c")
+
+	bytes, err := tree.Encode()
+	require.NoError(t, err)
+
+	decoded := rangemap.NewIntervalTree[int64, string]()
+	bytesRead, err := decoded.Decode(bytes)
+	require.NoError(t, err)
+	require.Equal(t, len(bytes), bytesRead)
+	require.Equal(t, tree.Entries(), decoded.Entries())
+
+	// failed decodes leave the IntervalTree untouched
+	_, err = decoded.Decode(bytes[:len(bytes)-1])
+	require.Error(t, err)
+	require.Equal(t, tree.Entries(), decoded.Entries())
+
+	// keys without a fixed size are not supported
+	unsupported := rangemap.NewIntervalTree[uint, string]()
+	unsupported.Insert(1, 10, "a")
+
+	_, err = unsupported.Encode()
+	require.Error(t, err)
+}
+
+func filter(entries []rangemap.Entry[int, int], predicate func(e rangemap.Entry[int, int]) bool) []rangemap.Entry[int, int] {
+	filtered := make([]rangemap.Entry[int, int], 0)
+	for _, e := range entries {
+		if predicate(e) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
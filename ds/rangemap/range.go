@@ -0,0 +1,49 @@
+package rangemap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Range is a half-open interval [Start, End) of keys.
+type Range[K cmp.Ordered] struct {
+	// Start is the first key of the Range.
+	Start K `serix:""`
+
+	// End is the first key after the Range.
+	End K `serix:""`
+}
+
+// NewRange creates a new Range [start, end).
+func NewRange[K cmp.Ordered](start, end K) Range[K] {
+	return Range[K]{Start: start, End: end}
+}
+
+// IsEmpty returns true if the Range does not contain any key.
+func (r Range[K]) IsEmpty() bool {
+	return r.Start >= r.End
+}
+
+// Contains returns true if the given key is part of the Range.
+func (r Range[K]) Contains(key K) bool {
+	return r.Start <= key && key < r.End
+}
+
+// Overlaps returns true if the Range shares at least one key with the other Range.
+func (r Range[K]) Overlaps(other Range[K]) bool {
+	return r.Start < other.End && other.Start < r.End && !r.IsEmpty() && !other.IsEmpty()
+}
+
+// String returns a human-readable version of the Range.
+func (r Range[K]) String() string {
+	return fmt.Sprintf("[%v, %v)", r.Start, r.End)
+}
+
+// Entry is a Range with an associated value.
+type Entry[K cmp.Ordered, V any] struct {
+	// Range is the Range of the Entry.
+	Range Range[K]
+
+	// Value is the value that is associated with the Range.
+	Value V
+}
@@ -0,0 +1,296 @@
+package rangemap
+
+import (
+	"cmp"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/hive.go/serializer/v2/serix"
+)
+
+// ErrInvalidRange is returned when a decoded Range is empty or overlaps a previous Range.
+var ErrInvalidRange = ierrors.New("invalid range")
+
+// fieldTypeSettings are the TypeSettings that are used to encode the keys and values of the entries (strings and slices
+// are prefixed with their length as an uint32).
+var fieldTypeSettings = serix.TypeSettings{}.WithLengthPrefixType(serix.LengthPrefixTypeAsUint32)
+
+// RangeMap is a concurrent-safe map from non-overlapping Ranges of keys to values. Setting a Range overwrites (and
+// splits) the Ranges that it overlaps, and adjacent Ranges with equal values are merged automatically.
+type RangeMap[K cmp.Ordered, V comparable] struct {
+	// entries contains the non-overlapping entries ordered by their Ranges.
+	entries []Entry[K, V]
+
+	// mutex is used to synchronize access to the entries.
+	mutex sync.RWMutex
+}
+
+// New creates a new (empty) RangeMap.
+func New[K cmp.Ordered, V comparable]() *RangeMap[K, V] {
+	return &RangeMap[K, V]{
+		entries: make([]Entry[K, V], 0),
+	}
+}
+
+// Set maps the given value to all keys in the Range [start, end). Parts of existing Ranges that overlap the new Range
+// are overwritten, and the new Range is merged with adjacent Ranges that have an equal value.
+func (r *RangeMap[K, V]) Set(start, end K, value V) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.set(start, end, value)
+}
+
+// Remove removes all keys in the Range [start, end) (splitting the Ranges that only partially overlap it).
+func (r *RangeMap[K, V]) Remove(start, end K) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if start >= end {
+		return
+	}
+
+	firstIndex, lastIndex := r.overlapping(start, end)
+	leftRemainder, rightRemainder := r.remainders(firstIndex, lastIndex, start, end)
+
+	r.replace(firstIndex, lastIndex, append(leftRemainder, rightRemainder...))
+}
+
+// Get returns the value that is mapped to the given key.
+func (r *RangeMap[K, V]) Get(key K) (value V, exists bool) {
+	_, value, exists = r.GetRange(key)
+
+	return value, exists
+}
+
+// GetRange returns the Range that contains the given key and its value.
+func (r *RangeMap[K, V]) GetRange(key K) (containingRange Range[K], value V, exists bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if index := r.search(key); index < len(r.entries) && r.entries[index].Range.Contains(key) {
+		return r.entries[index].Range, r.entries[index].Value, true
+	}
+
+	return containingRange, value, false
+}
+
+// Has returns true if the given key is part of any Range.
+func (r *RangeMap[K, V]) Has(key K) bool {
+	_, _, exists := r.GetRange(key)
+
+	return exists
+}
+
+// Overlapping returns the entries whose Ranges overlap the Range [start, end) in ascending order.
+func (r *RangeMap[K, V]) Overlapping(start, end K) []Entry[K, V] {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if start >= end {
+		return make([]Entry[K, V], 0)
+	}
+
+	firstIndex, lastIndex := r.overlapping(start, end)
+
+	return append(make([]Entry[K, V], 0, lastIndex-firstIndex), r.entries[firstIndex:lastIndex]...)
+}
+
+// Entries returns all entries in ascending order.
+func (r *RangeMap[K, V]) Entries() []Entry[K, V] {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append(make([]Entry[K, V], 0, len(r.entries)), r.entries...)
+}
+
+// ForEach iterates through a snapshot of the entries in ascending order and calls the consumer for every entry.
+// The iteration can be aborted by returning false in the consumer (in which case ForEach returns false).
+func (r *RangeMap[K, V]) ForEach(consumer func(keyRange Range[K], value V) bool) bool {
+	for _, entry := range r.Entries() {
+		if !consumer(entry.Range, entry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Size returns the number of (non-overlapping) Ranges.
+func (r *RangeMap[K, V]) Size() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.entries)
+}
+
+// Clear removes all Ranges.
+func (r *RangeMap[K, V]) Clear() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = make([]Entry[K, V], 0)
+}
+
+// Encode returns a serialized byte slice of the RangeMap (the keys and values are encoded with serix.DefaultAPI, so
+// custom types need to be registered there).
+//
+// serix only supports fixed-size integers, so RangeMaps with int, uint or uintptr keys can not be encoded.
+func (r *RangeMap[K, V]) Encode() ([]byte, error) {
+	return encodeEntries(r.Entries())
+}
+
+// Decode deserializes the given bytes into the RangeMap (the RangeMap is only modified if the bytes are valid).
+//
+// Like Encode, it does not support int, uint or uintptr keys.
+func (r *RangeMap[K, V]) Decode(bytes []byte) (bytesRead int, err error) {
+	decodedEntries, bytesRead, err := decodeEntries[K, V](bytes)
+	if err != nil {
+		return bytesRead, ierrors.Wrap(err, "failed to decode RangeMap")
+	}
+
+	decodedMap := New[K, V]()
+	for i, entry := range decodedEntries {
+		if entry.Range.IsEmpty() || (i > 0 && entry.Range.Start < decodedEntries[i-1].Range.End) {
+			return bytesRead, ierrors.Wrapf(ErrInvalidRange, "failed to decode RangeMap: range %s", entry.Range)
+		}
+
+		decodedMap.set(entry.Range.Start, entry.Range.End, entry.Value)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = decodedMap.entries
+
+	return bytesRead, nil
+}
+
+// set maps the given value to all keys in the Range [start, end) (expects the mutex to be locked).
+func (r *RangeMap[K, V]) set(start, end K, value V) {
+	if start >= end {
+		return
+	}
+
+	firstIndex, lastIndex := r.overlapping(start, end)
+	leftRemainder, rightRemainder := r.remainders(firstIndex, lastIndex, start, end)
+	newEntry := Entry[K, V]{Range: NewRange(start, end), Value: value}
+
+	// merge the new entry with the remainders of the overwritten entries or its adjacent neighbors (if they are equal)
+	if len(leftRemainder) != 0 && leftRemainder[0].Value == value {
+		newEntry.Range.Start, leftRemainder = leftRemainder[0].Range.Start, nil
+	} else if firstIndex > 0 && r.entries[firstIndex-1].Range.End == start && r.entries[firstIndex-1].Value == value {
+		firstIndex--
+		newEntry.Range.Start = r.entries[firstIndex].Range.Start
+	}
+
+	if len(rightRemainder) != 0 && rightRemainder[0].Value == value {
+		newEntry.Range.End, rightRemainder = rightRemainder[0].Range.End, nil
+	} else if lastIndex < len(r.entries) && r.entries[lastIndex].Range.Start == end && r.entries[lastIndex].Value == value {
+		newEntry.Range.End = r.entries[lastIndex].Range.End
+		lastIndex++
+	}
+
+	r.replace(firstIndex, lastIndex, append(append(leftRemainder, newEntry), rightRemainder...))
+}
+
+// overlapping returns the index range [firstIndex, lastIndex) of the entries that overlap the Range [start, end)
+// (expects the mutex to be locked).
+func (r *RangeMap[K, V]) overlapping(start, end K) (firstIndex, lastIndex int) {
+	firstIndex = r.search(start)
+	lastIndex = firstIndex + sort.Search(len(r.entries)-firstIndex, func(i int) bool {
+		return r.entries[firstIndex+i].Range.Start >= end
+	})
+
+	return firstIndex, lastIndex
+}
+
+// search returns the index of the first entry that ends after the given key (expects the mutex to be locked).
+func (r *RangeMap[K, V]) search(key K) int {
+	return sort.Search(len(r.entries), func(i int) bool {
+		return r.entries[i].Range.End > key
+	})
+}
+
+// remainders returns the parts of the given entries that are not covered by the Range [start, end) on its left and
+// on its right side (each containing at most one entry) (expects the mutex to be locked).
+func (r *RangeMap[K, V]) remainders(firstIndex, lastIndex int, start, end K) (leftRemainder, rightRemainder []Entry[K, V]) {
+	if firstIndex == lastIndex {
+		return nil, nil
+	}
+
+	if firstEntry := r.entries[firstIndex]; firstEntry.Range.Start < start {
+		leftRemainder = []Entry[K, V]{{Range: NewRange(firstEntry.Range.Start, start), Value: firstEntry.Value}}
+	}
+
+	if lastEntry := r.entries[lastIndex-1]; lastEntry.Range.End > end {
+		rightRemainder = []Entry[K, V]{{Range: NewRange(end, lastEntry.Range.End), Value: lastEntry.Value}}
+	}
+
+	return leftRemainder, rightRemainder
+}
+
+// replace replaces the entries in the index range [firstIndex, lastIndex) with the given entries (expects the mutex to
+// be locked).
+func (r *RangeMap[K, V]) replace(firstIndex, lastIndex int, replacements []Entry[K, V]) {
+	updatedEntries := make([]Entry[K, V], 0, len(r.entries)-(lastIndex-firstIndex)+len(replacements))
+	updatedEntries = append(updatedEntries, r.entries[:firstIndex]...)
+	updatedEntries = append(updatedEntries, replacements...)
+	updatedEntries = append(updatedEntries, r.entries[lastIndex:]...)
+
+	r.entries = updatedEntries
+}
+
+// encodeEntries returns a serialized byte slice of the given entries (prefixed with their number).
+func encodeEntries[K cmp.Ordered, V any](entries []Entry[K, V]) ([]byte, error) {
+	seri := serializer.NewSerializer()
+
+	seri.WriteNum(uint32(len(entries)), func(err error) error {
+		return ierrors.Wrap(err, "failed to write entry count")
+	})
+
+	for _, entry := range entries {
+		for _, field := range []any{entry.Range.Start, entry.Range.End, entry.Value} {
+			fieldBytes, err := serix.DefaultAPI.Encode(context.Background(), field, serix.WithTypeSettings(fieldTypeSettings))
+			if err != nil {
+				return nil, ierrors.Wrap(err, "failed to encode entry")
+			}
+
+			seri.WriteBytes(fieldBytes, func(err error) error {
+				return ierrors.Wrap(err, "failed to write entry")
+			})
+		}
+	}
+
+	return seri.Serialize()
+}
+
+// decodeEntries deserializes the entries from the given bytes.
+func decodeEntries[K cmp.Ordered, V any](bytes []byte) (entries []Entry[K, V], bytesRead int, err error) {
+	var entryCount uint32
+	if bytesRead, err = serix.DefaultAPI.Decode(context.Background(), bytes, &entryCount); err != nil {
+		return nil, bytesRead, ierrors.Wrap(err, "failed to read entry count")
+	}
+
+	// every entry consists of at least one byte, so we do not allocate more memory than the remaining bytes can fill
+	if int(entryCount) > len(bytes)-bytesRead {
+		return nil, bytesRead, ierrors.Wrap(serializer.ErrDeserializationNotEnoughData, "failed to read entries")
+	}
+
+	entries = make([]Entry[K, V], entryCount)
+	for i := range entries {
+		for _, field := range []any{&entries[i].Range.Start, &entries[i].Range.End, &entries[i].Value} {
+			fieldBytesRead, fieldErr := serix.DefaultAPI.Decode(context.Background(), bytes[bytesRead:], field, serix.WithTypeSettings(fieldTypeSettings))
+			if fieldErr != nil {
+				return nil, bytesRead, ierrors.Wrapf(fieldErr, "failed to read entry %d", i)
+			}
+
+			bytesRead += fieldBytesRead
+		}
+	}
+
+	return entries, bytesRead, nil
+}
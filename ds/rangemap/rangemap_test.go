@@ -0,0 +1,170 @@
+package rangemap_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/rangemap"
+	"github.com/iotaledger/hive.go/ierrors"
+)
+
+func TestRangeMap(t *testing.T) {
+	r := rangemap.New[int, string]()
+
+	r.Set(100, 250, "pruned")
+	r.Set(300, 400, "pruned")
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 250, "pruned"),
+		entry(300, 400, "pruned"),
+	}, r.Entries())
+
+	// filling the gap merges the adjacent ranges with equal values
+	r.Set(250, 300, "pruned")
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 400, "pruned"),
+	}, r.Entries())
+
+	// setting a different value splits the range
+	r.Set(200, 210, "active")
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 200, "pruned"),
+		entry(200, 210, "active"),
+		entry(210, 400, "pruned"),
+	}, r.Entries())
+
+	value, exists := r.Get(205)
+	require.True(t, exists)
+	require.Equal(t, "active", value)
+
+	containingRange, value, exists := r.GetRange(210)
+	require.True(t, exists)
+	require.Equal(t, "pruned", value)
+	require.Equal(t, rangemap.NewRange(210, 400), containingRange)
+
+	require.False(t, r.Has(400))
+	require.False(t, r.Has(99))
+	require.True(t, r.Has(100))
+
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 200, "pruned"),
+		entry(200, 210, "active"),
+	}, r.Overlapping(150, 205))
+	require.Empty(t, r.Overlapping(0, 100))
+	require.Empty(t, r.Overlapping(150, 150))
+
+	// overwriting a range with the value of its neighbors merges all of them
+	r.Set(195, 215, "pruned")
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 400, "pruned"),
+	}, r.Entries())
+
+	// removing a subrange splits the range
+	r.Remove(150, 160)
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(100, 150, "pruned"),
+		entry(160, 400, "pruned"),
+	}, r.Entries())
+
+	r.Remove(0, 155)
+	r.Remove(399, 1000)
+	require.Equal(t, []rangemap.Entry[int, string]{
+		entry(160, 399, "pruned"),
+	}, r.Entries())
+
+	// empty ranges are ignored
+	r.Set(500, 500, "ignored")
+	require.Equal(t, 1, r.Size())
+
+	r.Clear()
+	require.Zero(t, r.Size())
+}
+
+func TestRangeMapRandomized(t *testing.T) {
+	const keySpace = 200
+
+	r := rangemap.New[int, int]()
+	expected := make([]*int, keySpace)
+
+	for i := 0; i < 2000; i++ {
+		start := rand.Intn(keySpace)
+		end := start + rand.Intn(keySpace-start+1)
+
+		if rand.Intn(3) == 0 {
+			r.Remove(start, end)
+			for key := start; key < end; key++ {
+				expected[key] = nil
+			}
+		} else {
+			value := rand.Intn(3)
+
+			r.Set(start, end, value)
+			for key := start; key < end; key++ {
+				expected[key] = &value
+			}
+		}
+
+		for key, expectedValue := range expected {
+			value, exists := r.Get(key)
+			require.Equal(t, expectedValue != nil, exists)
+			if exists {
+				require.Equal(t, *expectedValue, value)
+			}
+		}
+
+		// the ranges are ordered, non-overlapping and adjacent ranges have different values
+		entries := r.Entries()
+		for j := 1; j < len(entries); j++ {
+			require.LessOrEqual(t, entries[j-1].Range.End, entries[j].Range.Start)
+			require.False(t, entries[j-1].Range.End == entries[j].Range.Start && entries[j-1].Value == entries[j].Value)
+		}
+	}
+}
+
+func TestRangeMapSerialization(t *testing.T) {
+	r := rangemap.New[uint32, string]()
+	r.Set(1, 5, "a")
+	r.Set(10, 20, "b")
+	r.Set(20, 30, "c")
+
+	bytes, err := r.Encode()
+	require.NoError(t, err)
+
+	decoded := rangemap.New[uint32, string]()
+	bytesRead, err := decoded.Decode(bytes)
+	require.NoError(t, err)
+	require.Equal(t, len(bytes), bytesRead)
+	require.Equal(t, r.Entries(), decoded.Entries())
+
+	// overlapping ranges are rejected
+	invalid := rangemap.NewIntervalTree[uint32, string]()
+	invalid.Insert(1, 10, "a")
+	invalid.Insert(5, 15, "b")
+
+	bytes, err = invalid.Encode()
+	require.NoError(t, err)
+
+	_, err = decoded.Decode(bytes)
+	require.True(t, ierrors.Is(err, rangemap.ErrInvalidRange))
+
+	_, err = decoded.Decode(bytes[:len(bytes)-1])
+	require.Error(t, err)
+
+	// failed decodes leave the RangeMap untouched
+	require.Equal(t, r.Entries(), decoded.Entries())
+
+	// keys without a fixed size are not supported
+	unsupported := rangemap.New[int, string]()
+	unsupported.Set(1, 5, "a")
+
+	_, err = unsupported.Encode()
+	require.Error(t, err)
+
+	_, err = unsupported.Decode(bytes)
+	require.Error(t, err)
+}
+
+func entry[K int | uint32 | int64, V any](start, end K, value V) rangemap.Entry[K, V] {
+	return rangemap.Entry[K, V]{Range: rangemap.NewRange(start, end), Value: value}
+}
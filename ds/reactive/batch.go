@@ -0,0 +1,277 @@
+package reactive
+
+import (
+	"cmp"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/ds/priorityqueue"
+)
+
+// region Batch ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Batch executes the given function as a transaction scope that collects the updates of the reactive Variables, Sets
+// and Maps that joined the Transaction and that propagates them once the scope ends.
+//
+// Values are updated immediately, but the subscribers of the joined elements are only notified when the scope ends.
+// The collected updates are propagated in topological order (inputs before the elements that are derived from them)
+// and every subscriber is called at most once per element with the value before the batch and the final value
+// (updates that cancel each other out are not propagated at all). The elements that are derived from an updated
+// element (with the constructors and InheritFrom methods of this package) join the Transaction while it propagates, so
+// consumers of derived values never observe inconsistent intermediate values.
+//
+// Only the joined elements are affected: updates of all other elements are propagated immediately, no matter which
+// goroutine makes them.
+func Batch(batchFunc func(tx *Transaction)) {
+	tx := &Transaction{
+		pendingUpdates: priorityqueue.New[batchedElement, batchPriority](),
+		joinedElements: make([]*elementRank, 0),
+	}
+	defer tx.propagate()
+
+	batchFunc(tx)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region Transaction //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Transaction is the scope of a Batch that collects the updates of the elements that joined it.
+type Transaction struct {
+	// pendingUpdates contains the elements with collected updates (ordered by their rank).
+	pendingUpdates *priorityqueue.PriorityQueue[batchedElement, batchPriority]
+
+	// joinedElements contains the elements whose updates are collected by the Transaction.
+	joinedElements []*elementRank
+
+	// propagationRank is the rank of the element whose updates are currently propagated.
+	propagationRank int
+
+	// isPropagating is true while the collected updates are propagated.
+	isPropagating bool
+
+	// isDone is true once all collected updates were propagated.
+	isDone bool
+
+	// sequence is used to propagate the updates of elements with the same rank in the order they were collected.
+	sequence uint64
+
+	// mutex is used to synchronize the access to the fields of the Transaction.
+	mutex sync.Mutex
+}
+
+// Join makes the Transaction collect the updates of the given reactive elements (Variables, Sets and Maps) until the
+// Batch ends - including the updates that other goroutines make in the meantime.
+//
+// Elements that already joined another open Transaction stay part of it (their updates are propagated when the other
+// Batch ends). It panics if an element can not join a Transaction.
+func (t *Transaction) Join(elements ...any) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, element := range elements {
+		joiningElement, canJoin := element.(interface{ graphIdentity() *elementRank })
+		if !canJoin {
+			panic(fmt.Sprintf("%T can not join a Transaction", element))
+		}
+
+		t.join(joiningElement.graphIdentity())
+	}
+}
+
+// join makes the Transaction collect the updates of the given element (if it did not join another Transaction yet).
+func (t *Transaction) join(element *elementRank) {
+	if !t.isDone && element.transaction.CompareAndSwap(nil, t) {
+		t.joinedElements = append(t.joinedElements, element)
+	}
+}
+
+// collect collects the updates of the given element and returns false if the Transaction is done already.
+func (t *Transaction) collect(element batchedElement) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.isDone {
+		return false
+	}
+
+	rank := element.rank()
+	if t.isPropagating {
+		// elements that are updated during the propagation depend on the currently propagated element (even if their
+		// rank is outdated)
+		rank = max(rank, t.propagationRank+1)
+	}
+
+	t.sequence++
+	t.pendingUpdates.Push(element, batchPriority{rank: rank, sequence: t.sequence})
+
+	return true
+}
+
+// propagate propagates the collected updates in topological order.
+func (t *Transaction) propagate() {
+	t.mutex.Lock()
+	t.isPropagating = true
+	t.mutex.Unlock()
+
+	for element, exists := t.next(); exists; element, exists = t.next() {
+		element.triggerBatchedUpdate()
+	}
+}
+
+// next returns the next element whose updates should be propagated (after its dependents joined the Transaction) and
+// releases the joined elements once all updates were propagated.
+func (t *Transaction) next() (element batchedElement, exists bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if element, exists = t.pendingUpdates.Pop(); exists {
+		t.propagationRank = max(t.propagationRank, element.rank())
+
+		for _, dependent := range element.graphIdentity().dependents() {
+			t.join(dependent)
+		}
+
+		return element, true
+	}
+
+	t.isDone = true
+	for _, joinedElement := range t.joinedElements {
+		joinedElement.transaction.CompareAndSwap(t, nil)
+	}
+	t.joinedElements = nil
+
+	return element, false
+}
+
+// collectInTransaction collects the updates of the given element in the Transaction that it joined and returns false
+// if it did not join an open Transaction.
+func collectInTransaction(element batchedElement) bool {
+	tx := element.graphIdentity().transaction.Load()
+
+	return tx != nil && tx.collect(element)
+}
+
+// joinTransactionOf makes the given element join the open Transaction of the given other element (if there is one).
+func joinTransactionOf(element, other *elementRank) {
+	if tx := other.transaction.Load(); tx != nil {
+		tx.mutex.Lock()
+		defer tx.mutex.Unlock()
+
+		tx.join(element)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region batchedElement ///////////////////////////////////////////////////////////////////////////////////////////////
+
+// batchedElement is a reactive element whose updates can be collected by a batch.
+type batchedElement interface {
+	// rank returns the position of the element in the dependency graph (elements are ranked higher than their inputs).
+	rank() int
+
+	// triggerBatchedUpdate notifies the subscribers about the collected updates.
+	triggerBatchedUpdate()
+
+	// graphIdentity returns the elementRank of the element (which tracks the Transaction that it joined).
+	graphIdentity() *elementRank
+}
+
+// batchPriority is the priority of a batchedElement in the queue of pending updates.
+type batchPriority struct {
+	rank     int
+	sequence uint64
+}
+
+// CompareTo compares the priority to another priority (lower ranks are propagated first).
+func (b batchPriority) CompareTo(other batchPriority) int {
+	if result := cmp.Compare(b.rank, other.rank); result != 0 {
+		return result
+	}
+
+	return cmp.Compare(b.sequence, other.sequence)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region elementRank //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// elementRank tracks the position of a reactive element in the dependency graph and the Transaction that it joined.
+type elementRank struct {
+	value atomic.Int64
+
+	// transaction is the open Transaction that collects the updates of the element (nil if there is none).
+	transaction atomic.Pointer[Transaction]
+
+	// dependentElements contains the elements that are derived from the element (with the number of their edges).
+	dependentElements map[*elementRank]int
+
+	// dependentElementsMutex is used to synchronize the access to the dependentElements.
+	dependentElementsMutex sync.Mutex
+}
+
+// rank returns the rank of the element.
+func (e *elementRank) rank() int {
+	return int(e.value.Load())
+}
+
+// raiseRank raises the rank of the element above the ranks of the given inputs (inputs that are not tracked by the
+// dependency graph are ignored).
+func (e *elementRank) raiseRank(inputs ...any) {
+	for _, input := range inputs {
+		rankedInput, isRanked := input.(interface{ rank() int })
+		if !isRanked {
+			continue
+		}
+
+		for inputRank := int64(rankedInput.rank()); ; {
+			if currentRank := e.value.Load(); currentRank > inputRank || e.value.CompareAndSwap(currentRank, inputRank+1) {
+				break
+			}
+		}
+	}
+}
+
+// dependents returns the elements that are derived from the element.
+func (e *elementRank) dependents() []*elementRank {
+	e.dependentElementsMutex.Lock()
+	defer e.dependentElementsMutex.Unlock()
+
+	dependents := make([]*elementRank, 0, len(e.dependentElements))
+	for dependent := range e.dependentElements {
+		dependents = append(dependents, dependent)
+	}
+
+	return dependents
+}
+
+// addDependent registers the given element as derived from the element and returns a function that removes it again.
+func (e *elementRank) addDependent(dependent *elementRank) (remove func()) {
+	e.dependentElementsMutex.Lock()
+	defer e.dependentElementsMutex.Unlock()
+
+	if e.dependentElements == nil {
+		e.dependentElements = make(map[*elementRank]int)
+	}
+	e.dependentElements[dependent]++
+
+	return func() {
+		e.dependentElementsMutex.Lock()
+		defer e.dependentElementsMutex.Unlock()
+
+		if e.dependentElements[dependent]--; e.dependentElements[dependent] <= 0 {
+			delete(e.dependentElements, dependent)
+		}
+	}
+}
+
+// rankAbove raises the rank of the given element above the ranks of the given inputs.
+func rankAbove(element any, inputs ...any) {
+	if rankedElement, isRanked := element.(interface{ raiseRank(inputs ...any) }); isRanked {
+		rankedElement.raiseRank(inputs...)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,170 @@
+package reactive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds"
+)
+
+func TestBatch(t *testing.T) {
+	input1 := NewVariable[int]().Init(1)
+	input2 := NewVariable[int]().Init(2)
+
+	sum := NewDerivedVariable2(func(_ int, input1 int, input2 int) int {
+		return input1 + input2
+	}, input1, input2)
+
+	// diamond: doubled depends on input1 and combined depends on both input1 and doubled
+	doubled := NewDerivedVariable(func(_ int, input1 int) int {
+		return input1 * 2
+	}, input1)
+	combined := NewDerivedVariable2(func(_ int, input1 int, doubled int) int {
+		return input1*10 + doubled
+	}, input1, doubled)
+
+	type update struct{ oldValue, newValue int }
+	sumUpdates, combinedUpdates := make([]update, 0), make([]update, 0)
+
+	sum.OnUpdate(func(oldValue, newValue int) {
+		sumUpdates = append(sumUpdates, update{oldValue, newValue})
+	})
+	combined.OnUpdate(func(oldValue, newValue int) {
+		// every observed value is consistent
+		require.Equal(t, input1.Get()*12, newValue)
+
+		combinedUpdates = append(combinedUpdates, update{oldValue, newValue})
+	})
+	sumUpdates, combinedUpdates = sumUpdates[:0], combinedUpdates[:0]
+
+	Batch(func(tx *Transaction) {
+		tx.Join(input1, input2)
+
+		input1.Set(3)
+		input2.Set(4)
+
+		// values are updated immediately, but notifications are deferred
+		require.Equal(t, 3, input1.Get())
+		require.Empty(t, sumUpdates)
+
+		// elements that joined the outer batch stay part of it
+		Batch(func(tx *Transaction) {
+			tx.Join(input1)
+
+			input1.Set(5)
+		})
+		require.Empty(t, sumUpdates)
+	})
+
+	require.Equal(t, []update{{3, 9}}, sumUpdates)
+	require.Equal(t, []update{{12, 60}}, combinedUpdates)
+
+	// updates that cancel each other out are not propagated
+	Batch(func(tx *Transaction) {
+		tx.Join(input1, input2)
+
+		input1.Set(7)
+		input2.Set(2)
+		input1.Set(5)
+		input2.Set(4)
+	})
+	require.Equal(t, []update{{3, 9}}, sumUpdates)
+	require.Equal(t, []update{{12, 60}}, combinedUpdates)
+
+	// updates outside of batches are propagated immediately
+	input2.Set(5)
+	require.Equal(t, []update{{3, 9}, {9, 10}}, sumUpdates)
+}
+
+func TestBatchSet(t *testing.T) {
+	source1 := NewSet[int](1)
+	source2 := NewSet[int]()
+
+	derived := NewDerivedSet[int]()
+	derived.InheritFrom(source1, source2)
+
+	updates := make([]ds.SetMutations[int], 0)
+	derived.OnUpdate(func(appliedMutations ds.SetMutations[int]) {
+		updates = append(updates, appliedMutations)
+	})
+	updates = updates[:0]
+
+	Batch(func(tx *Transaction) {
+		tx.Join(source1, source2)
+
+		source1.Add(2)
+		source2.Add(3)
+		source1.Delete(1)
+		source2.Add(4)
+		source2.Delete(4)
+	})
+
+	require.Len(t, updates, 1)
+	require.True(t, updates[0].AddedElements().Equals(ds.NewSet(2, 3)))
+	require.True(t, updates[0].DeletedElements().Equals(ds.NewSet(1)))
+
+	Batch(func(tx *Transaction) {
+		tx.Join(source1)
+
+		source1.Add(5)
+		source1.Delete(5)
+	})
+	require.Len(t, updates, 1)
+}
+
+func TestBatchUnrelatedUpdates(t *testing.T) {
+	batched := NewVariable[int]()
+	unrelated := NewVariable[int]()
+
+	batchedUpdates, unrelatedUpdates := make(chan int, 2), make(chan int, 2)
+	batched.OnUpdate(func(_, newValue int) { batchedUpdates <- newValue })
+	unrelated.OnUpdate(func(_, newValue int) { unrelatedUpdates <- newValue })
+
+	Batch(func(tx *Transaction) {
+		tx.Join(batched)
+
+		batched.Set(1)
+
+		// updates of elements that did not join the batch are propagated immediately (by any goroutine)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			unrelated.Set(2)
+		}()
+		<-done
+
+		require.Equal(t, 2, <-unrelatedUpdates)
+
+		unrelated.Set(3)
+		require.Equal(t, 3, <-unrelatedUpdates)
+
+		// updates of joined elements are collected (by any goroutine)
+		done = make(chan struct{})
+		go func() {
+			defer close(done)
+
+			batched.Set(4)
+		}()
+		<-done
+
+		require.Empty(t, batchedUpdates)
+	})
+
+	require.Equal(t, 4, <-batchedUpdates)
+	require.Empty(t, batchedUpdates)
+	require.Nil(t, batched.(*variable[int]).transaction.Load())
+
+	// updates after the batch are propagated immediately
+	batched.Set(5)
+	require.Equal(t, 5, <-batchedUpdates)
+}
+
+func TestBatchJoin(t *testing.T) {
+	require.Panics(t, func() {
+		Batch(func(tx *Transaction) {
+			tx.Join(1)
+		})
+	})
+}
@@ -4,8 +4,8 @@ import "github.com/iotaledger/hive.go/lo"
 
 // counter is the default implementation of the Counter interface.
 type counter[InputType comparable] struct {
-	// Variable holds the counter value.
-	Variable[int]
+	// variable holds the counter value.
+	*variable[int]
 
 	// condition is the condition that is used to determine whether the input value fulfills the counted criteria.
 	condition func(inputValue InputType) bool
@@ -14,7 +14,7 @@ type counter[InputType comparable] struct {
 // newCounter creates a counter that counts the number of times monitored input values fulfill a certain condition.
 func newCounter[InputType comparable](condition ...func(inputValue InputType) bool) *counter[InputType] {
 	return &counter[InputType]{
-		Variable: NewVariable[int](),
+		variable: newVariable[int](),
 		condition: lo.First(condition, func(newInputValue InputType) bool {
 			var zeroValue InputType
 			return newInputValue != zeroValue
@@ -27,19 +27,23 @@ func newCounter[InputType comparable](condition ...func(inputValue InputType) bo
 func (c *counter[InputType]) Monitor(input ReadableVariable[InputType]) (unsubscribe func()) {
 	var conditionWasTrue bool
 
-	return input.OnUpdate(func(_, newInputValue InputType) {
-		c.Compute(func(currentValue int) int {
-			if conditionIsTrue := c.condition(newInputValue); conditionIsTrue != conditionWasTrue {
-				if conditionIsTrue {
-					currentValue++
-				} else {
-					currentValue--
-				}
+	return lo.Batch(
+		dependsOn(c, "Monitor", input),
+
+		input.OnUpdate(func(_, newInputValue InputType) {
+			c.Compute(func(currentValue int) int {
+				if conditionIsTrue := c.condition(newInputValue); conditionIsTrue != conditionWasTrue {
+					if conditionIsTrue {
+						currentValue++
+					} else {
+						currentValue--
+					}
 
-				conditionWasTrue = conditionIsTrue
-			}
+					conditionWasTrue = conditionIsTrue
+				}
 
-			return currentValue
-		})
-	}, true)
+				return currentValue
+			})
+		}, true),
+	)
 }
@@ -0,0 +1,146 @@
+package reactive
+
+import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+)
+
+// region dispatcher ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// dispatcher executes the callbacks of a subscription in a WorkerPool while preserving the order in which they were
+// triggered (callbacks that have not started before the subscription is unsubscribed are skipped).
+type dispatcher struct {
+	// workerPool is the WorkerPool that executes the functions.
+	workerPool *workerpool.WorkerPool
+
+	// pendingFuncs contains the functions that are waiting to be executed.
+	pendingFuncs []func()
+
+	// isExecuting is true if a task that executes the pending functions was submitted to the WorkerPool.
+	isExecuting bool
+
+	// isUnsubscribed is true if the subscription of the dispatched callbacks was unsubscribed.
+	isUnsubscribed bool
+
+	// mutex is used to synchronize access to the pending functions.
+	mutex sync.Mutex
+}
+
+// newDispatcher creates a new dispatcher for the given WorkerPool.
+func newDispatcher(workerPool *workerpool.WorkerPool) *dispatcher {
+	return &dispatcher{
+		workerPool:   workerPool,
+		pendingFuncs: make([]func(), 0),
+	}
+}
+
+// subscription returns an unsubscribe function that stops the dispatched callbacks before it calls the given one.
+func (d *dispatcher) subscription(unsubscribe func()) func() {
+	return func() {
+		d.mutex.Lock()
+		d.isUnsubscribed = true
+		d.mutex.Unlock()
+
+		unsubscribe()
+	}
+}
+
+// dispatchCallback schedules the given callback for execution unless the subscription is unsubscribed before it runs.
+func (d *dispatcher) dispatchCallback(callback func()) {
+	d.dispatch(func() {
+		if !d.unsubscribed() {
+			callback()
+		}
+	})
+}
+
+// dispatch schedules the given function for execution (after all previously dispatched functions).
+func (d *dispatcher) dispatch(pendingFunc func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.pendingFuncs = append(d.pendingFuncs, pendingFunc)
+
+	// only one task executes the pending functions at a time (to preserve their order)
+	if !d.isExecuting {
+		d.submit()
+	}
+}
+
+// submit submits a task that executes the pending functions to the WorkerPool (the pending functions are dropped if the
+// WorkerPool is not running, just like the tasks that are submitted to a stopped WorkerPool).
+func (d *dispatcher) submit() {
+	d.isExecuting = true
+
+	// reset the state if the task was not submitted (or the WorkerPool panicked because it was shut down in the
+	// meantime), so that the next dispatched function submits a new task
+	submitted := false
+	defer func() {
+		if !submitted {
+			d.isExecuting = false
+			d.pendingFuncs = d.pendingFuncs[:0]
+		}
+	}()
+
+	if d.workerPool.IsRunning() {
+		d.workerPool.Submit(d.executePendingFuncs)
+		submitted = true
+	}
+}
+
+// executePendingFuncs executes the pending functions until there are no more.
+func (d *dispatcher) executePendingFuncs() {
+	for nextFunc := d.nextPendingFunc(); nextFunc != nil; nextFunc = d.nextPendingFunc() {
+		nextFunc()
+	}
+}
+
+// nextPendingFunc returns the next pending function (or nil if there is none).
+func (d *dispatcher) nextPendingFunc() func() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.pendingFuncs) == 0 {
+		d.isExecuting = false
+
+		return nil
+	}
+
+	nextFunc := d.pendingFuncs[0]
+	d.pendingFuncs[0] = nil
+	d.pendingFuncs = d.pendingFuncs[1:]
+
+	return nextFunc
+}
+
+// unsubscribed returns true if the subscription of the dispatched callbacks was unsubscribed.
+func (d *dispatcher) unsubscribed() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.isUnsubscribed
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region utils ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// dispatchSetup returns a setup function that executes the given setup function and its teardown with the given
+// dispatcher (so a teardown never runs before its setup, and setups that were skipped have nothing to tear down).
+func dispatchSetup[Type any](d *dispatcher, setup func(value Type) (teardown func())) func(value Type) (teardown func()) {
+	return func(value Type) (teardown func()) {
+		var dispatchedTeardown func()
+		d.dispatchCallback(func() { dispatchedTeardown = setup(value) })
+
+		return func() {
+			d.dispatch(func() {
+				if dispatchedTeardown != nil {
+					dispatchedTeardown()
+				}
+			})
+		}
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
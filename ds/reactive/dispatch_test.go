@@ -0,0 +1,183 @@
+package reactive
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+)
+
+func TestOnUpdateDispatched(t *testing.T) {
+	workerPool := workerpool.New(t.Name()).Start()
+	defer workerPool.Shutdown()
+
+	variable := NewVariable[int]()
+
+	var updatesMutex sync.Mutex
+	updates := make([][2]int, 0)
+
+	releaseSubscriber := make(chan struct{})
+	variable.OnUpdateDispatched(workerPool, func(oldValue, newValue int) {
+		<-releaseSubscriber
+
+		updatesMutex.Lock()
+		defer updatesMutex.Unlock()
+
+		updates = append(updates, [2]int{oldValue, newValue})
+	})
+
+	// the slow subscriber does not stall the producer
+	for i := 1; i <= 100; i++ {
+		variable.Set(i)
+	}
+	close(releaseSubscriber)
+
+	require.Eventually(t, func() bool {
+		updatesMutex.Lock()
+		defer updatesMutex.Unlock()
+
+		return len(updates) == 100
+	}, 5*time.Second, time.Millisecond)
+
+	// the updates are delivered in order
+	for i, update := range updates {
+		require.Equal(t, [2]int{i, i + 1}, update)
+	}
+}
+
+func TestSetOnUpdateDispatched(t *testing.T) {
+	workerPool := workerpool.New(t.Name()).Start()
+	defer workerPool.Shutdown()
+
+	set := NewSet[int]()
+
+	var elementsMutex sync.Mutex
+	elements := ds.NewSet[int]()
+
+	set.OnUpdateDispatched(workerPool, func(appliedMutations ds.SetMutations[int]) {
+		elementsMutex.Lock()
+		defer elementsMutex.Unlock()
+
+		elements.Apply(appliedMutations)
+	})
+
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+		set.Delete(i - 1)
+	}
+
+	require.Eventually(t, func() bool {
+		elementsMutex.Lock()
+		defer elementsMutex.Unlock()
+
+		return elements.Equals(ds.NewSet(99))
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestWithValueDispatched(t *testing.T) {
+	workerPool := workerpool.New(t.Name()).Start()
+	defer workerPool.Shutdown()
+
+	variable := NewVariable[int]()
+
+	var activeValuesMutex sync.Mutex
+	activeValues := make(map[int]bool)
+
+	teardown := variable.WithValueDispatched(workerPool, func(value int) (teardown func()) {
+		activeValuesMutex.Lock()
+		defer activeValuesMutex.Unlock()
+
+		activeValues[value] = true
+
+		return func() {
+			activeValuesMutex.Lock()
+			defer activeValuesMutex.Unlock()
+
+			delete(activeValues, value)
+		}
+	})
+
+	for i := 1; i <= 100; i++ {
+		variable.Set(i)
+	}
+
+	// the teardown of every value runs after its setup
+	require.Eventually(t, func() bool {
+		activeValuesMutex.Lock()
+		defer activeValuesMutex.Unlock()
+
+		return len(activeValues) == 1 && activeValues[100]
+	}, 5*time.Second, time.Millisecond)
+
+	teardown()
+
+	require.Eventually(t, func() bool {
+		activeValuesMutex.Lock()
+		defer activeValuesMutex.Unlock()
+
+		return len(activeValues) == 0
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestOnUpdateDispatchedUnsubscribe(t *testing.T) {
+	workerPool := workerpool.New(t.Name(), workerpool.WithWorkerCount(1)).Start()
+	defer workerPool.Shutdown()
+
+	variable := NewVariable[int]()
+
+	// block the only worker so that the dispatched updates stay pending
+	releaseWorker := make(chan struct{})
+	workerPool.Submit(func() { <-releaseWorker })
+
+	var updatesCount atomic.Int32
+	unsubscribe := variable.OnUpdateDispatched(workerPool, func(_, _ int) {
+		updatesCount.Add(1)
+	})
+
+	for i := 1; i <= 10; i++ {
+		variable.Set(i)
+	}
+
+	// the pending updates are skipped once the subscription is unsubscribed
+	unsubscribe()
+	close(releaseWorker)
+
+	workerPool.PendingTasksCounter.WaitIsZero()
+	require.Zero(t, updatesCount.Load())
+}
+
+func TestOnUpdateDispatchedStoppedWorkerPool(t *testing.T) {
+	workerPool := workerpool.New(t.Name())
+	defer workerPool.Shutdown()
+
+	variable := NewVariable[int]()
+
+	var updatesMutex sync.Mutex
+	updates := make([]int, 0)
+
+	variable.OnUpdateDispatched(workerPool, func(_, newValue int) {
+		updatesMutex.Lock()
+		defer updatesMutex.Unlock()
+
+		updates = append(updates, newValue)
+	})
+
+	// the update is dropped because the WorkerPool is not running yet
+	variable.Set(1)
+
+	// the updates are delivered once the WorkerPool is started
+	workerPool.Start()
+	variable.Set(2)
+
+	require.Eventually(t, func() bool {
+		updatesMutex.Lock()
+		defer updatesMutex.Unlock()
+
+		return len(updates) == 1 && updates[0] == 2
+	}, 5*time.Second, time.Millisecond)
+}
@@ -5,16 +5,36 @@ type EvictionState[Type EvictionStateSlotType] interface {
 	// LastEvictedSlot returns a reactive variable that contains the index of the last evicted slot.
 	LastEvictedSlot() Variable[Type]
 
+	// RetentionWindow returns the number of slots that are retained below the latest slot when the EvictionState is
+	// advanced.
+	RetentionWindow() Type
+
+	// IsEvicted returns true if the given slot was evicted.
+	IsEvicted(slot Type) bool
+
 	// EvictionEvent returns the event that is triggered when the given slot was evicted.
 	EvictionEvent(slot Type) Event
 
-	// Evict evicts the given slot and triggers the corresponding eviction events.
-	Evict(slot Type)
+	// OnEvict registers a cleanup function that is executed when the given slot is evicted (cleanups are executed in
+	// slot order and immediately if the slot was already evicted).
+	OnEvict(slot Type, cleanup func()) (unsubscribe func())
+
+	// OnSlotEvicted registers a callback that is triggered for every evicted slot (in ascending order).
+	OnSlotEvicted(callback func(slot Type)) (unsubscribe func())
+
+	// Advance evicts all slots that fall out of the retention window when the given slot becomes the latest slot and
+	// returns the evicted slots.
+	Advance(latestSlot Type) (evictedSlots []Type)
+
+	// Evict evicts all slots up to (and including) the given slot, executes their cleanups, triggers the corresponding
+	// eviction events and returns the evicted slots (in ascending order).
+	Evict(slot Type) (evictedSlots []Type)
 }
 
-// NewEvictionState creates a new EvictionState instance.
-func NewEvictionState[Type EvictionStateSlotType]() EvictionState[Type] {
-	return newEvictionState[Type]()
+// NewEvictionState creates a new EvictionState instance with an optional retention window (the number of slots that
+// are retained below the latest slot when the EvictionState is advanced).
+func NewEvictionState[Type EvictionStateSlotType](retentionWindow ...Type) EvictionState[Type] {
+	return newEvictionState[Type](retentionWindow...)
 }
 
 // EvictionStateSlotType represents a constraint for the slot type of EvictionState.
@@ -1,7 +1,11 @@
 package reactive
 
 import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/ds"
 	"github.com/iotaledger/hive.go/ds/shrinkingmap"
+	"github.com/iotaledger/hive.go/lo"
 )
 
 // evictionState is the default implementation of the EvictionState interface.
@@ -9,15 +13,30 @@ type evictionState[Type EvictionStateSlotType] struct {
 	// lastEvictedSlot is the index of the last evicted slot.
 	lastEvictedSlot Variable[Type]
 
+	// retentionWindow is the number of slots that are retained below the latest slot when the evictionState is advanced.
+	retentionWindow Type
+
 	// evictionEvents is the map of all eviction events that were not evicted yet.
 	evictionEvents *shrinkingmap.ShrinkingMap[Type, Event]
+
+	// cleanups is the map of all cleanup functions of the slots that were not evicted yet.
+	cleanups *shrinkingmap.ShrinkingMap[Type, ds.List[*callback[func()]]]
+
+	// slotEvictedCallbacks holds the callbacks that are triggered for every evicted slot.
+	slotEvictedCallbacks ds.List[*callback[func(slot Type)]]
+
+	// evictionMutex is used to make sure that evicted slots are processed in ascending order.
+	evictionMutex sync.Mutex
 }
 
 // newEvictionState creates a new evictionState instance.
-func newEvictionState[Type EvictionStateSlotType]() *evictionState[Type] {
+func newEvictionState[Type EvictionStateSlotType](retentionWindow ...Type) *evictionState[Type] {
 	return &evictionState[Type]{
-		lastEvictedSlot: NewVariable[Type](),
-		evictionEvents:  shrinkingmap.New[Type, Event](),
+		lastEvictedSlot:      NewVariable[Type](),
+		retentionWindow:      lo.First(retentionWindow),
+		evictionEvents:       shrinkingmap.New[Type, Event](),
+		cleanups:             shrinkingmap.New[Type, ds.List[*callback[func()]]](),
+		slotEvictedCallbacks: ds.NewList[*callback[func(slot Type)]](),
 	}
 }
 
@@ -26,14 +45,27 @@ func (e *evictionState[Type]) LastEvictedSlot() Variable[Type] {
 	return e.lastEvictedSlot
 }
 
+// RetentionWindow returns the number of slots that are retained below the latest slot when the EvictionState is
+// advanced.
+func (e *evictionState[Type]) RetentionWindow() Type {
+	return e.retentionWindow
+}
+
+// IsEvicted returns true if the given slot was evicted.
+func (e *evictionState[Type]) IsEvicted(slot Type) (isEvicted bool) {
+	e.lastEvictedSlot.Read(func(lastEvictedSlotIndex Type) {
+		isEvicted = e.isEvicted(slot, lastEvictedSlotIndex)
+	})
+
+	return isEvicted
+}
+
 // EvictionEvent returns the event that is triggered when the given slot was evicted.
 func (e *evictionState[Type]) EvictionEvent(slot Type) Event {
 	evictionEvent := evictedSlotEvent
 
 	e.lastEvictedSlot.Read(func(lastEvictedSlotIndex Type) {
-		var zeroValue Type
-
-		if slot > lastEvictedSlotIndex || (slot == zeroValue && lastEvictedSlotIndex == zeroValue) {
+		if !e.isEvicted(slot, lastEvictedSlotIndex) {
 			evictionEvent, _ = e.evictionEvents.GetOrCreate(slot, NewEvent)
 		}
 	})
@@ -41,31 +73,157 @@ func (e *evictionState[Type]) EvictionEvent(slot Type) Event {
 	return evictionEvent
 }
 
-// Evict evicts the given slot and triggers the corresponding eviction events.
-func (e *evictionState[Type]) Evict(slot Type) {
-	for _, slotEvictedEvent := range e.evict(slot) {
-		slotEvictedEvent.Trigger()
+// OnEvict registers a cleanup function that is executed when the given slot is evicted (cleanups are executed in slot
+// order and immediately if the slot was already evicted).
+func (e *evictionState[Type]) OnEvict(slot Type, cleanup func()) (unsubscribe func()) {
+	var cleanups ds.List[*callback[func()]]
+	var cleanupElement ds.ListElement[*callback[func()]]
+
+	createdCallback := newCallback[func()](cleanup)
+
+	e.lastEvictedSlot.Read(func(lastEvictedSlotIndex Type) {
+		if !e.isEvicted(slot, lastEvictedSlotIndex) {
+			cleanups, _ = e.cleanups.GetOrCreate(slot, func() ds.List[*callback[func()]] {
+				return ds.NewList[*callback[func()]]()
+			})
+
+			cleanupElement = cleanups.PushBack(createdCallback)
+		}
+	})
+
+	if cleanups == nil {
+		cleanup()
+
+		return func() {}
+	}
+
+	return func() {
+		cleanups.Remove(cleanupElement)
+
+		createdCallback.MarkUnsubscribed()
+	}
+}
+
+// OnSlotEvicted registers a callback that is triggered for every evicted slot (in ascending order).
+func (e *evictionState[Type]) OnSlotEvicted(callback func(slot Type)) (unsubscribe func()) {
+	createdCallback := newCallback[func(slot Type)](callback)
+	callbackElement := e.slotEvictedCallbacks.PushBack(createdCallback)
+
+	return func() {
+		e.slotEvictedCallbacks.Remove(callbackElement)
+
+		createdCallback.MarkUnsubscribed()
 	}
 }
 
+// Advance evicts all slots that fall out of the retention window when the given slot becomes the latest slot and
+// returns the evicted slots.
+func (e *evictionState[Type]) Advance(latestSlot Type) (evictedSlots []Type) {
+	if latestSlot < e.retentionWindow {
+		return nil
+	}
+
+	return e.Evict(latestSlot - e.retentionWindow)
+}
+
+// Evict evicts all slots up to (and including) the given slot, executes their cleanups, triggers the corresponding
+// eviction events and returns the evicted slots (in ascending order).
+func (e *evictionState[Type]) Evict(slot Type) (evictedSlots []Type) {
+	e.evictionMutex.Lock()
+	defer e.evictionMutex.Unlock()
+
+	evictedSlotDetails := e.evictSlots(slot)
+	slotEvictedCallbacks := e.slotEvictedCallbacks.Values()
+
+	evictedSlots = make([]Type, 0, len(evictedSlotDetails))
+	for _, evictedSlot := range evictedSlotDetails {
+		for _, cleanup := range evictedSlot.cleanups {
+			if cleanup.LockExecution(0) {
+				cleanup.Invoke()
+				cleanup.UnlockExecution()
+			}
+		}
+
+		if evictedSlot.event != nil {
+			evictedSlot.event.Trigger()
+		}
+
+		for _, slotEvictedCallback := range slotEvictedCallbacks {
+			if slotEvictedCallback.LockExecution(0) {
+				slotEvictedCallback.Invoke(evictedSlot.slot)
+				slotEvictedCallback.UnlockExecution()
+			}
+		}
+
+		evictedSlots = append(evictedSlots, evictedSlot.slot)
+	}
+
+	return evictedSlots
+}
+
 // evict advances the lastEvictedSlot to the given slot and returns the events that shall be triggered.
 func (e *evictionState[Type]) evict(slot Type) (eventsToTrigger []Event) {
+	for _, evictedSlot := range e.evictSlots(slot) {
+		if evictedSlot.event != nil {
+			eventsToTrigger = append(eventsToTrigger, evictedSlot.event)
+		}
+	}
+
+	return eventsToTrigger
+}
+
+// evictSlots advances the lastEvictedSlot to the given slot and returns the details of all evicted slots.
+func (e *evictionState[Type]) evictSlots(slot Type) (evictedSlots []*evictedSlot[Type]) {
 	e.lastEvictedSlot.Compute(func(lastEvictedSlotIndex Type) Type {
 		if slot <= lastEvictedSlotIndex {
 			return lastEvictedSlotIndex
 		}
 
-		for i := lastEvictedSlotIndex + Type(1); i <= slot; i++ {
+		// slot 0 is only considered evicted once a later slot is evicted, so it is processed by the first eviction
+		firstSlot := lastEvictedSlotIndex + Type(1)
+		if lastEvictedSlotIndex == *new(Type) {
+			firstSlot = lastEvictedSlotIndex
+		}
+
+		for i := firstSlot; i <= slot; i++ {
+			evictedSlot := &evictedSlot[Type]{slot: i}
+
 			if slotEvictedEvent, exists := e.evictionEvents.Get(i); exists {
-				eventsToTrigger = append(eventsToTrigger, slotEvictedEvent)
+				evictedSlot.event = slotEvictedEvent
 				e.evictionEvents.Delete(i)
 			}
+
+			if cleanups, exists := e.cleanups.Get(i); exists {
+				evictedSlot.cleanups = cleanups.Values()
+				e.cleanups.Delete(i)
+			}
+
+			evictedSlots = append(evictedSlots, evictedSlot)
 		}
 
 		return slot
 	})
 
-	return eventsToTrigger
+	return evictedSlots
+}
+
+// isEvicted returns true if the given slot is evicted according to the given index of the last evicted slot.
+func (e *evictionState[Type]) isEvicted(slot Type, lastEvictedSlotIndex Type) bool {
+	var zeroValue Type
+
+	return slot <= lastEvictedSlotIndex && (slot != zeroValue || lastEvictedSlotIndex != zeroValue)
+}
+
+// evictedSlot contains the details of a slot that was evicted.
+type evictedSlot[Type EvictionStateSlotType] struct {
+	// slot is the index of the evicted slot.
+	slot Type
+
+	// event is the eviction event of the slot (if it was requested).
+	event Event
+
+	// cleanups are the cleanup functions that were registered for the slot.
+	cleanups []*callback[func()]
 }
 
 var evictedSlotEvent = func() Event {
@@ -70,3 +70,76 @@ func TestEvictTriggered(t *testing.T) {
 	state.Evict(slotToEvict)
 	require.True(t, eventToTrigger.WasTriggered(), "evicted event should have been triggered")
 }
+
+func TestEvictionStateOnEvict(t *testing.T) {
+	state := NewEvictionState[int]()
+
+	executedCleanups := make([]string, 0)
+	cleanup := func(name string) func() {
+		return func() { executedCleanups = append(executedCleanups, name) }
+	}
+
+	state.OnEvict(3, cleanup("3a"))
+	state.OnEvict(1, cleanup("1"))
+	state.OnEvict(3, cleanup("3b"))
+	unsubscribe := state.OnEvict(2, cleanup("2"))
+	unsubscribe()
+
+	evictedSlots := make([]int, 0)
+	state.OnSlotEvicted(func(slot int) { evictedSlots = append(evictedSlots, slot) })
+
+	require.Equal(t, []int{0, 1, 2, 3, 4}, state.Evict(4))
+	require.Equal(t, []string{"1", "3a", "3b"}, executedCleanups)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, evictedSlots)
+	require.True(t, state.IsEvicted(4))
+	require.False(t, state.IsEvicted(5))
+
+	// cleanups of already evicted slots are executed immediately
+	state.OnEvict(2, cleanup("late"))
+	require.Equal(t, []string{"1", "3a", "3b", "late"}, executedCleanups)
+
+	// evicting an already evicted slot does nothing
+	require.Empty(t, state.Evict(3))
+	require.Equal(t, []int{0, 1, 2, 3, 4}, evictedSlots)
+}
+
+func TestEvictionStateSlotZero(t *testing.T) {
+	state := NewEvictionState[int]()
+
+	executedCleanups := make([]string, 0)
+	state.OnEvict(0, func() { executedCleanups = append(executedCleanups, "0") })
+	slotZeroEvicted := state.EvictionEvent(0)
+
+	// slot 0 is not evicted before a later slot is evicted
+	require.Empty(t, state.Evict(0))
+	require.False(t, state.IsEvicted(0))
+	require.False(t, slotZeroEvicted.WasTriggered())
+
+	require.Equal(t, []int{0, 1, 2, 3}, state.Evict(3))
+	require.Equal(t, []string{"0"}, executedCleanups)
+	require.True(t, slotZeroEvicted.WasTriggered())
+	require.True(t, state.IsEvicted(0))
+
+	// cleanups of slot 0 are executed immediately once it was evicted
+	state.OnEvict(0, func() { executedCleanups = append(executedCleanups, "late") })
+	require.Equal(t, []string{"0", "late"}, executedCleanups)
+	require.Equal(t, 0, state.(*evictionState[int]).cleanups.Size())
+}
+
+func TestEvictionStateRetentionWindow(t *testing.T) {
+	state := NewEvictionState[uint32](3)
+	require.Equal(t, uint32(3), state.RetentionWindow())
+
+	slotEvicted := state.EvictionEvent(2)
+
+	require.Empty(t, state.Advance(2))
+	require.Empty(t, state.Advance(3))
+	require.False(t, slotEvicted.WasTriggered())
+
+	require.Equal(t, []uint32{0, 1, 2}, state.Advance(5))
+	require.True(t, slotEvicted.WasTriggered())
+	require.Equal(t, uint32(2), state.LastEvictedSlot().Get())
+
+	require.Equal(t, []uint32{3, 4, 5, 6, 7}, state.Advance(10))
+	require.Empty(t, state.Advance(9))
+}
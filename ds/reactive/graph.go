@@ -0,0 +1,443 @@
+package reactive
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/lo"
+)
+
+// region public API ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// EnableDependencyGraph enables the instrumentation that records the names of reactive elements and the edges that are
+// created by NewDerivedVariable*, InheritFrom, DeriveValueFrom, Counter.Monitor and DerivedSet.InheritFrom.
+//
+// The instrumentation is meant for debugging: it keeps the recorded elements alive until they are no longer part of an
+// edge (or until the instrumentation is disabled again).
+func EnableDependencyGraph() {
+	dependencyGraph.enable()
+}
+
+// DisableDependencyGraph disables the instrumentation and discards all recorded names and edges.
+func DisableDependencyGraph() {
+	dependencyGraph.disable()
+}
+
+// SetGraphName assigns the given name to the given reactive element (it is ignored if the instrumentation is disabled).
+func SetGraphName(element any, name string) {
+	dependencyGraph.setName(element, name)
+}
+
+// GraphName returns the name of the given reactive element (or an empty string if it was not named).
+func GraphName(element any) string {
+	return dependencyGraph.name(element)
+}
+
+// DependencyGraph returns a snapshot of the live reactive elements and the edges between them that were recorded since
+// the instrumentation was enabled.
+func DependencyGraph() *Graph {
+	return dependencyGraph.snapshot()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region Graph ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Graph is a snapshot of the dependency graph of reactive elements.
+type Graph struct {
+	// Nodes contains the reactive elements of the graph (ordered by their ID).
+	Nodes []*GraphNode `json:"nodes"`
+
+	// Edges contains the subscriptions between the reactive elements (ordered by their creation).
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// DOT returns the Graphviz DOT representation of the Graph.
+func (g *Graph) DOT() string {
+	var builder strings.Builder
+
+	builder.WriteString("digraph reactive {\n")
+	builder.WriteString("\tnode [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		labelLines := make([]string, 0, 4)
+		if node.Name != "" {
+			labelLines = append(labelLines, node.Name)
+		}
+		labelLines = append(labelLines,
+			node.Type,
+			"value: "+node.Value,
+			fmt.Sprintf("updates: %d, subscribers: %d", node.UpdateCount, node.SubscriberCount),
+		)
+
+		fmt.Fprintf(&builder, "\t%d [label=%s];\n", node.ID, dotString(labelLines...))
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&builder, "\t%d -> %d [label=%s];\n", edge.From, edge.To, dotString(edge.Kind))
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// JSON returns the JSON representation of the Graph.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// dotString returns a quoted DOT string that contains the given lines.
+func dotString(lines ...string) string {
+	escapedLines := make([]string, len(lines))
+	for i, line := range lines {
+		escapedLines[i] = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(line)
+	}
+
+	return `"` + strings.Join(escapedLines, `\n`) + `"`
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region GraphNode ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// GraphNode is a reactive element in the dependency graph.
+type GraphNode struct {
+	// ID is the identifier of the node.
+	ID uint64 `json:"id"`
+
+	// Name is the name that was assigned to the element.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the element.
+	Type string `json:"type"`
+
+	// Value is the string representation of the current value of the element.
+	Value string `json:"value"`
+
+	// UpdateCount is the number of updates of the element.
+	UpdateCount uint64 `json:"updateCount"`
+
+	// SubscriberCount is the number of callbacks that are subscribed to the element.
+	SubscriberCount int `json:"subscriberCount"`
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region GraphEdge ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// GraphEdge is a subscription of a reactive element to one of its inputs.
+type GraphEdge struct {
+	// From is the ID of the input.
+	From uint64 `json:"from"`
+
+	// To is the ID of the element that is subscribed to the input.
+	To uint64 `json:"to"`
+
+	// Kind is the name of the operation that created the edge.
+	Kind string `json:"kind"`
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region graphTracker /////////////////////////////////////////////////////////////////////////////////////////////////
+
+// dependencyGraph is the graphTracker that records the dependency graph of all reactive elements.
+var dependencyGraph = newGraphTracker()
+
+// graphTracker records the names of reactive elements and the edges between them while it is enabled.
+type graphTracker struct {
+	// isEnabled is true if the tracker records names and edges (it is checked before acquiring the mutex, so that
+	// disabled instrumentation does not synchronize the creation of reactive elements).
+	isEnabled atomic.Bool
+
+	// epoch is increased whenever the tracker is reset (to ignore the removal of edges of previous epochs).
+	epoch uint64
+
+	// nodes contains the tracked nodes (indexed by the identity of their element).
+	nodes map[any]*trackedNode
+
+	// edges contains the tracked edges (indexed by their ID).
+	edges map[uint64]*trackedEdge
+
+	// nextID is the identifier of the next tracked node or edge.
+	nextID uint64
+
+	// mutex is used to synchronize access to the tracker.
+	mutex sync.Mutex
+}
+
+// newGraphTracker creates a new (disabled) graphTracker.
+func newGraphTracker() *graphTracker {
+	return &graphTracker{
+		nodes: make(map[any]*trackedNode),
+		edges: make(map[uint64]*trackedEdge),
+	}
+}
+
+// enable enables the tracker.
+func (g *graphTracker) enable() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.isEnabled.Store(true)
+}
+
+// disable disables the tracker and discards the recorded nodes and edges.
+func (g *graphTracker) disable() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.isEnabled.Store(false)
+	g.epoch++
+	g.nodes = make(map[any]*trackedNode)
+	g.edges = make(map[uint64]*trackedEdge)
+}
+
+// setName assigns the given name to the given element (named elements are kept until the tracker is disabled).
+func (g *graphTracker) setName(element any, name string) {
+	if !g.isEnabled.Load() || name == "" {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if !g.isEnabled.Load() {
+		return
+	}
+
+	// the first name of an element holds a reference to its node
+	node, exists := g.nodes[graphIdentityOf(element)]
+	if !exists || node.name == "" {
+		node = g.acquireNode(element)
+	}
+
+	if node != nil {
+		node.name = name
+	}
+}
+
+// name returns the name of the given element.
+func (g *graphTracker) name(element any) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if node, exists := g.nodes[graphIdentityOf(element)]; exists {
+		return node.name
+	}
+
+	return ""
+}
+
+// link records the edges from the given inputs to the given element and returns a function that removes them again.
+func (g *graphTracker) link(element any, kind string, inputs ...any) (unlink func()) {
+	if !g.isEnabled.Load() {
+		return func() {}
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	// the tracker might have been disabled while waiting for the lock
+	if !g.isEnabled.Load() {
+		return func() {}
+	}
+
+	edgeIDs := make([]uint64, 0, len(inputs))
+	for _, input := range inputs {
+		if to := g.acquireNode(element); to != nil {
+			if from := g.acquireNode(input); from != nil {
+				g.nextID++
+				g.edges[g.nextID] = &trackedEdge{id: g.nextID, from: from, to: to, kind: kind}
+
+				edgeIDs = append(edgeIDs, g.nextID)
+			} else {
+				g.releaseNode(to)
+			}
+		}
+	}
+
+	epoch := g.epoch
+
+	return func() {
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+
+		if epoch != g.epoch {
+			return
+		}
+
+		for _, edgeID := range edgeIDs {
+			if edge, exists := g.edges[edgeID]; exists {
+				delete(g.edges, edgeID)
+
+				g.releaseNode(edge.from)
+				g.releaseNode(edge.to)
+			}
+		}
+	}
+}
+
+// snapshot returns a snapshot of the recorded nodes and edges.
+func (g *graphTracker) snapshot() *Graph {
+	g.mutex.Lock()
+	nodes := make([]*trackedNode, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+
+	graph := &Graph{
+		Nodes: make([]*GraphNode, 0, len(nodes)),
+		Edges: make([]*GraphEdge, 0, len(g.edges)),
+	}
+
+	edges := make([]*trackedEdge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		edges = append(edges, edge)
+	}
+	g.mutex.Unlock()
+
+	sort.Slice(edges, func(i, j int) bool { return edges[i].id < edges[j].id })
+	for _, edge := range edges {
+		graph.Edges = append(graph.Edges, &GraphEdge{From: edge.from.id, To: edge.to.id, Kind: edge.kind})
+	}
+
+	// the elements are described without holding the lock (to not block the tracker while reading their values)
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node.describe())
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+
+	return graph
+}
+
+// acquireNode returns the node of the given element and increases its reference count (it returns nil if the element
+// can not be tracked).
+func (g *graphTracker) acquireNode(element any) *trackedNode {
+	identity := graphIdentityOf(element)
+	if identity == nil {
+		return nil
+	}
+
+	node, exists := g.nodes[identity]
+	if !exists {
+		g.nextID++
+		node = &trackedNode{id: g.nextID, identity: identity, element: element}
+		g.nodes[identity] = node
+	}
+
+	node.references++
+
+	return node
+}
+
+// releaseNode decreases the reference count of the given node and removes it once it is no longer referenced.
+func (g *graphTracker) releaseNode(node *trackedNode) {
+	if node.references--; node.references == 0 {
+		delete(g.nodes, node.identity)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region trackedNode //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// trackedNode is a reactive element that is tracked by the graphTracker.
+type trackedNode struct {
+	// id is the identifier of the node.
+	id uint64
+
+	// identity is the key of the node in the graphTracker.
+	identity any
+
+	// element is the reactive element of the node.
+	element any
+
+	// name is the name that was assigned to the element.
+	name string
+
+	// references is the number of edges (and names) that reference the node.
+	references int
+}
+
+// describe returns the GraphNode that describes the current state of the element.
+func (t *trackedNode) describe() *GraphNode {
+	node := &GraphNode{
+		ID:   t.id,
+		Name: t.name,
+		Type: fmt.Sprintf("%T", t.element),
+	}
+
+	if describedElement, isDescribed := t.element.(interface {
+		describeGraphNode() (value string, updateCount uint64, subscriberCount int)
+	}); isDescribed {
+		node.Value, node.UpdateCount, node.SubscriberCount = describedElement.describeGraphNode()
+	}
+
+	return node
+}
+
+// trackedEdge is an edge between two tracked nodes.
+type trackedEdge struct {
+	// id is the identifier of the edge.
+	id uint64
+
+	// from is the node of the input.
+	from *trackedNode
+
+	// to is the node of the element that is subscribed to the input.
+	to *trackedNode
+
+	// kind is the name of the operation that created the edge.
+	kind string
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region utils ////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// dependsOn registers the given element as depending on the given inputs (it ranks the element above its inputs,
+// registers it as a dependent of the inputs and records the edges in the dependency graph) and returns a function that
+// removes the registrations and the recorded edges again.
+func dependsOn(element any, kind string, inputs ...any) (unlink func()) {
+	rankAbove(element, inputs...)
+
+	unlinkCallbacks := []func(){dependencyGraph.link(element, kind, inputs...)}
+	if dependentElement, isDependent := element.(interface{ graphIdentity() *elementRank }); isDependent {
+		for _, input := range inputs {
+			if trackedInput, isTracked := input.(interface{ graphIdentity() *elementRank }); isTracked {
+				unlinkCallbacks = append(unlinkCallbacks, trackedInput.graphIdentity().addDependent(dependentElement.graphIdentity()))
+			}
+		}
+	}
+
+	return lo.Batch(unlinkCallbacks...)
+}
+
+// graphIdentity returns the identity of the element in the dependency graph (wrappers of the same element share the
+// identity of the embedded elementRank).
+func (e *elementRank) graphIdentity() *elementRank {
+	return e
+}
+
+// graphIdentityOf returns the identity of the given element in the dependency graph (or nil if it can not be tracked).
+func graphIdentityOf(element any) any {
+	if identifiableElement, isIdentifiable := element.(interface{ graphIdentity() *elementRank }); isIdentifiable {
+		return identifiableElement.graphIdentity()
+	}
+
+	if element == nil || !reflect.TypeOf(element).Comparable() {
+		return nil
+	}
+
+	return element
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,81 @@
+package reactive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	// edges are only recorded while the instrumentation is enabled
+	untracked := NewVariable[int]()
+	NewDerivedVariable[int](func(_ int, value int) int { return value }, untracked)
+	require.Empty(t, DependencyGraph().Nodes)
+
+	EnableDependencyGraph()
+	defer DisableDependencyGraph()
+
+	input1 := NewVariable[int]().Init(1)
+	input2 := NewVariable[int]().Init(2)
+	SetGraphName(input1, "input1")
+	SetGraphName(input2, "input2")
+
+	sum := NewDerivedVariable2[int](func(_ int, value1 int, value2 int) int { return value1 + value2 }, input1, input2)
+	SetGraphName(sum, "sum")
+
+	inherited := NewVariable[int]()
+	unsubscribeInherited := inherited.InheritFrom(sum)
+
+	counter := NewCounter[int]()
+	unsubscribeCounter := counter.Monitor(input1)
+
+	inputSet := NewSet[int](1, 2)
+	derivedSet := NewDerivedSet[int]()
+	unsubscribeDerivedSet := derivedSet.InheritFrom(inputSet)
+
+	input1.Set(3)
+
+	graph := DependencyGraph()
+	nodes := make(map[string]*GraphNode)
+	for _, node := range graph.Nodes {
+		nodes[node.Name] = node
+	}
+
+	require.Len(t, graph.Nodes, 7)
+	require.Equal(t, "5", nodes["sum"].Value)
+	require.EqualValues(t, 2, nodes["sum"].UpdateCount)
+	require.Equal(t, 1, nodes["sum"].SubscriberCount)
+	require.EqualValues(t, 2, nodes["input1"].UpdateCount)
+
+	require.Equal(t, []*GraphEdge{
+		{From: nodes["input1"].ID, To: nodes["sum"].ID, Kind: "NewDerivedVariable2"},
+		{From: nodes["input2"].ID, To: nodes["sum"].ID, Kind: "NewDerivedVariable2"},
+		{From: nodes["sum"].ID, To: graph.Edges[2].To, Kind: "InheritFrom"},
+		{From: nodes["input1"].ID, To: graph.Edges[3].To, Kind: "Monitor"},
+		{From: graph.Edges[4].From, To: graph.Edges[4].To, Kind: "InheritFrom"},
+	}, graph.Edges)
+
+	// the exports contain the recorded nodes and edges
+	require.Contains(t, graph.DOT(), `[label="sum\n*reactive.derivedVariable[int]\nvalue: 5\nupdates: 2, subscribers: 1"];`)
+
+	jsonGraph, err := graph.JSON()
+	require.NoError(t, err)
+
+	var decodedGraph Graph
+	require.NoError(t, json.Unmarshal(jsonGraph, &decodedGraph))
+	require.Equal(t, graph, &decodedGraph)
+
+	// unsubscribing removes the edges (and the unnamed nodes that are no longer referenced)
+	unsubscribeInherited()
+	unsubscribeCounter()
+	unsubscribeDerivedSet()
+
+	graph = DependencyGraph()
+	require.Len(t, graph.Nodes, 3)
+	require.Len(t, graph.Edges, 2)
+
+	sum.Unsubscribe()
+	require.Len(t, DependencyGraph().Nodes, 3)
+	require.Empty(t, DependencyGraph().Edges)
+}
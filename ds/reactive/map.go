@@ -0,0 +1,90 @@
+package reactive
+
+import (
+	"iter"
+
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+)
+
+// region Map //////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Map is a reactive map implementation that allows consumers to subscribe to its changes as well as to the values of
+// its individual keys.
+type Map[KeyType, ValueType comparable] interface {
+	// Set sets the value of the given key and returns the previous value (and whether the key existed before).
+	Set(key KeyType, value ValueType) (previousValue ValueType, previousExists bool)
+
+	// Get returns the value of the given key.
+	Get(key KeyType) (value ValueType, exists bool)
+
+	// Has returns true if the given key exists.
+	Has(key KeyType) bool
+
+	// Delete deletes the given key and returns true if it existed.
+	Delete(key KeyType) (deleted bool)
+
+	// Compute atomically computes the new value of the given key from its current value. The entry is deleted if the
+	// compute function returns false for keepEntry.
+	Compute(key KeyType, computeFunc func(currentValue ValueType, exists bool) (newValue ValueType, keepEntry bool)) (newValue ValueType, exists bool)
+
+	// Size returns the number of entries in the map.
+	Size() int
+
+	// ForEach iterates over all entries of the map (until the consumer returns false).
+	ForEach(consumer func(key KeyType, value ValueType) bool)
+
+	// All returns a sequence of all entries of the map (in no particular order). Every iteration works on a snapshot of
+	// the entries that is taken when the iteration starts, so the map can be modified while iterating.
+	All() iter.Seq2[KeyType, ValueType]
+
+	// Keys returns a sequence of all keys of the map (with the same snapshot semantics as All).
+	Keys() iter.Seq[KeyType]
+
+	// Values returns a sequence of all values of the map (with the same snapshot semantics as All).
+	Values() iter.Seq[ValueType]
+
+	// Entry returns a ReadableVariable that tracks the value of the given key for as long as the key exists (it is
+	// reset to its zero value once the key is deleted - a key that is added again gets a new ReadableVariable).
+	Entry(key KeyType) (entry ReadableVariable[ValueType], exists bool)
+
+	// OnUpdate registers the given callback that is triggered when the map changes.
+	OnUpdate(callback func(appliedMutations MapMutations[KeyType, ValueType]), triggerWithInitialZeroValue ...bool) (unsubscribe func())
+
+	// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the
+	// other in the order they were triggered). Updates that did not start before the subscription is unsubscribed are
+	// skipped.
+	OnUpdateDispatched(workerPool *workerpool.WorkerPool, callback func(appliedMutations MapMutations[KeyType, ValueType]), triggerWithInitialZeroValue ...bool) (unsubscribe func())
+
+	// WithEntries is a utility function that allows to set up dynamic behavior based on the entries of the Map which
+	// is torn down once the entry is deleted (or the returned teardown function is called). It accepts an optional
+	// condition that has to be satisfied for the setup function to be called.
+	WithEntries(setup func(key KeyType, entry ReadableVariable[ValueType]) (teardown func()), condition ...func(key KeyType) bool) (teardown func())
+}
+
+// NewMap creates a new Map.
+func NewMap[KeyType, ValueType comparable]() Map[KeyType, ValueType] {
+	return newMap[KeyType, ValueType]()
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region MapMutations /////////////////////////////////////////////////////////////////////////////////////////////////
+
+// MapMutations represents the changes of a Map that were applied atomically. A key that was deleted and added again
+// (within a Batch) is contained in both the deleted and the added entries, so consumers should process the deleted
+// entries first.
+type MapMutations[KeyType, ValueType comparable] interface {
+	// AddedEntries returns the entries that were added (key -> new value).
+	AddedEntries() map[KeyType]ValueType
+
+	// UpdatedEntries returns the existing entries whose value changed (key -> new value).
+	UpdatedEntries() map[KeyType]ValueType
+
+	// DeletedEntries returns the entries that were deleted (key -> last value).
+	DeletedEntries() map[KeyType]ValueType
+
+	// IsEmpty returns true if the MapMutations instance is empty.
+	IsEmpty() bool
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
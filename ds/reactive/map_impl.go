@@ -0,0 +1,486 @@
+package reactive
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/iotaledger/hive.go/ds"
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
+)
+
+// region reactiveMap //////////////////////////////////////////////////////////////////////////////////////////////////
+
+// reactiveMap is the default implementation of the Map interface.
+type reactiveMap[KeyType, ValueType comparable] struct {
+	// entries holds the variables that track the values of the keys.
+	entries map[KeyType]*variable[ValueType]
+
+	// updateCallbacks are the registered callbacks that are triggered when the map changes.
+	updateCallbacks ds.List[*callback[func(MapMutations[KeyType, ValueType])]]
+
+	// uniqueUpdateID is the unique ID that is used to identify an update.
+	uniqueUpdateID uniqueID
+
+	// batchedUpdate is the update that was collected by the open Transaction (nil if there is none).
+	batchedUpdate *batchedMapUpdate[KeyType, ValueType]
+
+	// entriesMutex is used to synchronize the access to the entries.
+	entriesMutex sync.RWMutex
+
+	// mutex is used to make write operations atomic (all subscribers are notified before the next write operation is
+	// executed).
+	mutex sync.Mutex
+
+	// elementRank tracks the position of the map in the dependency graph.
+	elementRank
+}
+
+// newMap creates a new reactiveMap.
+func newMap[KeyType, ValueType comparable]() *reactiveMap[KeyType, ValueType] {
+	return &reactiveMap[KeyType, ValueType]{
+		entries:         make(map[KeyType]*variable[ValueType]),
+		updateCallbacks: ds.NewList[*callback[func(MapMutations[KeyType, ValueType])]](),
+	}
+}
+
+// Set sets the value of the given key and returns the previous value (and whether the key existed before).
+func (m *reactiveMap[KeyType, ValueType]) Set(key KeyType, value ValueType) (previousValue ValueType, previousExists bool) {
+	m.Compute(key, func(currentValue ValueType, exists bool) (ValueType, bool) {
+		previousValue, previousExists = currentValue, exists
+
+		return value, true
+	})
+
+	return previousValue, previousExists
+}
+
+// Get returns the value of the given key.
+func (m *reactiveMap[KeyType, ValueType]) Get(key KeyType) (value ValueType, exists bool) {
+	m.entriesMutex.RLock()
+	entry, exists := m.entries[key]
+	m.entriesMutex.RUnlock()
+
+	if !exists {
+		return value, false
+	}
+
+	return entry.Get(), true
+}
+
+// Has returns true if the given key exists.
+func (m *reactiveMap[KeyType, ValueType]) Has(key KeyType) bool {
+	m.entriesMutex.RLock()
+	defer m.entriesMutex.RUnlock()
+
+	_, exists := m.entries[key]
+
+	return exists
+}
+
+// Delete deletes the given key and returns true if it existed.
+func (m *reactiveMap[KeyType, ValueType]) Delete(key KeyType) (deleted bool) {
+	m.Compute(key, func(currentValue ValueType, exists bool) (ValueType, bool) {
+		deleted = exists
+
+		return currentValue, false
+	})
+
+	return deleted
+}
+
+// Compute atomically computes the new value of the given key from its current value. The entry is deleted if the
+// compute function returns false for keepEntry.
+func (m *reactiveMap[KeyType, ValueType]) Compute(key KeyType, computeFunc func(currentValue ValueType, exists bool) (newValue ValueType, keepEntry bool)) (newValue ValueType, exists bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	update, updateID, registeredCallbacks := m.compute(key, computeFunc)
+	if update == nil {
+		return m.Get(key)
+	}
+
+	// the entries are updated in the Transaction of the map (if it joined one)
+	joinTransactionOf(update.entry.graphIdentity(), m.graphIdentity())
+
+	// the entries of existing keys are updated before and the entries of deleted keys are reset after the subscribers
+	// of the map are notified (so that everything that was set up for a deleted key can be torn down first)
+	if update.exists {
+		update.entry.Set(update.entryValue)
+	}
+
+	if !m.collectBatchedUpdate(key, update, updateID) {
+		for _, registeredCallback := range registeredCallbacks {
+			if registeredCallback.LockExecution(updateID) {
+				registeredCallback.Invoke(update.mutations)
+				registeredCallback.UnlockExecution()
+			}
+		}
+	}
+
+	if !update.exists {
+		update.entry.Set(update.entryValue)
+	}
+
+	return update.entryValue, update.exists
+}
+
+// Size returns the number of entries in the map.
+func (m *reactiveMap[KeyType, ValueType]) Size() int {
+	m.entriesMutex.RLock()
+	defer m.entriesMutex.RUnlock()
+
+	return len(m.entries)
+}
+
+// ForEach iterates over all entries of the map (until the consumer returns false).
+func (m *reactiveMap[KeyType, ValueType]) ForEach(consumer func(key KeyType, value ValueType) bool) {
+	for key, value := range m.snapshot() {
+		if !consumer(key, value) {
+			return
+		}
+	}
+}
+
+// All returns a sequence of all entries of the map (iterating over a snapshot of the entries).
+func (m *reactiveMap[KeyType, ValueType]) All() iter.Seq2[KeyType, ValueType] {
+	return func(yield func(KeyType, ValueType) bool) {
+		m.ForEach(yield)
+	}
+}
+
+// Keys returns a sequence of all keys of the map (iterating over a snapshot of the entries).
+func (m *reactiveMap[KeyType, ValueType]) Keys() iter.Seq[KeyType] {
+	return func(yield func(KeyType) bool) {
+		m.ForEach(func(key KeyType, _ ValueType) bool {
+			return yield(key)
+		})
+	}
+}
+
+// Values returns a sequence of all values of the map (iterating over a snapshot of the entries).
+func (m *reactiveMap[KeyType, ValueType]) Values() iter.Seq[ValueType] {
+	return func(yield func(ValueType) bool) {
+		m.ForEach(func(_ KeyType, value ValueType) bool {
+			return yield(value)
+		})
+	}
+}
+
+// Entry returns a ReadableVariable that tracks the value of the given key for as long as the key exists.
+func (m *reactiveMap[KeyType, ValueType]) Entry(key KeyType) (entry ReadableVariable[ValueType], exists bool) {
+	m.entriesMutex.RLock()
+	defer m.entriesMutex.RUnlock()
+
+	if entryVariable, exists := m.entries[key]; exists {
+		return entryVariable, true
+	}
+
+	return nil, false
+}
+
+// OnUpdate registers the given callback that is triggered when the map changes.
+func (m *reactiveMap[KeyType, ValueType]) OnUpdate(callback func(appliedMutations MapMutations[KeyType, ValueType]), triggerWithInitialZeroValue ...bool) (unsubscribe func()) {
+	m.entriesMutex.Lock()
+
+	mutations := newMapMutations[KeyType, ValueType]()
+	for key, entry := range m.entries {
+		mutations.addedEntries[key] = entry.Get()
+	}
+
+	createdCallback := newCallback[func(MapMutations[KeyType, ValueType])](callback)
+	callbackElement := m.updateCallbacks.PushBack(createdCallback)
+
+	// grab the lock to make sure that the callback is not executed before we have called it with the initial value.
+	createdCallback.LockExecution(m.uniqueUpdateID)
+	defer createdCallback.UnlockExecution()
+
+	m.entriesMutex.Unlock()
+
+	if !mutations.IsEmpty() || lo.First(triggerWithInitialZeroValue) {
+		createdCallback.Invoke(mutations)
+	}
+
+	return func() {
+		m.updateCallbacks.Remove(callbackElement)
+
+		createdCallback.MarkUnsubscribed()
+	}
+}
+
+// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the other
+// in the order they were triggered). Updates that did not start before the subscription is unsubscribed are skipped.
+func (m *reactiveMap[KeyType, ValueType]) OnUpdateDispatched(workerPool *workerpool.WorkerPool, callback func(appliedMutations MapMutations[KeyType, ValueType]), triggerWithInitialZeroValue ...bool) (unsubscribe func()) {
+	d := newDispatcher(workerPool)
+
+	return d.subscription(m.OnUpdate(func(appliedMutations MapMutations[KeyType, ValueType]) {
+		d.dispatchCallback(func() { callback(appliedMutations) })
+	}, triggerWithInitialZeroValue...))
+}
+
+// WithEntries is a utility function that allows to set up dynamic behavior based on the entries of the Map which is
+// torn down once the entry is deleted (or the returned teardown function is called). It accepts an optional condition
+// that has to be satisfied for the setup function to be called.
+func (m *reactiveMap[KeyType, ValueType]) WithEntries(setup func(key KeyType, entry ReadableVariable[ValueType]) (teardown func()), condition ...func(key KeyType) bool) (teardown func()) {
+	teardownFunctions := make(map[KeyType]func())
+
+	return lo.Batch(
+		m.OnUpdate(func(appliedMutations MapMutations[KeyType, ValueType]) {
+			for key := range appliedMutations.DeletedEntries() {
+				if teardownFunc, exists := teardownFunctions[key]; exists {
+					delete(teardownFunctions, key)
+
+					teardownFunc()
+				}
+			}
+
+			for key := range appliedMutations.AddedEntries() {
+				if len(condition) != 0 && !condition[0](key) {
+					continue
+				}
+
+				// the entry might have been deleted again in the meantime (its deletion is reported next)
+				if entry, exists := m.Entry(key); exists {
+					if teardownFunc := setup(key, entry); teardownFunc != nil {
+						teardownFunctions[key] = teardownFunc
+					}
+				}
+			}
+		}),
+
+		func() {
+			for key, teardownFunc := range teardownFunctions {
+				delete(teardownFunctions, key)
+
+				teardownFunc()
+			}
+		},
+	)
+}
+
+// compute applies the given compute function to the entry of the given key and returns the resulting update (nil if
+// nothing changed), the update ID and the callbacks to trigger.
+func (m *reactiveMap[KeyType, ValueType]) compute(key KeyType, computeFunc func(currentValue ValueType, exists bool) (newValue ValueType, keepEntry bool)) (update *mapUpdate[KeyType, ValueType], updateID uniqueID, callbacksToTrigger []*callback[func(MapMutations[KeyType, ValueType])]) {
+	m.entriesMutex.Lock()
+	defer m.entriesMutex.Unlock()
+
+	var currentValue ValueType
+	entry, exists := m.entries[key]
+	if exists {
+		currentValue = entry.Get()
+	}
+
+	newValue, keepEntry := computeFunc(currentValue, exists)
+
+	update = &mapUpdate[KeyType, ValueType]{
+		mutations:     newMapMutations[KeyType, ValueType](),
+		previousEntry: entry,
+		previousValue: currentValue,
+		entry:         entry,
+		entryValue:    newValue,
+		exists:        keepEntry,
+	}
+
+	switch {
+	case keepEntry && !exists:
+		// the entry is created with its value, so that it is never observed with the zero value by concurrent readers
+		update.entry = newVariable[ValueType]()
+		update.entry.value = newValue
+		m.entries[key] = update.entry
+		update.mutations.addedEntries[key] = newValue
+	case keepEntry && newValue != currentValue:
+		update.mutations.updatedEntries[key] = newValue
+	case !keepEntry && exists:
+		delete(m.entries, key)
+		update.mutations.deletedEntries[key] = currentValue
+		update.entryValue = *new(ValueType)
+	default:
+		return nil, 0, nil
+	}
+
+	return update, m.uniqueUpdateID.Next(), m.updateCallbacks.Values()
+}
+
+// snapshot returns a copy of the current entries.
+func (m *reactiveMap[KeyType, ValueType]) snapshot() map[KeyType]ValueType {
+	m.entriesMutex.RLock()
+	defer m.entriesMutex.RUnlock()
+
+	entries := make(map[KeyType]ValueType, len(m.entries))
+	for key, entry := range m.entries {
+		entries[key] = entry.Get()
+	}
+
+	return entries
+}
+
+// describeGraphNode returns the current entries, the number of updates and the number of subscribers of the map.
+func (m *reactiveMap[KeyType, ValueType]) describeGraphNode() (value string, updateCount uint64, subscriberCount int) {
+	entries := m.snapshot()
+
+	m.entriesMutex.RLock()
+	defer m.entriesMutex.RUnlock()
+
+	return fmt.Sprint(entries), uint64(m.uniqueUpdateID), m.updateCallbacks.Len()
+}
+
+// collectBatchedUpdate collects the given update in the open Transaction of the element and returns false if it did not join one.
+func (m *reactiveMap[KeyType, ValueType]) collectBatchedUpdate(key KeyType, update *mapUpdate[KeyType, ValueType], updateID uniqueID) bool {
+	if m.batchedUpdate == nil {
+		if !collectInTransaction(m) {
+			return false
+		}
+
+		m.batchedUpdate = &batchedMapUpdate[KeyType, ValueType]{
+			previousEntries: make(map[KeyType]*variable[ValueType]),
+			previousValues:  make(map[KeyType]ValueType),
+		}
+	}
+
+	m.batchedUpdate.updateID = updateID
+
+	// only the state before the first update of the key is relevant for the net mutations
+	if _, exists := m.batchedUpdate.previousEntries[key]; !exists {
+		m.batchedUpdate.previousEntries[key] = update.previousEntry
+		m.batchedUpdate.previousValues[key] = update.previousValue
+	}
+
+	return true
+}
+
+// triggerBatchedUpdate notifies the subscribers about the net mutations that were collected by a Batch.
+func (m *reactiveMap[KeyType, ValueType]) triggerBatchedUpdate() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	batchedUpdate := m.batchedUpdate
+	if batchedUpdate == nil {
+		return
+	}
+	m.batchedUpdate = nil
+
+	m.entriesMutex.RLock()
+	mutations := newMapMutations[KeyType, ValueType]()
+	for key, previousEntry := range batchedUpdate.previousEntries {
+		currentEntry, exists := m.entries[key]
+
+		switch {
+		case previousEntry == nil && !exists:
+			// the key was added and deleted again
+		case previousEntry == currentEntry:
+			if currentValue := currentEntry.Get(); currentValue != batchedUpdate.previousValues[key] {
+				mutations.updatedEntries[key] = currentValue
+			}
+		default:
+			if previousEntry != nil {
+				mutations.deletedEntries[key] = batchedUpdate.previousValues[key]
+			}
+
+			if exists {
+				mutations.addedEntries[key] = currentEntry.Get()
+			}
+		}
+	}
+	registeredCallbacks := m.updateCallbacks.Values()
+	m.entriesMutex.RUnlock()
+
+	if mutations.IsEmpty() {
+		return
+	}
+
+	for _, registeredCallback := range registeredCallbacks {
+		if registeredCallback.LockExecution(batchedUpdate.updateID) {
+			registeredCallback.Invoke(mutations)
+			registeredCallback.UnlockExecution()
+		}
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region mapUpdate ////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// mapUpdate is an update of a single key of a reactiveMap.
+type mapUpdate[KeyType, ValueType comparable] struct {
+	// mutations are the resulting mutations of the map.
+	mutations *mapMutations[KeyType, ValueType]
+
+	// previousEntry is the entry of the key before the update (nil if the key did not exist).
+	previousEntry *variable[ValueType]
+
+	// previousValue is the value of the key before the update.
+	previousValue ValueType
+
+	// entry is the entry that is affected by the update.
+	entry *variable[ValueType]
+
+	// entryValue is the new value of the affected entry (the zero value if the key was deleted).
+	entryValue ValueType
+
+	// exists is true if the key exists after the update.
+	exists bool
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region batchedMapUpdate /////////////////////////////////////////////////////////////////////////////////////////////
+
+// batchedMapUpdate is an update of a map that was collected by a Batch.
+type batchedMapUpdate[KeyType, ValueType comparable] struct {
+	// previousEntries contains the entries of the updated keys before the Batch (nil if the key did not exist).
+	previousEntries map[KeyType]*variable[ValueType]
+
+	// previousValues contains the values of the updated keys before the Batch.
+	previousValues map[KeyType]ValueType
+
+	// updateID is the identifier of the latest update of the map.
+	updateID uniqueID
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region mapMutations /////////////////////////////////////////////////////////////////////////////////////////////////
+
+// mapMutations is the default implementation of the MapMutations interface.
+type mapMutations[KeyType, ValueType comparable] struct {
+	// addedEntries contains the entries that were added.
+	addedEntries map[KeyType]ValueType
+
+	// updatedEntries contains the existing entries whose value changed.
+	updatedEntries map[KeyType]ValueType
+
+	// deletedEntries contains the entries that were deleted.
+	deletedEntries map[KeyType]ValueType
+}
+
+// newMapMutations creates a new mapMutations instance.
+func newMapMutations[KeyType, ValueType comparable]() *mapMutations[KeyType, ValueType] {
+	return &mapMutations[KeyType, ValueType]{
+		addedEntries:   make(map[KeyType]ValueType),
+		updatedEntries: make(map[KeyType]ValueType),
+		deletedEntries: make(map[KeyType]ValueType),
+	}
+}
+
+// AddedEntries returns the entries that were added (key -> new value).
+func (m *mapMutations[KeyType, ValueType]) AddedEntries() map[KeyType]ValueType {
+	return m.addedEntries
+}
+
+// UpdatedEntries returns the existing entries whose value changed (key -> new value).
+func (m *mapMutations[KeyType, ValueType]) UpdatedEntries() map[KeyType]ValueType {
+	return m.updatedEntries
+}
+
+// DeletedEntries returns the entries that were deleted (key -> last value).
+func (m *mapMutations[KeyType, ValueType]) DeletedEntries() map[KeyType]ValueType {
+	return m.deletedEntries
+}
+
+// IsEmpty returns true if the MapMutations instance is empty.
+func (m *mapMutations[KeyType, ValueType]) IsEmpty() bool {
+	return len(m.addedEntries) == 0 && len(m.updatedEntries) == 0 && len(m.deletedEntries) == 0
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,134 @@
+package reactive
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	peers := NewMap[string, int]()
+
+	mutations := make([]MapMutations[string, int], 0)
+	peers.OnUpdate(func(appliedMutations MapMutations[string, int]) {
+		mutations = append(mutations, appliedMutations)
+	})
+
+	previousValue, previousExists := peers.Set("a", 1)
+	require.Equal(t, 0, previousValue)
+	require.False(t, previousExists)
+	require.Len(t, mutations, 1)
+	require.Equal(t, map[string]int{"a": 1}, mutations[0].AddedEntries())
+
+	// per-key views track the value of their key
+	entry, exists := peers.Entry("a")
+	require.True(t, exists)
+
+	entryUpdates := make([]int, 0)
+	entry.OnUpdate(func(_, newValue int) {
+		entryUpdates = append(entryUpdates, newValue)
+	})
+
+	previousValue, previousExists = peers.Set("a", 2)
+	require.Equal(t, 1, previousValue)
+	require.True(t, previousExists)
+	require.Equal(t, map[string]int{"a": 2}, mutations[1].UpdatedEntries())
+	require.Equal(t, []int{1, 2}, entryUpdates)
+
+	// setting the same value is not an update
+	peers.Set("a", 2)
+	require.Len(t, mutations, 2)
+
+	newValue, exists := peers.Compute("b", func(currentValue int, exists bool) (int, bool) {
+		require.False(t, exists)
+
+		return currentValue + 10, true
+	})
+	require.Equal(t, 10, newValue)
+	require.True(t, exists)
+	require.Equal(t, 2, peers.Size())
+
+	// deleted keys reset their views
+	require.True(t, peers.Delete("a"))
+	require.False(t, peers.Delete("a"))
+	require.Equal(t, map[string]int{"a": 2}, mutations[3].DeletedEntries())
+	require.Equal(t, []int{1, 2, 0}, entryUpdates)
+	require.False(t, peers.Has("a"))
+
+	_, exists = peers.Entry("a")
+	require.False(t, exists)
+
+	value, exists := peers.Get("b")
+	require.True(t, exists)
+	require.Equal(t, 10, value)
+
+	// batches deliver the net mutations at once
+	peers.Set("a", 1)
+	mutations = mutations[:0]
+
+	Batch(func(tx *Transaction) {
+		tx.Join(peers)
+
+		peers.Set("c", 1)
+		peers.Set("c", 2)
+		peers.Set("a", 5)
+		peers.Set("a", 1)
+		peers.Set("b", 11)
+		peers.Set("d", 1)
+		peers.Delete("d")
+	})
+
+	require.Len(t, mutations, 1)
+	require.Equal(t, map[string]int{"c": 2}, mutations[0].AddedEntries())
+	require.Equal(t, map[string]int{"b": 11}, mutations[0].UpdatedEntries())
+	require.Empty(t, mutations[0].DeletedEntries())
+}
+
+func TestMapWithEntries(t *testing.T) {
+	peers := NewMap[string, int]()
+	peers.Set("a", 1)
+
+	activeEntries := make(map[string]int)
+	teardown := peers.WithEntries(func(key string, entry ReadableVariable[int]) (teardown func()) {
+		return entry.OnUpdate(func(_, newValue int) {
+			activeEntries[key] = newValue
+		})
+	}, func(key string) bool {
+		return key != "ignored"
+	})
+
+	require.Equal(t, map[string]int{"a": 1}, activeEntries)
+
+	peers.Set("b", 2)
+	peers.Set("ignored", 3)
+	peers.Set("a", 4)
+	require.Equal(t, map[string]int{"a": 4, "b": 2}, activeEntries)
+
+	// the setup of deleted entries is torn down before their views are reset
+	peers.Delete("b")
+	peers.Set("b", 5)
+	require.Equal(t, map[string]int{"a": 4, "b": 5}, activeEntries)
+
+	teardown()
+	peers.Set("a", 6)
+	require.Equal(t, map[string]int{"a": 4, "b": 5}, activeEntries)
+}
+
+func TestMapIterators(t *testing.T) {
+	peers := NewMap[string, int]()
+	peers.Set("a", 1)
+	peers.Set("b", 2)
+
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, maps.Collect(peers.All()))
+	require.ElementsMatch(t, []string{"a", "b"}, slices.Collect(peers.Keys()))
+	require.ElementsMatch(t, []int{1, 2}, slices.Collect(peers.Values()))
+
+	// the map can be modified during the iteration (without affecting the running iteration)
+	for key, value := range peers.All() {
+		peers.Set(key, value*10)
+		peers.Set(key+key, value)
+	}
+	require.Equal(t, map[string]int{"a": 10, "b": 20, "aa": 1, "bb": 2}, maps.Collect(peers.All()))
+}
@@ -2,6 +2,7 @@ package reactive
 
 import (
 	"github.com/iotaledger/hive.go/ds"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
 )
 
 // region Set ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -29,15 +30,45 @@ type ReadableSet[ElementType comparable] interface {
 	// OnUpdate registers the given callback that is triggered when the value changes.
 	OnUpdate(callback func(appliedMutations ds.SetMutations[ElementType]), triggerWithInitialZeroValue ...bool) (unsubscribe func())
 
+	// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the
+	// other in the order they were triggered). Updates that did not start before the subscription is unsubscribed are
+	// skipped.
+	OnUpdateDispatched(workerPool *workerpool.WorkerPool, callback func(appliedMutations ds.SetMutations[ElementType]), triggerWithInitialZeroValue ...bool) (unsubscribe func())
+
 	// SubtractReactive returns a new set that will automatically be updated to always hold all elements of the current
 	// set minus the elements of the other sets.
 	SubtractReactive(others ...ReadableSet[ElementType]) Set[ElementType]
 
+	// UnionReactive returns a new set that will automatically be updated to always hold all elements that are contained
+	// in the current set or in any of the other sets.
+	UnionReactive(others ...ReadableSet[ElementType]) Set[ElementType]
+
+	// IntersectReactive returns a new set that will automatically be updated to always hold the elements that are
+	// contained in the current set and in all of the other sets.
+	IntersectReactive(others ...ReadableSet[ElementType]) Set[ElementType]
+
+	// SymmetricDifferenceReactive returns a new set that will automatically be updated to always hold the elements that
+	// are contained in an odd number of the sets (for two sets these are the elements that are contained in exactly one
+	// of them).
+	SymmetricDifferenceReactive(others ...ReadableSet[ElementType]) Set[ElementType]
+
+	// FilterReactive returns a new set that will automatically be updated to always hold the elements of the current
+	// set that satisfy the given predicate.
+	FilterReactive(predicate func(element ElementType) bool) Set[ElementType]
+
+	// SizeReactive returns a ReadableVariable that will automatically be updated to always hold the size of the set.
+	SizeReactive() ReadableVariable[int]
+
 	// WithElements is a utility function that allows to set up dynamic behavior based on the elements of the Set which
 	// is torn down once the element is removed gi(or the returned teardown function is called). It accepts an optional
 	// condition that has to be satisfied for the setup function to be called.
 	WithElements(setup func(element ElementType) (teardown func()), condition ...func(ElementType) bool) (teardown func())
 
+	// WithElementsDispatched works like WithElements, but executes the setup and teardown functions in the given
+	// WorkerPool (one after the other in the order they were triggered). Setups that did not start before the returned
+	// teardown function is called are skipped.
+	WithElementsDispatched(workerPool *workerpool.WorkerPool, setup func(element ElementType) (teardown func()), condition ...func(ElementType) bool) (teardown func())
+
 	// ReadableSet imports the read methods of the Set interface.
 	ds.ReadableSet[ElementType]
 }
@@ -47,6 +78,12 @@ func NewReadableSet[ElementType comparable](elements ...ElementType) ReadableSet
 	return newReadableSet(elements...)
 }
 
+// NewMappedSet creates a new set that will automatically be updated to always hold the results of applying the given
+// mapping function to the elements of the source set (the mapping function must be deterministic).
+func NewMappedSet[SourceType, TargetType comparable](source ReadableSet[SourceType], mapping func(element SourceType) TargetType) Set[TargetType] {
+	return newMappedSet(source, mapping)
+}
+
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // region DerivedSet ///////////////////////////////////////////////////////////////////////////////////////////////////
@@ -5,6 +5,7 @@ import (
 
 	"github.com/iotaledger/hive.go/ds"
 	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
 	"github.com/iotaledger/hive.go/serializer/v2/serix"
 )
 
@@ -15,6 +16,9 @@ type set[ElementType comparable] struct {
 	// readableSet embeds the ReadableSet implementation.
 	*readableSet[ElementType]
 
+	// batchedUpdate is the update that was collected by the open Transaction (nil if there is none).
+	batchedUpdate *batchedSetUpdate[ElementType]
+
 	// mutex is a mutex that is used to make write operations atomic.
 	mutex sync.Mutex
 }
@@ -56,6 +60,9 @@ func (s *set[ElementType]) Apply(mutations ds.SetMutations[ElementType]) (applie
 	defer s.mutex.Unlock()
 
 	appliedMutations, updateID, registeredCallbacks := s.apply(mutations)
+	if s.collectBatchedUpdate(appliedMutations, updateID) {
+		return appliedMutations
+	}
 
 	for _, registeredCallback := range registeredCallbacks {
 		if registeredCallback.LockExecution(updateID) {
@@ -73,6 +80,9 @@ func (s *set[ElementType]) Compute(mutationFactory func(set ds.ReadableSet[Eleme
 	defer s.mutex.Unlock()
 
 	appliedMutations, updateID, registeredCallbacks := s.apply(mutationFactory(s.readableSet))
+	if s.collectBatchedUpdate(appliedMutations, updateID) {
+		return appliedMutations
+	}
 
 	for _, registeredCallback := range registeredCallbacks {
 		if registeredCallback.LockExecution(updateID) {
@@ -90,6 +100,9 @@ func (s *set[ElementType]) Replace(elements ds.ReadableSet[ElementType]) (remove
 	defer s.mutex.Unlock()
 
 	appliedMutations, updateID, registeredCallbacks := s.replace(elements)
+	if s.collectBatchedUpdate(appliedMutations, updateID) {
+		return appliedMutations.DeletedElements()
+	}
 
 	for _, registeredCallback := range registeredCallbacks {
 		if registeredCallback.LockExecution(updateID) {
@@ -114,6 +127,60 @@ func (s *set[ElementType]) ReadOnly() ds.ReadableSet[ElementType] {
 	return s.readableSet
 }
 
+// collectBatchedUpdate collects the given mutations in the open Transaction of the element and returns false if it did not join one.
+func (s *set[ElementType]) collectBatchedUpdate(appliedMutations ds.SetMutations[ElementType], updateID uniqueID) bool {
+	if s.batchedUpdate == nil {
+		if !collectInTransaction(s) {
+			return false
+		}
+
+		s.batchedUpdate = &batchedSetUpdate[ElementType]{
+			mutations: ds.NewSetMutations[ElementType](),
+		}
+	}
+
+	s.batchedUpdate.updateID = updateID
+
+	// the applied mutations only contain actual changes, so opposing mutations cancel each other out
+	appliedMutations.AddedElements().Range(func(element ElementType) {
+		if !s.batchedUpdate.mutations.DeletedElements().Delete(element) {
+			s.batchedUpdate.mutations.AddedElements().Add(element)
+		}
+	})
+	appliedMutations.DeletedElements().Range(func(element ElementType) {
+		if !s.batchedUpdate.mutations.AddedElements().Delete(element) {
+			s.batchedUpdate.mutations.DeletedElements().Add(element)
+		}
+	})
+
+	return true
+}
+
+// triggerBatchedUpdate notifies the subscribers about the mutations that were collected by a Batch.
+func (s *set[ElementType]) triggerBatchedUpdate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	batchedUpdate := s.batchedUpdate
+	if batchedUpdate == nil || batchedUpdate.mutations.IsEmpty() {
+		s.batchedUpdate = nil
+
+		return
+	}
+	s.batchedUpdate = nil
+
+	s.readableSet.mutex.RLock()
+	registeredCallbacks := s.updateCallbacks.Values()
+	s.readableSet.mutex.RUnlock()
+
+	for _, registeredCallback := range registeredCallbacks {
+		if registeredCallback.LockExecution(batchedUpdate.updateID) {
+			registeredCallback.Invoke(batchedUpdate.mutations)
+			registeredCallback.UnlockExecution()
+		}
+	}
+}
+
 // apply applies the given mutations to the set.
 func (s *set[ElementType]) apply(mutations ds.SetMutations[ElementType]) (appliedMutations ds.SetMutations[ElementType], triggerID uniqueID, callbacksToTrigger []*callback[func(ds.SetMutations[ElementType])]) {
 	s.readableSet.mutex.Lock()
@@ -132,6 +199,19 @@ func (s *set[ElementType]) replace(elements ds.ReadableSet[ElementType]) (applie
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// region batchedSetUpdate /////////////////////////////////////////////////////////////////////////////////////////////
+
+// batchedSetUpdate is an update of a set that was collected by a Batch.
+type batchedSetUpdate[ElementType comparable] struct {
+	// mutations are the net mutations of the set since the start of the Batch.
+	mutations ds.SetMutations[ElementType]
+
+	// updateID is the identifier of the latest update of the set.
+	updateID uniqueID
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // region readableSet //////////////////////////////////////////////////////////////////////////////////////////////////
 
 // readableSet is th standard implementation of the ReadableSet interface.
@@ -148,6 +228,9 @@ type readableSet[ElementType comparable] struct {
 	// mutex is the mutex that is used to synchronize the access to the value.
 	mutex sync.RWMutex
 
+	// elementRank tracks the position of the set in the dependency graph.
+	elementRank
+
 	// Readable embeds the set.Readable interface.
 	ds.ReadableSet[ElementType]
 }
@@ -189,10 +272,19 @@ func (r *readableSet[ElementType]) OnUpdate(callback func(appliedMutations ds.Se
 	}
 }
 
+// describeGraphNode returns the current value, the number of updates and the number of subscribers of the set.
+func (r *readableSet[ElementType]) describeGraphNode() (value string, updateCount uint64, subscriberCount int) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.value.String(), uint64(r.uniqueUpdateID), r.updateCallbacks.Len()
+}
+
 // SubtractReactive returns a new set that will automatically be updated to always hold all elements of the current set
 // minus the elements of the other sets.
 func (r *readableSet[ElementType]) SubtractReactive(others ...ReadableSet[ElementType]) Set[ElementType] {
-	s := NewSet[ElementType]()
+	s := newSet[ElementType]()
+	s.raiseRank(r)
 
 	setArithmetic := ds.NewSetArithmetic[ElementType]()
 
@@ -203,6 +295,8 @@ func (r *readableSet[ElementType]) SubtractReactive(others ...ReadableSet[Elemen
 	})
 
 	for _, other := range others {
+		s.raiseRank(other)
+
 		other.OnUpdate(func(mutations ds.SetMutations[ElementType]) {
 			s.Compute(func(ds.ReadableSet[ElementType]) ds.SetMutations[ElementType] {
 				return setArithmetic.Subtract(mutations)
@@ -213,6 +307,125 @@ func (r *readableSet[ElementType]) SubtractReactive(others ...ReadableSet[Elemen
 	return s
 }
 
+// UnionReactive returns a new set that will automatically be updated to always hold all elements that are contained in
+// the current set or in any of the other sets.
+func (r *readableSet[ElementType]) UnionReactive(others ...ReadableSet[ElementType]) Set[ElementType] {
+	s := newDerivedSet[ElementType]()
+	s.InheritFrom(append([]ReadableSet[ElementType]{r}, others...)...)
+
+	return s
+}
+
+// IntersectReactive returns a new set that will automatically be updated to always hold the elements that are contained
+// in the current set and in all of the other sets.
+func (r *readableSet[ElementType]) IntersectReactive(others ...ReadableSet[ElementType]) Set[ElementType] {
+	s := newSet[ElementType]()
+
+	// an element is part of the intersection once it was added by all of the sets
+	setArithmetic := ds.NewSetArithmetic[ElementType]()
+	threshold := len(others) + 1
+
+	for _, source := range append([]ReadableSet[ElementType]{r}, others...) {
+		dependsOn(s, "IntersectReactive", source)
+
+		source.OnUpdate(func(mutations ds.SetMutations[ElementType]) {
+			s.Compute(func(ds.ReadableSet[ElementType]) ds.SetMutations[ElementType] {
+				return setArithmetic.Add(mutations, threshold)
+			})
+		})
+	}
+
+	return s
+}
+
+// SymmetricDifferenceReactive returns a new set that will automatically be updated to always hold the elements that
+// are contained in an odd number of the sets (for two sets these are the elements that are contained in exactly one of
+// them).
+func (r *readableSet[ElementType]) SymmetricDifferenceReactive(others ...ReadableSet[ElementType]) Set[ElementType] {
+	s := newSet[ElementType]()
+
+	// occurrences counts the number of sets that contain an element (it is only accessed while s is locked)
+	occurrences := make(map[ElementType]int)
+
+	for _, source := range append([]ReadableSet[ElementType]{r}, others...) {
+		dependsOn(s, "SymmetricDifferenceReactive", source)
+
+		source.OnUpdate(func(mutations ds.SetMutations[ElementType]) {
+			s.Compute(func(ds.ReadableSet[ElementType]) ds.SetMutations[ElementType] {
+				appliedMutations := ds.NewSetMutations[ElementType]()
+
+				toggleElement := func(element ElementType, delta int) {
+					if occurrences[element] += delta; occurrences[element] == 0 {
+						delete(occurrences, element)
+					}
+
+					if occurrences[element]%2 == 1 {
+						appliedMutations.AddedElements().Add(element)
+					} else {
+						appliedMutations.DeletedElements().Add(element)
+					}
+				}
+
+				mutations.AddedElements().Range(func(element ElementType) { toggleElement(element, 1) })
+				mutations.DeletedElements().Range(func(element ElementType) { toggleElement(element, -1) })
+
+				return appliedMutations
+			})
+		})
+	}
+
+	return s
+}
+
+// FilterReactive returns a new set that will automatically be updated to always hold the elements of the current set
+// that satisfy the given predicate.
+func (r *readableSet[ElementType]) FilterReactive(predicate func(element ElementType) bool) Set[ElementType] {
+	s := newSet[ElementType]()
+	dependsOn(s, "FilterReactive", r)
+
+	r.OnUpdate(func(mutations ds.SetMutations[ElementType]) {
+		s.Apply(ds.NewSetMutations[ElementType]().
+			WithAddedElements(mutations.AddedElements().Filter(predicate)).
+			WithDeletedElements(mutations.DeletedElements().Filter(predicate)),
+		)
+	})
+
+	return s
+}
+
+// SizeReactive returns a ReadableVariable that will automatically be updated to always hold the size of the set.
+func (r *readableSet[ElementType]) SizeReactive() ReadableVariable[int] {
+	size := newVariable[int]()
+	dependsOn(size, "SizeReactive", r)
+
+	r.OnUpdate(func(mutations ds.SetMutations[ElementType]) {
+		size.Compute(func(currentSize int) int {
+			return currentSize + mutations.AddedElements().Size() - mutations.DeletedElements().Size()
+		})
+	})
+
+	return size
+}
+
+// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the other
+// in the order they were triggered). Updates that did not start before the subscription is unsubscribed are skipped.
+func (r *readableSet[ElementType]) OnUpdateDispatched(workerPool *workerpool.WorkerPool, callback func(appliedMutations ds.SetMutations[ElementType]), triggerWithInitialZeroValue ...bool) (unsubscribe func()) {
+	d := newDispatcher(workerPool)
+
+	return d.subscription(r.OnUpdate(func(appliedMutations ds.SetMutations[ElementType]) {
+		d.dispatchCallback(func() { callback(appliedMutations) })
+	}, triggerWithInitialZeroValue...))
+}
+
+// WithElementsDispatched works like WithElements, but executes the setup and teardown functions in the given
+// WorkerPool (one after the other in the order they were triggered). Setups that did not start before the returned
+// teardown function is called are skipped.
+func (r *readableSet[ElementType]) WithElementsDispatched(workerPool *workerpool.WorkerPool, setup func(element ElementType) (teardown func()), condition ...func(ElementType) bool) (teardown func()) {
+	d := newDispatcher(workerPool)
+
+	return d.subscription(r.WithElements(dispatchSetup(d, setup), condition...))
+}
+
 // WithElements is a utility function that allows to set up dynamic behavior based on the elements of the Set which is
 // torn down once the element is removed (or the returned teardown function is called). It accepts an optional
 // condition that has to be satisfied for the setup function to be called.
@@ -250,6 +463,36 @@ func (r *readableSet[ElementType]) WithElements(setup func(element ElementType)
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// region mappedSet //////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// newMappedSet creates a new set that holds the results of applying the given mapping function to the elements of the
+// source set.
+func newMappedSet[SourceType, TargetType comparable](source ReadableSet[SourceType], mapping func(element SourceType) TargetType) *set[TargetType] {
+	s := newSet[TargetType]()
+	dependsOn(s, "NewMappedSet", source)
+
+	// different source elements can be mapped to the same target element, so we count the occurrences
+	setArithmetic := ds.NewSetArithmetic[TargetType]()
+
+	source.OnUpdate(func(mutations ds.SetMutations[SourceType]) {
+		s.Compute(func(ds.ReadableSet[TargetType]) ds.SetMutations[TargetType] {
+			appliedMutations := ds.NewSetMutations[TargetType]()
+
+			addElement := setArithmetic.AddedElementsCollector(appliedMutations)
+			mutations.AddedElements().Range(func(element SourceType) { addElement(mapping(element)) })
+
+			deleteElement := setArithmetic.SubtractedElementsCollector(appliedMutations)
+			mutations.DeletedElements().Range(func(element SourceType) { deleteElement(mapping(element)) })
+
+			return appliedMutations
+		})
+	})
+
+	return s
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // region derivedSet ///////////////////////////////////////////////////////////////////////////////////////////////////
 
 // derivedSet is the standard implementation of the DerivedSet interface.
@@ -274,6 +517,8 @@ func (s *derivedSet[ElementType]) InheritFrom(sources ...ReadableSet[ElementType
 	unsubscribeCallbacks := make([]func(), 0)
 
 	for _, source := range sources {
+		unsubscribeCallbacks = append(unsubscribeCallbacks, dependsOn(s, "InheritFrom", source))
+
 		sourceElements := ds.NewSet[ElementType]()
 
 		unsubscribeFromSource := source.OnUpdate(func(appliedMutations ds.SetMutations[ElementType]) {
@@ -296,6 +541,9 @@ func (s *derivedSet[ElementType]) inheritMutations(mutations ds.SetMutations[Ele
 	defer s.mutex.Unlock()
 
 	appliedMutations, updateID, registeredCallbacks := s.applyInheritedMutations(mutations)
+	if s.collectBatchedUpdate(appliedMutations, updateID) {
+		return appliedMutations
+	}
 
 	for _, registeredCallback := range registeredCallbacks {
 		if registeredCallback.LockExecution(updateID) {
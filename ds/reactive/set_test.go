@@ -369,3 +369,107 @@ func TestSet_Encoding(t *testing.T) {
 
 	require.True(t, testSet.Equals(decoded))
 }
+
+func TestReadableSet_UnionReactive(t *testing.T) {
+	set1 := NewSet[int](1, 2)
+	set2 := NewSet[int](2, 3)
+
+	union := set1.UnionReactive(set2)
+	require.True(t, union.Equals(ds.NewSet(1, 2, 3)))
+
+	set1.Delete(2)
+	require.True(t, union.Equals(ds.NewSet(1, 2, 3)))
+
+	set2.Delete(2)
+	require.True(t, union.Equals(ds.NewSet(1, 3)))
+}
+
+func TestReadableSet_IntersectReactive(t *testing.T) {
+	set1 := NewSet[int](1, 2, 3)
+	set2 := NewSet[int](2, 3, 4)
+	set3 := NewSet[int](3, 4)
+
+	intersection := set1.IntersectReactive(set2, set3)
+	require.True(t, intersection.Equals(ds.NewSet(3)))
+
+	set3.Add(2)
+	require.True(t, intersection.Equals(ds.NewSet(2, 3)))
+
+	set1.Delete(3)
+	require.True(t, intersection.Equals(ds.NewSet(2)))
+
+	set1.Add(4)
+	require.True(t, intersection.Equals(ds.NewSet(2, 4)))
+}
+
+func TestReadableSet_SymmetricDifferenceReactive(t *testing.T) {
+	set1 := NewSet[int](1, 2)
+	set2 := NewSet[int](2, 3)
+
+	symmetricDifference := set1.SymmetricDifferenceReactive(set2)
+	require.True(t, symmetricDifference.Equals(ds.NewSet(1, 3)))
+
+	set1.Add(3)
+	require.True(t, symmetricDifference.Equals(ds.NewSet(1)))
+
+	set2.Delete(2)
+	require.True(t, symmetricDifference.Equals(ds.NewSet(1, 2)))
+
+	set2.Add(1)
+	set2.Delete(3)
+	require.True(t, symmetricDifference.Equals(ds.NewSet(2, 3)))
+}
+
+func TestReadableSet_FilterReactive(t *testing.T) {
+	source := NewSet[int](1, 2, 3, 4)
+
+	even := source.FilterReactive(func(element int) bool { return element%2 == 0 })
+	require.True(t, even.Equals(ds.NewSet(2, 4)))
+
+	mutations := make([]ds.SetMutations[int], 0)
+	even.OnUpdate(func(appliedMutations ds.SetMutations[int]) {
+		mutations = append(mutations, appliedMutations)
+	})
+
+	source.Add(5)
+	source.Add(6)
+	source.Delete(2)
+	require.True(t, even.Equals(ds.NewSet(4, 6)))
+	require.Len(t, mutations, 3)
+}
+
+func TestReadableSet_SizeReactive(t *testing.T) {
+	source := NewSet[int](1, 2)
+
+	size := source.SizeReactive()
+	require.Equal(t, 2, size.Get())
+
+	source.AddAll(ds.NewSet(3, 4))
+	require.Equal(t, 4, size.Get())
+
+	source.Replace(ds.NewSet(5))
+	require.Equal(t, 1, size.Get())
+
+	Batch(func(tx *Transaction) {
+		tx.Join(source)
+
+		source.Add(6)
+		source.Delete(6)
+	})
+	require.Equal(t, 1, size.Get())
+}
+
+func TestNewMappedSet(t *testing.T) {
+	validators := NewSet[string]("alice", "bob", "carol")
+
+	initials := NewMappedSet(validators, func(validator string) byte { return validator[0] })
+	require.True(t, initials.Equals(ds.NewSet[byte]('a', 'b', 'c')))
+
+	// elements that are mapped to the same target are counted
+	validators.Add("anna")
+	validators.Delete("alice")
+	require.True(t, initials.Equals(ds.NewSet[byte]('a', 'b', 'c')))
+
+	validators.Delete("anna")
+	require.True(t, initials.Equals(ds.NewSet[byte]('b', 'c')))
+}
@@ -2,13 +2,15 @@ package reactive
 
 import (
 	"cmp"
+	"iter"
 )
 
 // region SortedSet ////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // SortedSet is a reactive Set implementation that allows consumers to subscribe to its changes and that keeps a sorted
 // perception of its elements. If the ElementType implements a Less method, it will be used to break ties between
-// elements with the same weight.
+// elements with the same weight. Remaining ties are broken by the order in which the elements were added (elements
+// that were added earlier are considered heavier), independently of how their weights changed over time.
 type SortedSet[ElementType comparable] interface {
 	// Set imports the methods of the Set interface.
 	Set[ElementType]
@@ -19,11 +21,33 @@ type SortedSet[ElementType comparable] interface {
 	// Descending returns a slice of all elements of the set in descending order.
 	Descending() []ElementType
 
+	// All returns a sequence of all elements of the set in descending order (the order of Rank). Every iteration works
+	// on a snapshot of the elements that is taken when the iteration starts, so the set can be modified while iterating.
+	All() iter.Seq[ElementType]
+
+	// Backward returns a sequence of all elements of the set in ascending order (with the same snapshot semantics as
+	// All).
+	Backward() iter.Seq[ElementType]
+
 	// HeaviestElement returns the element with the heaviest weight.
 	HeaviestElement() ReadableVariable[ElementType]
 
 	// LightestElement returns the element with the lightest weight.
 	LightestElement() ReadableVariable[ElementType]
+
+	// TopK returns a ReadableSet that is automatically updated to always hold the k heaviest elements of the set and a
+	// function that stops updating it.
+	TopK(k int) (topK ReadableSet[ElementType], unsubscribe func())
+
+	// Rank returns the position of the given element in descending order (the heaviest element has rank 0).
+	Rank(element ElementType) (rank int, exists bool)
+
+	// ElementAt returns the element at the given position in descending order (the heaviest element has rank 0).
+	ElementAt(rank int) (element ElementType, exists bool)
+
+	// RangeByRank iterates over the elements in descending order starting at the given rank (until the consumer
+	// returns false). The consumer must not modify the set.
+	RangeByRank(fromRank int, consumer func(element ElementType) bool)
 }
 
 // NewSortedSet creates a new SortedSet instance that sorts its elements by the given weightVariable. If the ElementType
@@ -33,3 +57,27 @@ func NewSortedSet[ElementType comparable, WeightType cmp.Ordered](weightVariable
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region WeightedSortedSet ////////////////////////////////////////////////////////////////////////////////////////////
+
+// WeightedSortedSet is a SortedSet that additionally allows to query its elements by their weight.
+type WeightedSortedSet[ElementType comparable, WeightType cmp.Ordered] interface {
+	// SortedSet imports the methods of the SortedSet interface.
+	SortedSet[ElementType]
+
+	// AscendFrom iterates over the elements with a weight of at least the given weight in ascending order (until the
+	// consumer returns false). The consumer must not modify the set.
+	AscendFrom(minWeight WeightType, consumer func(element ElementType, weight WeightType) bool)
+
+	// DescendFrom iterates over the elements with a weight of at most the given weight in descending order (until the
+	// consumer returns false). The consumer must not modify the set.
+	DescendFrom(maxWeight WeightType, consumer func(element ElementType, weight WeightType) bool)
+}
+
+// NewWeightedSortedSet creates a new WeightedSortedSet instance that sorts its elements by the given weightVariable
+// (see NewSortedSet).
+func NewWeightedSortedSet[ElementType comparable, WeightType cmp.Ordered](weightVariable func(element ElementType) Variable[WeightType]) WeightedSortedSet[ElementType, WeightType] {
+	return newSortedSet(weightVariable)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
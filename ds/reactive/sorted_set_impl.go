@@ -2,6 +2,8 @@ package reactive
 
 import (
 	"cmp"
+	"iter"
+	"math/rand"
 
 	"github.com/iotaledger/hive.go/ds"
 	"github.com/iotaledger/hive.go/ds/shrinkingmap"
@@ -18,8 +20,11 @@ type sortedSet[ElementType comparable, WeightType cmp.Ordered] struct {
 	// elements is a map of all elements that are part of the set.
 	elements *shrinkingmap.ShrinkingMap[ElementType, *sortedSetElement[ElementType, WeightType]]
 
-	// sortedElements is a slice of all elements that are part of the set, sorted by their weight.
-	sortedElements []*sortedSetElement[ElementType, WeightType]
+	// root is the root of the order-statistics tree that keeps the elements sorted in descending order.
+	root *sortedSetElement[ElementType, WeightType]
+
+	// sequence is used to break ties between elements with the same weight (that don't implement a Less method).
+	sequence uint64
 
 	// heaviestElement is a reference to the element with the heaviest weight.
 	heaviestElement Variable[ElementType]
@@ -27,10 +32,13 @@ type sortedSet[ElementType comparable, WeightType cmp.Ordered] struct {
 	// lightestElement is a reference to the element with the lightest weight.
 	lightestElement Variable[ElementType]
 
+	// topKViews contains the views that track the heaviest elements of the set.
+	topKViews ds.List[*topKView[ElementType]]
+
 	// weightVariable is the function that is used to retrieve the weight of an element.
 	weightVariable func(element ElementType) Variable[WeightType]
 
-	// mutex is used to synchronize access to the sortedElements slice.
+	// mutex is used to synchronize access to the order-statistics tree.
 	mutex syncutils.RWMutex
 }
 
@@ -40,9 +48,9 @@ func newSortedSet[ElementType comparable, WeightType cmp.Ordered](weightVariable
 	s := &sortedSet[ElementType, WeightType]{
 		Set:             NewSet[ElementType](),
 		elements:        shrinkingmap.New[ElementType, *sortedSetElement[ElementType, WeightType]](),
-		sortedElements:  make([]*sortedSetElement[ElementType, WeightType], 0),
 		heaviestElement: NewVariable[ElementType](),
 		lightestElement: NewVariable[ElementType](),
+		topKViews:       ds.NewList[*topKView[ElementType]](),
 		weightVariable:  weightVariable,
 	}
 
@@ -59,12 +67,14 @@ func (s *sortedSet[ElementType, WeightType]) Ascending() (sortedSlice []ElementT
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	if sortedElementsCount := len(s.sortedElements); sortedElementsCount > 0 {
-		sortedSlice = make([]ElementType, sortedElementsCount)
+	if sortedElementsCount := s.root.subtreeSize(); sortedElementsCount > 0 {
+		sortedSlice = make([]ElementType, 0, sortedElementsCount)
 
-		for i, sortedElement := range s.sortedElements {
-			sortedSlice[sortedElementsCount-i-1] = sortedElement.element
-		}
+		s.root.walkBackward(0, sortedElementsCount-1, func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+			sortedSlice = append(sortedSlice, sortedElement.element)
+
+			return true
+		})
 	}
 
 	return sortedSlice
@@ -75,17 +85,42 @@ func (s *sortedSet[ElementType, WeightType]) Descending() (sortedSlice []Element
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	if sortedElementsCount := len(s.sortedElements); sortedElementsCount > 0 {
-		sortedSlice = make([]ElementType, sortedElementsCount)
+	if sortedElementsCount := s.root.subtreeSize(); sortedElementsCount > 0 {
+		sortedSlice = make([]ElementType, 0, sortedElementsCount)
 
-		for i, sortedElement := range s.sortedElements {
-			sortedSlice[i] = sortedElement.element
-		}
+		s.root.walkForward(0, 0, func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+			sortedSlice = append(sortedSlice, sortedElement.element)
+
+			return true
+		})
 	}
 
 	return sortedSlice
 }
 
+// All returns a sequence of all elements of the set in descending order (iterating over a snapshot of the elements).
+func (s *sortedSet[ElementType, WeightType]) All() iter.Seq[ElementType] {
+	return func(yield func(ElementType) bool) {
+		for _, element := range s.Descending() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a sequence of all elements of the set in ascending order (iterating over a snapshot of the
+// elements).
+func (s *sortedSet[ElementType, WeightType]) Backward() iter.Seq[ElementType] {
+	return func(yield func(ElementType) bool) {
+		for _, element := range s.Ascending() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
 // HeaviestElement returns the element with the heaviest weight.
 func (s *sortedSet[ElementType, WeightType]) HeaviestElement() ReadableVariable[ElementType] {
 	return s.heaviestElement
@@ -96,29 +131,127 @@ func (s *sortedSet[ElementType, WeightType]) LightestElement() ReadableVariable[
 	return s.lightestElement
 }
 
-// addSorted adds the given element to the sortedElements slice.
+// TopK returns a ReadableSet that is automatically updated to always hold the k heaviest elements of the set and a
+// function that stops updating it.
+func (s *sortedSet[ElementType, WeightType]) TopK(k int) (topK ReadableSet[ElementType], unsubscribe func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	view := newTopKView[ElementType](k)
+	if k > 0 {
+		s.root.walkForward(0, 0, func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+			view.add(sortedElement.element)
+
+			return view.mutations.AddedElements().Size() < k
+		})
+		view.applyMutations()
+	}
+
+	listElement := s.topKViews.PushBack(view)
+
+	return view.set, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.topKViews.Remove(listElement)
+	}
+}
+
+// Rank returns the position of the given element in descending order (the heaviest element has rank 0).
+func (s *sortedSet[ElementType, WeightType]) Rank(element ElementType) (rank int, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	sortedElement, exists := s.elements.Get(element)
+	if !exists || !sortedElement.isSorted {
+		return 0, false
+	}
+
+	return s.rank(sortedElement), true
+}
+
+// ElementAt returns the element at the given position in descending order (the heaviest element has rank 0).
+func (s *sortedSet[ElementType, WeightType]) ElementAt(rank int) (element ElementType, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if sortedElement := s.root.elementAt(rank); sortedElement != nil {
+		return sortedElement.element, true
+	}
+
+	return element, false
+}
+
+// RangeByRank iterates over the elements in descending order starting at the given rank (until the consumer returns
+// false). The consumer must not modify the set.
+func (s *sortedSet[ElementType, WeightType]) RangeByRank(fromRank int, consumer func(element ElementType) bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	s.root.walkForward(0, max(fromRank, 0), func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+		return consumer(sortedElement.element)
+	})
+}
+
+// AscendFrom iterates over the elements with a weight of at least the given weight in ascending order (until the
+// consumer returns false). The consumer must not modify the set.
+func (s *sortedSet[ElementType, WeightType]) AscendFrom(minWeight WeightType, consumer func(element ElementType, weight WeightType) bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if lastRank := s.root.countHeavier(minWeight, true) - 1; lastRank >= 0 {
+		s.root.walkBackward(0, lastRank, func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+			return consumer(sortedElement.element, sortedElement.weight)
+		})
+	}
+}
+
+// DescendFrom iterates over the elements with a weight of at most the given weight in descending order (until the
+// consumer returns false). The consumer must not modify the set.
+func (s *sortedSet[ElementType, WeightType]) DescendFrom(maxWeight WeightType, consumer func(element ElementType, weight WeightType) bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	s.root.walkForward(0, s.root.countHeavier(maxWeight, false), func(sortedElement *sortedSetElement[ElementType, WeightType]) bool {
+		return consumer(sortedElement.element, sortedElement.weight)
+	})
+}
+
+// addSorted adds the given element to the order-statistics tree.
 func (s *sortedSet[ElementType, WeightType]) addSorted(element ElementType) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if listElement, created := s.elements.GetOrCreate(element, func() *sortedSetElement[ElementType, WeightType] {
-		return newSortedSetElement(element, s)
+	if sortedElement, created := s.elements.GetOrCreate(element, func() *sortedSetElement[ElementType, WeightType] {
+		s.sequence++
+
+		return newSortedSetElement[WeightType](element, s.sequence)
 	}); created {
-		listElement.unsubscribeFromWeightUpdates = s.weightVariable(element).OnUpdate(func(_ WeightType, newWeight WeightType) {
+		sortedElement.unsubscribeFromWeightUpdates = s.weightVariable(element).OnUpdate(func(_ WeightType, newWeight WeightType) {
 			// only lock if this is not the initial update
-			if listElement.unsubscribeFromWeightUpdates != nil {
+			if sortedElement.unsubscribeFromWeightUpdates != nil {
 				s.mutex.Lock()
 				defer s.mutex.Unlock()
 			}
 
-			listElement.weight = newWeight
+			// ignore updates that arrive after the element was deleted
+			if sortedElement.isDeleted {
+				return
+			}
+
+			if sortedElement.isSorted {
+				s.removeFromTree(sortedElement)
+			}
 
-			s.updatePosition(listElement)
+			sortedElement.weight = newWeight
+
+			s.insertIntoTree(sortedElement)
+			s.updateViews()
 		}, true)
 	}
 }
 
-// deleteSorted deletes the given element from the sortedElements slice.
+// deleteSorted deletes the given element from the order-statistics tree.
 func (s *sortedSet[ElementType, WeightType]) deleteSorted(element ElementType) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -126,89 +259,169 @@ func (s *sortedSet[ElementType, WeightType]) deleteSorted(element ElementType) {
 	if deletedElement, deleted := s.elements.DeleteAndReturn(element); deleted {
 		// unsubscribe from weight updates
 		deletedElement.unsubscribeFromWeightUpdates()
+		deletedElement.isDeleted = true
 
-		// shift all elements to the right of the deleted element one position to the left
-		for i := deletedElement.index; i < len(s.sortedElements)-1; i++ {
-			s.sortedElements[i] = s.sortedElements[i+1]
-			s.sortedElements[i].index--
+		if deletedElement.isSorted {
+			s.removeFromTree(deletedElement)
+			s.updateViews()
 		}
+	}
+}
+
+// insertIntoTree inserts the given element into the order-statistics tree and collects the resulting changes of the
+// top-K views.
+func (s *sortedSet[ElementType, WeightType]) insertIntoTree(element *sortedSetElement[ElementType, WeightType]) {
+	left, right := s.split(s.root, element)
+	s.root = s.merge(s.merge(left, element), right)
+	element.isSorted = true
 
-		// prevent memory leak and shrink slice
-		s.sortedElements[len(s.sortedElements)-1] = nil
-		s.sortedElements = s.sortedElements[:len(s.sortedElements)-1]
+	rank := s.rank(element)
+	s.topKViews.Range(func(view *topKView[ElementType]) {
+		if rank < view.k {
+			view.add(element.element)
 
-		// update heaviest and lightest element
-		if deletedElement.index == 0 {
-			if len(s.sortedElements) > 0 {
-				s.heaviestElement.Set(s.sortedElements[0].element)
-			} else {
-				s.heaviestElement.Set(*new(ElementType))
+			if displacedElement := s.root.elementAt(view.k); displacedElement != nil {
+				view.delete(displacedElement.element)
 			}
 		}
-		if deletedElement.index == len(s.sortedElements) {
-			if len(s.sortedElements) > 0 {
-				s.lightestElement.Set(s.sortedElements[len(s.sortedElements)-1].element)
-			} else {
-				s.lightestElement.Set(*new(ElementType))
+	})
+}
+
+// removeFromTree removes the given element from the order-statistics tree and collects the resulting changes of the
+// top-K views.
+func (s *sortedSet[ElementType, WeightType]) removeFromTree(element *sortedSetElement[ElementType, WeightType]) {
+	rank := s.rank(element)
+
+	s.root = s.remove(s.root, element)
+	element.isSorted = false
+
+	s.topKViews.Range(func(view *topKView[ElementType]) {
+		if rank < view.k {
+			view.delete(element.element)
+
+			if advancedElement := s.root.elementAt(view.k - 1); advancedElement != nil {
+				view.add(advancedElement.element)
 			}
 		}
+	})
+}
+
+// updateViews applies the collected changes to the top-K views and updates the heaviest and lightest element.
+func (s *sortedSet[ElementType, WeightType]) updateViews() {
+	s.topKViews.Range(func(view *topKView[ElementType]) {
+		view.applyMutations()
+	})
+
+	if heaviestElement := s.root.elementAt(0); heaviestElement != nil {
+		s.heaviestElement.Set(heaviestElement.element)
+	} else {
+		s.heaviestElement.Set(*new(ElementType))
+	}
+
+	if lightestElement := s.root.elementAt(s.root.subtreeSize() - 1); lightestElement != nil {
+		s.lightestElement.Set(lightestElement.element)
+	} else {
+		s.lightestElement.Set(*new(ElementType))
 	}
 }
 
-// updatePosition updates the position of the given element in the sortedElements slice.
-func (s *sortedSet[ElementType, WeightType]) updatePosition(element *sortedSetElement[ElementType, WeightType]) (moved bool) {
-	// update heaviest and lightest references after we are done moving the element
-	defer func(fromIndex int) {
-		if moved && fromIndex == 0 {
-			// moved away from the heaviest element
-			s.heaviestElement.Set(s.sortedElements[0].element)
-		} else if element.index == 0 { // We check the index of the element after it is moved.
-			// moved towards the heaviest element
-			s.heaviestElement.Set(element.element)
+// rank returns the position of the given (sorted) element in descending order.
+func (s *sortedSet[ElementType, WeightType]) rank(element *sortedSetElement[ElementType, WeightType]) (rank int) {
+	for currentElement := s.root; currentElement != nil; {
+		switch s.compare(element, currentElement) {
+		case -1:
+			currentElement = currentElement.left
+		case 1:
+			rank += currentElement.left.subtreeSize() + 1
+			currentElement = currentElement.right
+		default:
+			return rank + currentElement.left.subtreeSize()
 		}
+	}
 
-		if moved && fromIndex == len(s.sortedElements)-1 {
-			// moved away from the lightest element
-			s.lightestElement.Set(s.sortedElements[len(s.sortedElements)-1].element)
-		} else if element.index == len(s.sortedElements)-1 { // We check the index of the element after it is moved.
-			// moved towards the lightest element
-			s.lightestElement.Set(element.element)
-		}
-	}(element.index) // We capture the index of the element before it is moved.
+	panic("element is not part of the sorted set")
+}
 
-	// try to move the element to the left first
-	for ; element.index != 0; moved = true {
-		if !s.swap(s.sortedElements[element.index-1], element) {
-			break
-		}
+// split splits the given subtree into the elements that are sorted before the given element and the remaining ones.
+func (s *sortedSet[ElementType, WeightType]) split(subtree, element *sortedSetElement[ElementType, WeightType]) (left, right *sortedSetElement[ElementType, WeightType]) {
+	if subtree == nil {
+		return nil, nil
 	}
 
-	// if the element was not moved to the left, try to move it to the right
-	if !moved {
-		for ; element.index != len(s.sortedElements)-1; moved = true {
-			if !s.swap(element, s.sortedElements[element.index+1]) {
-				break
-			}
-		}
+	if s.compare(subtree, element) < 0 {
+		subtree.right, right = s.split(subtree.right, element)
+		subtree.updateSize()
+
+		return subtree, right
 	}
 
-	return
+	left, subtree.left = s.split(subtree.left, element)
+	subtree.updateSize()
+
+	return left, subtree
 }
 
-// swap swaps the position of the two given elements in the sortedElements slice.
-func (s *sortedSet[ElementType, WeightType]) swap(left *sortedSetElement[ElementType, WeightType], right *sortedSetElement[ElementType, WeightType]) (swapped bool) {
-	if swapped = left.weight < right.weight; !swapped && left.weight == right.weight {
-		if leftAsLessable, ok := ((any)(left.element)).(lessable[ElementType]); ok {
-			swapped = leftAsLessable.Less(right.element)
-		}
+// merge merges the given subtrees (all elements of the left subtree must be sorted before the right subtree).
+func (s *sortedSet[ElementType, WeightType]) merge(left, right *sortedSetElement[ElementType, WeightType]) *sortedSetElement[ElementType, WeightType] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left.right = s.merge(left.right, right)
+		left.updateSize()
+
+		return left
+	default:
+		right.left = s.merge(left, right.left)
+		right.updateSize()
+
+		return right
+	}
+}
+
+// remove removes the given element from the given subtree and returns the new root of the subtree.
+func (s *sortedSet[ElementType, WeightType]) remove(subtree, element *sortedSetElement[ElementType, WeightType]) *sortedSetElement[ElementType, WeightType] {
+	switch s.compare(element, subtree) {
+	case -1:
+		subtree.left = s.remove(subtree.left, element)
+	case 1:
+		subtree.right = s.remove(subtree.right, element)
+	default:
+		mergedSubtree := s.merge(subtree.left, subtree.right)
+		subtree.left, subtree.right, subtree.size = nil, nil, 1
+
+		return mergedSubtree
+	}
+
+	subtree.updateSize()
+
+	return subtree
+}
+
+// compare compares the given elements in descending order (heavier elements are sorted first). Ties are broken by the
+// Less method of the elements (if available) and then by the order in which the elements were added.
+func (s *sortedSet[ElementType, WeightType]) compare(left, right *sortedSetElement[ElementType, WeightType]) int {
+	if left == right {
+		return 0
 	}
 
-	if swapped {
-		s.sortedElements[left.index], s.sortedElements[right.index] = s.sortedElements[right.index], s.sortedElements[left.index]
-		left.index, right.index = right.index, left.index
+	if left.weight != right.weight {
+		return cmp.Compare(right.weight, left.weight)
+	}
+
+	if leftAsLessable, ok := ((any)(left.element)).(lessable[ElementType]); ok {
+		if leftAsLessable.Less(right.element) {
+			return 1
+		}
+
+		if ((any)(right.element)).(lessable[ElementType]).Less(left.element) {
+			return -1
+		}
 	}
 
-	return swapped
+	return cmp.Compare(left.sequence, right.sequence)
 }
 
 // lessable is an interface that allows consumers to define a custom less function for a type.
@@ -220,7 +433,8 @@ type lessable[T any] interface {
 
 // region sortedSetElement /////////////////////////////////////////////////////////////////////////////////////////////
 
-// sortedSetElement is an element of the sortedElements slice.
+// sortedSetElement is an element of the order-statistics tree (a treap that is augmented with the size of its
+// subtrees).
 type sortedSetElement[ElementType comparable, WeightType cmp.Ordered] struct {
 	// element is the element that is part of the set.
 	element ElementType
@@ -228,23 +442,171 @@ type sortedSetElement[ElementType comparable, WeightType cmp.Ordered] struct {
 	// weight is the weight of the element.
 	weight WeightType
 
-	// index is the index of the element in the sortedElements slice.
-	index int
+	// sequence is used to break ties between elements with the same weight.
+	sequence uint64
+
+	// priority is the random heap priority of the element in the treap.
+	priority uint32
+
+	// size is the number of elements in the subtree of the element.
+	size int
+
+	// left is the subtree of the elements that are sorted before the element.
+	left *sortedSetElement[ElementType, WeightType]
+
+	// right is the subtree of the elements that are sorted after the element.
+	right *sortedSetElement[ElementType, WeightType]
+
+	// isSorted is true if the element is part of the tree.
+	isSorted bool
+
+	// isDeleted is true if the element was deleted from the set.
+	isDeleted bool
 
 	// unsubscribeFromWeightUpdates is the function that is used to unsubscribe from weight updates.
 	unsubscribeFromWeightUpdates func()
 }
 
 // newSortedSetElement creates a new sortedSetElement instance.
-func newSortedSetElement[WeightType cmp.Ordered, ElementType comparable](element ElementType, sortedSet *sortedSet[ElementType, WeightType]) *sortedSetElement[ElementType, WeightType] {
-	s := &sortedSetElement[ElementType, WeightType]{
-		element: element,
-		index:   len(sortedSet.sortedElements),
+func newSortedSetElement[WeightType cmp.Ordered, ElementType comparable](element ElementType, sequence uint64) *sortedSetElement[ElementType, WeightType] {
+	return &sortedSetElement[ElementType, WeightType]{
+		element:  element,
+		sequence: sequence,
+		priority: rand.Uint32(), //nolint:gosec // the priority of a treap does not need to be cryptographically secure
+		size:     1,
+	}
+}
+
+// subtreeSize returns the number of elements in the subtree of the element (it can be called on nil elements).
+func (s *sortedSetElement[ElementType, WeightType]) subtreeSize() int {
+	if s == nil {
+		return 0
 	}
 
-	sortedSet.sortedElements = append(sortedSet.sortedElements, s)
+	return s.size
+}
 
-	return s
+// updateSize updates the size of the subtree after its children changed.
+func (s *sortedSetElement[ElementType, WeightType]) updateSize() {
+	s.size = s.left.subtreeSize() + s.right.subtreeSize() + 1
+}
+
+// elementAt returns the element at the given position of the subtree (or nil if it does not exist).
+func (s *sortedSetElement[ElementType, WeightType]) elementAt(rank int) *sortedSetElement[ElementType, WeightType] {
+	for currentElement := s; currentElement != nil; {
+		switch leftSize := currentElement.left.subtreeSize(); {
+		case rank < leftSize:
+			currentElement = currentElement.left
+		case rank > leftSize:
+			rank -= leftSize + 1
+			currentElement = currentElement.right
+		default:
+			return currentElement
+		}
+	}
+
+	return nil
+}
+
+// countHeavier returns the number of elements in the subtree whose weight is heavier than (or equal to) the given
+// weight.
+func (s *sortedSetElement[ElementType, WeightType]) countHeavier(weight WeightType, includeEqual bool) (count int) {
+	for currentElement := s; currentElement != nil; {
+		if currentElement.weight > weight || includeEqual && currentElement.weight == weight {
+			count += currentElement.left.subtreeSize() + 1
+			currentElement = currentElement.right
+		} else {
+			currentElement = currentElement.left
+		}
+	}
+
+	return count
+}
+
+// walkForward iterates over the elements of the subtree in descending order starting at the given rank (the offset is
+// the rank of the first element of the subtree).
+func (s *sortedSetElement[ElementType, WeightType]) walkForward(offset, fromRank int, consumer func(element *sortedSetElement[ElementType, WeightType]) bool) bool {
+	if s == nil {
+		return true
+	}
+
+	rank := offset + s.left.subtreeSize()
+	if fromRank < rank && !s.left.walkForward(offset, fromRank, consumer) {
+		return false
+	}
+
+	if fromRank <= rank && !consumer(s) {
+		return false
+	}
+
+	return s.right.walkForward(rank+1, fromRank, consumer)
+}
+
+// walkBackward iterates over the elements of the subtree in ascending order starting at the given rank (the offset is
+// the rank of the first element of the subtree).
+func (s *sortedSetElement[ElementType, WeightType]) walkBackward(offset, fromRank int, consumer func(element *sortedSetElement[ElementType, WeightType]) bool) bool {
+	if s == nil {
+		return true
+	}
+
+	rank := offset + s.left.subtreeSize()
+	if fromRank > rank && !s.right.walkBackward(rank+1, fromRank, consumer) {
+		return false
+	}
+
+	if fromRank >= rank && !consumer(s) {
+		return false
+	}
+
+	return s.left.walkBackward(offset, fromRank, consumer)
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region topKView /////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// topKView is a set that tracks the k heaviest elements of a sortedSet.
+type topKView[ElementType comparable] struct {
+	// k is the number of tracked elements.
+	k int
+
+	// set is the set that holds the tracked elements.
+	set *set[ElementType]
+
+	// mutations are the collected changes that were not applied to the set yet.
+	mutations ds.SetMutations[ElementType]
+}
+
+// newTopKView creates a new topKView that tracks the k heaviest elements.
+func newTopKView[ElementType comparable](k int) *topKView[ElementType] {
+	return &topKView[ElementType]{
+		k:         k,
+		set:       newSet[ElementType](),
+		mutations: ds.NewSetMutations[ElementType](),
+	}
+}
+
+// add collects the addition of the given element (opposing changes cancel each other out).
+func (t *topKView[ElementType]) add(element ElementType) {
+	if !t.mutations.DeletedElements().Delete(element) {
+		t.mutations.AddedElements().Add(element)
+	}
+}
+
+// delete collects the deletion of the given element (opposing changes cancel each other out).
+func (t *topKView[ElementType]) delete(element ElementType) {
+	if !t.mutations.AddedElements().Delete(element) {
+		t.mutations.DeletedElements().Add(element)
+	}
+}
+
+// applyMutations applies the collected changes to the set.
+func (t *topKView[ElementType]) applyMutations() {
+	if !t.mutations.IsEmpty() {
+		t.set.Apply(t.mutations)
+
+		t.mutations = ds.NewSetMutations[ElementType]()
+	}
 }
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1,9 +1,15 @@
 package reactive
 
 import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds"
 )
 
 func Test_SortedSet(t *testing.T) {
@@ -48,6 +54,157 @@ func Test_SortedSet(t *testing.T) {
 	requireOrder(t, []*sortableElement{}, testSet)
 }
 
+func Test_SortedSetTieBreaking(t *testing.T) {
+	weights := map[int]Variable[int]{
+		1: NewVariable[int]().Init(1),
+		2: NewVariable[int]().Init(1),
+		3: NewVariable[int]().Init(1),
+	}
+
+	testSet := NewSortedSet(func(element int) Variable[int] { return weights[element] })
+	testSet.Add(2)
+	testSet.Add(1)
+	testSet.Add(3)
+
+	// elements with the same weight are sorted by the order in which they were added
+	require.Equal(t, 2, testSet.HeaviestElement().Get())
+	require.Equal(t, 3, testSet.LightestElement().Get())
+	require.Equal(t, []int{2, 1, 3}, testSet.Descending())
+
+	// the order does not depend on the previous weights of the elements
+	weights[3].Set(2)
+	require.Equal(t, 3, testSet.HeaviestElement().Get())
+
+	weights[3].Set(1)
+	require.Equal(t, 2, testSet.HeaviestElement().Get())
+	require.Equal(t, 3, testSet.LightestElement().Get())
+
+	weights[2].Set(0)
+	require.Equal(t, 1, testSet.HeaviestElement().Get())
+
+	weights[2].Set(1)
+	require.Equal(t, 2, testSet.HeaviestElement().Get())
+	require.Equal(t, []int{2, 1, 3}, testSet.Descending())
+}
+
+func Test_SortedSetQueries(t *testing.T) {
+	elements := make([]*sortableElement, 10)
+	for i := range elements {
+		elements[i] = newSortableElement(fmt.Sprintf("%02d", i), i)
+	}
+
+	testSet := NewWeightedSortedSet((*sortableElement).weight)
+	for _, element := range elements {
+		testSet.Add(element)
+	}
+
+	topK, unsubscribe := testSet.TopK(3)
+	requireElements(t, topK, elements[9], elements[8], elements[7])
+
+	rank, exists := testSet.Rank(elements[9])
+	require.True(t, exists)
+	require.Equal(t, 0, rank)
+
+	element, exists := testSet.ElementAt(2)
+	require.True(t, exists)
+	require.Equal(t, elements[7], element)
+
+	_, exists = testSet.ElementAt(10)
+	require.False(t, exists)
+
+	// weight changes update the ranks and the top-K view
+	elements[0].Weight.Set(8)
+	requireElements(t, topK, elements[9], elements[8], elements[0])
+
+	rank, exists = testSet.Rank(elements[0])
+	require.True(t, exists)
+	require.Equal(t, 2, rank)
+
+	elements[9].Weight.Set(1)
+	requireElements(t, topK, elements[8], elements[0], elements[7])
+
+	testSet.Delete(elements[8])
+	requireElements(t, topK, elements[0], elements[7], elements[6])
+
+	_, exists = testSet.Rank(elements[8])
+	require.False(t, exists)
+
+	// weight ranges are iterated in the requested order
+	ascending := make([]*sortableElement, 0)
+	testSet.AscendFrom(6, func(element *sortableElement, _ int) bool {
+		ascending = append(ascending, element)
+
+		return true
+	})
+	require.Equal(t, []*sortableElement{elements[6], elements[7], elements[0]}, ascending)
+
+	descending := make([]*sortableElement, 0)
+	testSet.DescendFrom(5, func(element *sortableElement, weight int) bool {
+		descending = append(descending, element)
+
+		return weight > 3
+	})
+	require.Equal(t, []*sortableElement{elements[5], elements[4], elements[3]}, descending)
+
+	ranked := make([]*sortableElement, 0)
+	testSet.RangeByRank(6, func(element *sortableElement) bool {
+		ranked = append(ranked, element)
+
+		return true
+	})
+	require.Equal(t, []*sortableElement{elements[2], elements[9], elements[1]}, ranked)
+
+	// unsubscribed views are no longer updated
+	unsubscribe()
+	elements[1].Weight.Set(10)
+	requireElements(t, topK, elements[0], elements[7], elements[6])
+}
+
+func Test_SortedSetRandomized(t *testing.T) {
+	elements := make([]*sortableElement, 50)
+	for i := range elements {
+		elements[i] = newSortableElement(fmt.Sprintf("%02d", i), rand.Intn(10))
+	}
+
+	testSet := NewSortedSet((*sortableElement).weight)
+	topK, _ := testSet.TopK(5)
+
+	for i := 0; i < 1000; i++ {
+		element := elements[rand.Intn(len(elements))]
+
+		switch rand.Intn(3) {
+		case 0:
+			testSet.Add(element)
+		case 1:
+			testSet.Delete(element)
+		default:
+			element.Weight.Set(rand.Intn(10))
+		}
+
+		expectedOrder := testSet.ToSlice()
+		sort.Slice(expectedOrder, func(i, j int) bool {
+			if expectedOrder[i].Weight.Get() != expectedOrder[j].Weight.Get() {
+				return expectedOrder[i].Weight.Get() > expectedOrder[j].Weight.Get()
+			}
+
+			return expectedOrder[j].Less(expectedOrder[i])
+		})
+
+		requireOrder(t, expectedOrder, testSet)
+		requireElements(t, topK, expectedOrder[:min(5, len(expectedOrder))]...)
+
+		for expectedRank, expectedElement := range expectedOrder {
+			rank, exists := testSet.Rank(expectedElement)
+			require.True(t, exists)
+			require.Equal(t, expectedRank, rank)
+		}
+	}
+}
+
+func requireElements[ElementType comparable](t *testing.T, set ReadableSet[ElementType], expectedElements ...ElementType) {
+	require.True(t, set.Equals(ds.NewSet(expectedElements...)), "expected %v, got %v", expectedElements, set.ToSlice())
+}
+
 func requireOrder[ElementType comparable](t *testing.T, expectedElements []ElementType, sortedSet SortedSet[ElementType]) {
 	descendingElements := sortedSet.Descending()
 	require.Equal(t, len(expectedElements), len(descendingElements))
@@ -61,6 +218,9 @@ func requireOrder[ElementType comparable](t *testing.T, expectedElements []Eleme
 		require.Equal(t, expectedElement, ascendingElements[len(expectedElements)-i-1])
 	}
 
+	require.Equal(t, descendingElements, slices.Collect(sortedSet.All()))
+	require.Equal(t, ascendingElements, slices.Collect(sortedSet.Backward()))
+
 	if len(expectedElements) > 0 {
 		require.Equal(t, expectedElements[0], sortedSet.HeaviestElement().Get())
 		require.Equal(t, expectedElements[len(expectedElements)-1], sortedSet.LightestElement().Get())
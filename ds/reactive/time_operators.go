@@ -0,0 +1,231 @@
+package reactive
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/lo"
+)
+
+// region public API ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Debounce derives a ReadableVariable from the given input that only takes over the value of the input once it did not
+// change for the given duration. The optional TimeSource defaults to the system clock.
+func Debounce[Type comparable](input ReadableVariable[Type], duration time.Duration, optTimeSource ...TimeSource) (output ReadableVariable[Type], unsubscribe func()) {
+	t := newTimedOperator[Type](optTimeSource...)
+
+	var latestValue Type
+	var generation uint64
+
+	t.subscribe(input, func(newValue Type) (Type, bool) {
+		latestValue = newValue
+		generation++
+
+		t.cancelTimers()
+		t.schedule(duration, func(scheduledGeneration uint64) func() (Type, bool) {
+			return func() (Type, bool) { return latestValue, scheduledGeneration == generation }
+		}(generation))
+
+		return newValue, false
+	})
+
+	return t.output, t.Unsubscribe
+}
+
+// Throttle derives a ReadableVariable from the given input that takes over the value of the input at most once per
+// interval. The first update is propagated immediately and the latest update within an interval is propagated once the
+// interval ends. The optional TimeSource defaults to the system clock.
+func Throttle[Type comparable](input ReadableVariable[Type], interval time.Duration, optTimeSource ...TimeSource) (output ReadableVariable[Type], unsubscribe func()) {
+	t := newTimedOperator[Type](optTimeSource...)
+
+	var pendingValue Type
+	var hasPendingValue, isThrottled bool
+
+	var endInterval func() (Type, bool)
+	endInterval = func() (Type, bool) {
+		if !hasPendingValue {
+			isThrottled = false
+
+			return pendingValue, false
+		}
+
+		hasPendingValue = false
+		t.schedule(interval, endInterval)
+
+		return pendingValue, true
+	}
+
+	t.subscribe(input, func(newValue Type) (Type, bool) {
+		if isThrottled {
+			pendingValue, hasPendingValue = newValue, true
+
+			return newValue, false
+		}
+
+		isThrottled = true
+		t.schedule(interval, endInterval)
+
+		return newValue, true
+	})
+
+	return t.output, t.Unsubscribe
+}
+
+// Delay derives a ReadableVariable from the given input that takes over every value of the input after the given
+// duration. The optional TimeSource defaults to the system clock.
+func Delay[Type comparable](input ReadableVariable[Type], duration time.Duration, optTimeSource ...TimeSource) (output ReadableVariable[Type], unsubscribe func()) {
+	t := newTimedOperator[Type](optTimeSource...)
+
+	delayedValues := make([]Type, 0)
+
+	t.subscribe(input, func(newValue Type) (Type, bool) {
+		delayedValues = append(delayedValues, newValue)
+
+		// the timers might fire out of order, so we always propagate the oldest value
+		t.schedule(duration, func() (Type, bool) {
+			oldestValue := delayedValues[0]
+			delayedValues = delayedValues[1:]
+
+			return oldestValue, true
+		})
+
+		return newValue, false
+	})
+
+	return t.output, t.Unsubscribe
+}
+
+// Sample derives a ReadableVariable from the given input that takes over the value of the input whenever the given
+// Clock ticks.
+func Sample[Type comparable](input ReadableVariable[Type], clock Clock) (output ReadableVariable[Type], unsubscribe func()) {
+	t := newTimedOperator[Type]()
+	t.output.Set(input.Get())
+	t.unsubscribe = clock.OnTick(func(time.Time) {
+		t.update(func() (Type, bool) { return input.Get(), true })
+	})
+
+	return t.output, t.Unsubscribe
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region timedOperator ////////////////////////////////////////////////////////////////////////////////////////////////
+
+// timedOperator contains the logic that is shared by the time-based operators.
+type timedOperator[Type comparable] struct {
+	// output is the variable that holds the derived value.
+	output *variable[Type]
+
+	// timeSource is used to schedule the delayed updates.
+	timeSource TimeSource
+
+	// scheduledTimers contains the cancel functions of the scheduled timers.
+	scheduledTimers map[uint64]func()
+
+	// nextTimerID is the identifier of the next scheduled timer.
+	nextTimerID uint64
+
+	// unsubscribe is the function that unsubscribes the operator from its input.
+	unsubscribe func()
+
+	// isStopped is true if the operator was unsubscribed.
+	isStopped bool
+
+	// mutex is used to synchronize access to the state of the operator.
+	mutex sync.Mutex
+
+	// updateOrderMutex is used to make sure that the output is updated in the order of the updates of the operator.
+	updateOrderMutex sync.Mutex
+}
+
+// newTimedOperator creates a new timedOperator.
+func newTimedOperator[Type comparable](optTimeSource ...TimeSource) *timedOperator[Type] {
+	return &timedOperator[Type]{
+		output:          newVariable[Type](),
+		timeSource:      lo.First(optTimeSource, SystemTimeSource()),
+		scheduledTimers: make(map[uint64]func()),
+		unsubscribe:     func() {},
+	}
+}
+
+// Unsubscribe unsubscribes the operator from its input and cancels all scheduled updates.
+func (t *timedOperator[Type]) Unsubscribe() {
+	t.mutex.Lock()
+	if t.isStopped {
+		t.mutex.Unlock()
+
+		return
+	}
+
+	t.isStopped = true
+	t.cancelTimers()
+	t.mutex.Unlock()
+
+	t.unsubscribe()
+}
+
+// subscribe subscribes the operator to the given input. The output takes over the current value of the input
+// immediately and all later updates are passed to the given handler (that is executed while holding the lock).
+func (t *timedOperator[Type]) subscribe(input ReadableVariable[Type], handler func(newValue Type) (outputValue Type, updateOutput bool)) {
+	isInitialized := false
+
+	t.unsubscribe = input.OnUpdate(func(_, newValue Type) {
+		t.update(func() (Type, bool) {
+			if !isInitialized {
+				isInitialized = true
+
+				return newValue, true
+			}
+
+			return handler(newValue)
+		})
+	}, true)
+}
+
+// update executes the given function while holding the lock and updates the output with the returned value (if
+// requested).
+func (t *timedOperator[Type]) update(updateFunc func() (outputValue Type, updateOutput bool)) {
+	t.updateOrderMutex.Lock()
+	defer t.updateOrderMutex.Unlock()
+
+	if outputValue, updateOutput := t.lockedUpdate(updateFunc); updateOutput {
+		t.output.Set(outputValue)
+	}
+}
+
+// lockedUpdate executes the given function while holding the lock (unless the operator was stopped).
+func (t *timedOperator[Type]) lockedUpdate(updateFunc func() (outputValue Type, updateOutput bool)) (outputValue Type, updateOutput bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.isStopped {
+		return outputValue, false
+	}
+
+	return updateFunc()
+}
+
+// schedule schedules the given update after the given duration (it must be called while holding the lock).
+func (t *timedOperator[Type]) schedule(duration time.Duration, updateFunc func() (outputValue Type, updateOutput bool)) {
+	timerID := t.nextTimerID
+	t.nextTimerID++
+
+	t.scheduledTimers[timerID] = t.timeSource.AfterFunc(duration, func() {
+		t.update(func() (Type, bool) {
+			delete(t.scheduledTimers, timerID)
+
+			return updateFunc()
+		})
+	})
+}
+
+// cancelTimers cancels all scheduled timers (it must be called while holding the lock).
+func (t *timedOperator[Type]) cancelTimers() {
+	for timerID, cancel := range t.scheduledTimers {
+		cancel()
+
+		delete(t.scheduledTimers, timerID)
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
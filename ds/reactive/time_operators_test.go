@@ -0,0 +1,261 @@
+package reactive
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounce(t *testing.T) {
+	timeSource := newManualTimeSource()
+
+	input := NewVariable[int]()
+	input.Set(1)
+
+	output, unsubscribe := Debounce[int](input, 10*time.Millisecond, timeSource)
+	require.Equal(t, 1, output.Get())
+
+	input.Set(2)
+	timeSource.Advance(5 * time.Millisecond)
+	input.Set(3)
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, 1, output.Get())
+
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, 3, output.Get())
+
+	input.Set(4)
+	unsubscribe()
+	timeSource.Advance(time.Second)
+	require.Equal(t, 3, output.Get())
+	require.Zero(t, timeSource.ScheduledCallbacks())
+}
+
+func TestThrottle(t *testing.T) {
+	timeSource := newManualTimeSource()
+
+	input := NewVariable[int]()
+	output, unsubscribe := Throttle[int](input, 10*time.Millisecond, timeSource)
+
+	updates := make([]int, 0)
+	output.OnUpdate(func(_, newValue int) {
+		updates = append(updates, newValue)
+	})
+
+	// the first update is propagated immediately
+	input.Set(1)
+	require.Equal(t, []int{1}, updates)
+
+	// the latest update within the interval is propagated at its end
+	input.Set(2)
+	input.Set(3)
+	timeSource.Advance(10 * time.Millisecond)
+	require.Equal(t, []int{1, 3}, updates)
+
+	// the trailing update starts a new interval
+	input.Set(4)
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, []int{1, 3}, updates)
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, []int{1, 3, 4}, updates)
+
+	// quiet intervals end the throttling
+	timeSource.Advance(10 * time.Millisecond)
+	input.Set(5)
+	require.Equal(t, []int{1, 3, 4, 5}, updates)
+
+	input.Set(6)
+	unsubscribe()
+	timeSource.Advance(time.Second)
+	require.Equal(t, []int{1, 3, 4, 5}, updates)
+	require.Zero(t, timeSource.ScheduledCallbacks())
+}
+
+func TestDelay(t *testing.T) {
+	timeSource := newManualTimeSource()
+
+	input := NewVariable[int]()
+	input.Set(1)
+
+	output, unsubscribe := Delay[int](input, 10*time.Millisecond, timeSource)
+	require.Equal(t, 1, output.Get())
+
+	updates := make([]int, 0)
+	output.OnUpdate(func(_, newValue int) {
+		updates = append(updates, newValue)
+	})
+
+	input.Set(2)
+	timeSource.Advance(5 * time.Millisecond)
+	input.Set(3)
+	require.Equal(t, []int{1}, updates)
+
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, []int{1, 2}, updates)
+
+	timeSource.Advance(5 * time.Millisecond)
+	require.Equal(t, []int{1, 2, 3}, updates)
+
+	input.Set(4)
+	unsubscribe()
+	timeSource.Advance(time.Second)
+	require.Equal(t, []int{1, 2, 3}, updates)
+	require.Zero(t, timeSource.ScheduledCallbacks())
+}
+
+func TestSample(t *testing.T) {
+	clock := newManualClock()
+
+	input := NewVariable[int]()
+	input.Set(1)
+
+	output, unsubscribe := Sample[int](input, clock)
+	require.Equal(t, 1, output.Get())
+
+	input.Set(2)
+	input.Set(3)
+	require.Equal(t, 1, output.Get())
+
+	clock.Tick()
+	require.Equal(t, 3, output.Get())
+
+	input.Set(4)
+	unsubscribe()
+	clock.Tick()
+	require.Equal(t, 3, output.Get())
+}
+
+func TestDebounceSystemTimeSource(t *testing.T) {
+	input := NewVariable[int]()
+
+	output, unsubscribe := Debounce[int](input, 10*time.Millisecond)
+	defer unsubscribe()
+
+	input.Set(1)
+	input.Set(2)
+
+	require.Eventually(t, func() bool {
+		return output.Get() == 2
+	}, time.Second, time.Millisecond)
+}
+
+// manualTimeSource is a TimeSource that only advances when instructed to.
+type manualTimeSource struct {
+	now            time.Time
+	callbacks      map[uint64]*manualCallback
+	nextCallbackID uint64
+	mutex          sync.Mutex
+}
+
+// manualCallback is a callback that was scheduled on a manualTimeSource.
+type manualCallback struct {
+	id       uint64
+	dueTime  time.Time
+	callback func()
+}
+
+func newManualTimeSource() *manualTimeSource {
+	return &manualTimeSource{
+		now:       time.Unix(0, 0),
+		callbacks: make(map[uint64]*manualCallback),
+	}
+}
+
+func (m *manualTimeSource) Now() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.now
+}
+
+func (m *manualTimeSource) AfterFunc(duration time.Duration, callback func()) (cancel func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	scheduledCallback := &manualCallback{id: m.nextCallbackID, dueTime: m.now.Add(duration), callback: callback}
+	m.callbacks[scheduledCallback.id] = scheduledCallback
+	m.nextCallbackID++
+
+	return func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		delete(m.callbacks, scheduledCallback.id)
+	}
+}
+
+// Advance moves the time forward and executes the callbacks that became due (in the order of their due time).
+func (m *manualTimeSource) Advance(duration time.Duration) {
+	m.mutex.Lock()
+	targetTime := m.now.Add(duration)
+	m.mutex.Unlock()
+
+	for nextCallback := m.popDueCallback(targetTime); nextCallback != nil; nextCallback = m.popDueCallback(targetTime) {
+		nextCallback.callback()
+	}
+
+	m.mutex.Lock()
+	m.now = targetTime
+	m.mutex.Unlock()
+}
+
+// ScheduledCallbacks returns the number of callbacks that are still scheduled.
+func (m *manualTimeSource) ScheduledCallbacks() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.callbacks)
+}
+
+func (m *manualTimeSource) popDueCallback(targetTime time.Time) *manualCallback {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	dueCallbacks := make([]*manualCallback, 0)
+	for _, scheduledCallback := range m.callbacks {
+		if !scheduledCallback.dueTime.After(targetTime) {
+			dueCallbacks = append(dueCallbacks, scheduledCallback)
+		}
+	}
+
+	if len(dueCallbacks) == 0 {
+		return nil
+	}
+
+	sort.Slice(dueCallbacks, func(i, j int) bool {
+		if !dueCallbacks[i].dueTime.Equal(dueCallbacks[j].dueTime) {
+			return dueCallbacks[i].dueTime.Before(dueCallbacks[j].dueTime)
+		}
+
+		return dueCallbacks[i].id < dueCallbacks[j].id
+	})
+
+	delete(m.callbacks, dueCallbacks[0].id)
+	m.now = dueCallbacks[0].dueTime
+
+	return dueCallbacks[0]
+}
+
+// manualClock is a Clock that only ticks when instructed to.
+type manualClock struct {
+	Variable[time.Time]
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{Variable: NewVariable[time.Time]()}
+}
+
+func (m *manualClock) OnTick(handler func(now time.Time)) (unsubscribe func()) {
+	return m.OnUpdate(func(_, now time.Time) {
+		handler(now)
+	})
+}
+
+func (m *manualClock) Tick() {
+	m.Set(m.Get().Add(time.Second))
+}
+
+func (m *manualClock) Shutdown() {}
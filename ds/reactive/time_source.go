@@ -0,0 +1,47 @@
+package reactive
+
+import (
+	"time"
+)
+
+// region TimeSource ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TimeSource is the source of time that is used to schedule the time-based operators of reactive variables (it can be
+// injected to control the time in tests or simulations).
+type TimeSource interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc schedules the given callback to be executed after the given duration and returns a function that
+	// cancels the execution.
+	AfterFunc(duration time.Duration, callback func()) (cancel func())
+}
+
+// SystemTimeSource returns the TimeSource that is based on the system clock.
+func SystemTimeSource() TimeSource {
+	return systemTimeSource{}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// region systemTimeSource /////////////////////////////////////////////////////////////////////////////////////////////
+
+// systemTimeSource is the TimeSource that is based on the system clock.
+type systemTimeSource struct{}
+
+// Now returns the current time.
+func (systemTimeSource) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc schedules the given callback to be executed after the given duration and returns a function that cancels
+// the execution.
+func (systemTimeSource) AfterFunc(duration time.Duration, callback func()) (cancel func()) {
+	timer := time.AfterFunc(duration, callback)
+
+	return func() {
+		timer.Stop()
+	}
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
 )
 
 // region Variable /////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -48,9 +49,19 @@ type ReadableVariable[Type comparable] interface {
 	// is called).
 	WithNonEmptyValue(setup func(value Type) (teardown func())) (teardown func())
 
+	// WithValueDispatched works like WithValue, but executes the setup and teardown functions in the given WorkerPool
+	// (one after the other in the order they were triggered). Setups that did not start before the returned teardown
+	// function is called are skipped.
+	WithValueDispatched(workerPool *workerpool.WorkerPool, setup func(value Type) (teardown func()), condition ...func(Type) bool) (teardown func())
+
 	// OnUpdate registers the given callback that is triggered when the value changes.
 	OnUpdate(consumer func(oldValue, newValue Type), triggerWithInitialZeroValue ...bool) (unsubscribe func())
 
+	// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the
+	// other in the order they were triggered). Updates that did not start before the subscription is unsubscribed are
+	// skipped.
+	OnUpdateDispatched(workerPool *workerpool.WorkerPool, consumer func(oldValue, newValue Type), triggerWithInitialZeroValue ...bool) (unsubscribe func())
+
 	// OnUpdateOnce registers the given callback for the next update and then automatically unsubscribes it. It is
 	// possible to provide an optional condition that has to be satisfied for the callback to be triggered.
 	OnUpdateOnce(callback func(oldValue, newValue Type), optCondition ...func(oldValue Type, newValue Type) bool) (unsubscribe func())
@@ -115,9 +126,13 @@ type DerivedVariable[Type comparable] interface {
 // NewDerivedVariable creates a DerivedVariable that transforms an input value into a different one.
 func NewDerivedVariable[Type, InputType1 comparable, InputValueType1 ReadableVariable[InputType1]](compute func(currentValue Type, inputValue1 InputType1) Type, input1 InputValueType1, initialValue ...Type) DerivedVariable[Type] {
 	return newDerivedVariable[Type](func(d DerivedVariable[Type]) func() {
-		return input1.OnUpdate(func(_, input1 InputType1) {
-			d.Compute(func(currentValue Type) Type { return compute(currentValue, input1) })
-		}, true)
+		return lo.Batch(
+			dependsOn(d, "NewDerivedVariable", input1),
+
+			input1.OnUpdate(func(_, input1 InputType1) {
+				d.Compute(func(currentValue Type) Type { return compute(currentValue, input1) })
+			}, true),
+		)
 	}, initialValue...)
 }
 
@@ -125,6 +140,8 @@ func NewDerivedVariable[Type, InputType1 comparable, InputValueType1 ReadableVar
 func NewDerivedVariable2[Type, InputType1, InputType2 comparable, InputValueType1 ReadableVariable[InputType1], InputValueType2 ReadableVariable[InputType2]](compute func(currentValue Type, inputValue1 InputType1, inputValue2 InputType2) Type, input1 InputValueType1, input2 InputValueType2, initialValue ...Type) DerivedVariable[Type] {
 	return newDerivedVariable[Type](func(d DerivedVariable[Type]) func() {
 		return lo.Batch(
+			dependsOn(d, "NewDerivedVariable2", input1, input2),
+
 			input1.OnUpdate(func(_, input1 InputType1) {
 				d.Compute(func(currentValue Type) Type { return compute(currentValue, input1, input2.Get()) })
 			}, true),
@@ -140,6 +157,8 @@ func NewDerivedVariable2[Type, InputType1, InputType2 comparable, InputValueType
 func NewDerivedVariable3[Type, InputType1, InputType2, InputType3 comparable, InputValueType1 ReadableVariable[InputType1], InputValueType2 ReadableVariable[InputType2], InputValueType3 ReadableVariable[InputType3]](compute func(currentValue Type, inputValue1 InputType1, inputValue2 InputType2, inputValue3 InputType3) Type, input1 InputValueType1, input2 InputValueType2, input3 InputValueType3, initialValue ...Type) DerivedVariable[Type] {
 	return newDerivedVariable[Type](func(d DerivedVariable[Type]) func() {
 		return lo.Batch(
+			dependsOn(d, "NewDerivedVariable3", input1, input2, input3),
+
 			input1.OnUpdate(func(_, input1 InputType1) {
 				d.Compute(func(currentValue Type) Type { return compute(currentValue, input1, input2.Get(), input3.Get()) })
 			}, true),
@@ -159,6 +178,8 @@ func NewDerivedVariable3[Type, InputType1, InputType2, InputType3 comparable, In
 func NewDerivedVariable4[Type, InputType1, InputType2, InputType3, InputType4 comparable, InputValueType1 ReadableVariable[InputType1], InputValueType2 ReadableVariable[InputType2], InputValueType3 ReadableVariable[InputType3], InputValueType4 ReadableVariable[InputType4]](compute func(currentValue Type, inputValue1 InputType1, inputValue2 InputType2, inputValue3 InputType3, inputValue4 InputType4) Type, input1 InputValueType1, input2 InputValueType2, input3 InputValueType3, input4 InputValueType4, initialValue ...Type) DerivedVariable[Type] {
 	return newDerivedVariable[Type](func(d DerivedVariable[Type]) func() {
 		return lo.Batch(
+			dependsOn(d, "NewDerivedVariable4", input1, input2, input3, input4),
+
 			input1.OnUpdate(func(_, input1 InputType1) {
 				d.Compute(func(currentValue Type) Type {
 					return compute(currentValue, input1, input2.Get(), input3.Get(), input4.Get())
@@ -1,12 +1,14 @@
 package reactive
 
 import (
+	"fmt"
 	"log/slog"
 	"sync"
 	"unsafe"
 
 	"github.com/iotaledger/hive.go/ds"
 	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/workerpool"
 )
 
 // region variable /////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -18,6 +20,9 @@ type variable[Type comparable] struct {
 	// transformationFunc is the function that is used to transform the value before it is stored.
 	transformationFunc func(currentValue Type, newValue Type) Type
 
+	// batchedUpdate is the update that was collected by the open Transaction (nil if there is none).
+	batchedUpdate *batchedVariableUpdate[Type]
+
 	// updateOrderMutex is used to make sure that write operations are executed sequentially (all subscribers are
 	// notified before the next write operation is executed).
 	updateOrderMutex sync.Mutex
@@ -50,6 +55,9 @@ func (v *variable[Type]) Compute(computeFunc func(currentValue Type) Type) (prev
 	defer v.updateOrderMutex.Unlock()
 
 	newValue, previousValue, updateID, registeredCallbacks := v.updateValue(computeFunc)
+	if updateID == 0 || v.collectBatchedUpdate(previousValue, updateID) {
+		return previousValue
+	}
 
 	for _, registeredCallback := range registeredCallbacks {
 		if registeredCallback.LockExecution(updateID) {
@@ -80,9 +88,7 @@ func (v *variable[Type]) DefaultTo(defaultValue Type) (newValue Type, updated bo
 
 // InheritFrom inherits the value from the given ReadableVariable.
 func (v *variable[Type]) InheritFrom(other ReadableVariable[Type]) (unsubscribe func()) {
-	return other.OnUpdate(func(_, newValue Type) {
-		v.Set(newValue)
-	}, true)
+	return v.inheritFrom(other, "InheritFrom")
 }
 
 // DeriveValueFrom is a utility function that allows to derive a value from a newly created DerivedVariable.
@@ -90,7 +96,7 @@ func (v *variable[Type]) InheritFrom(other ReadableVariable[Type]) (unsubscribe
 func (v *variable[Type]) DeriveValueFrom(source DerivedVariable[Type]) (teardown func()) {
 	// no need to unsubscribe variable from source (it will no longer change and get garbage collected after
 	// unsubscribing from its inputs)
-	_ = v.InheritFrom(source)
+	_ = v.inheritFrom(source, "DeriveValueFrom")
 
 	return source.Unsubscribe
 }
@@ -104,6 +110,66 @@ func (v *variable[Type]) ToggleValue(value Type) (reset func()) {
 	}
 }
 
+// inheritFrom inherits the value from the given ReadableVariable and records the edge with the given kind in the
+// dependency graph.
+func (v *variable[Type]) inheritFrom(other ReadableVariable[Type], kind string) (unsubscribe func()) {
+	return lo.Batch(
+		dependsOn(v, kind, other),
+
+		other.OnUpdate(func(_, newValue Type) {
+			v.Set(newValue)
+		}, true),
+	)
+}
+
+// collectBatchedUpdate collects the given update in the open Transaction of the element and returns false if it did not join one.
+func (v *variable[Type]) collectBatchedUpdate(previousValue Type, updateID uniqueID) bool {
+	if v.batchedUpdate != nil {
+		v.batchedUpdate.updateID = updateID
+
+		return true
+	}
+
+	if !collectInTransaction(v) {
+		return false
+	}
+
+	v.batchedUpdate = &batchedVariableUpdate[Type]{
+		previousValue: previousValue,
+		updateID:      updateID,
+	}
+
+	return true
+}
+
+// triggerBatchedUpdate notifies the subscribers about the update that was collected by a Batch.
+func (v *variable[Type]) triggerBatchedUpdate() {
+	v.updateOrderMutex.Lock()
+	defer v.updateOrderMutex.Unlock()
+
+	batchedUpdate := v.batchedUpdate
+	if batchedUpdate == nil {
+		return
+	}
+	v.batchedUpdate = nil
+
+	v.valueMutex.RLock()
+	newValue, registeredCallbacks := v.value, v.registeredCallbacks.Values()
+	v.valueMutex.RUnlock()
+
+	// updates that cancel each other out are not propagated
+	if newValue == batchedUpdate.previousValue {
+		return
+	}
+
+	for _, registeredCallback := range registeredCallbacks {
+		if registeredCallback.LockExecution(batchedUpdate.updateID) {
+			registeredCallback.Invoke(batchedUpdate.previousValue, newValue)
+			registeredCallback.UnlockExecution()
+		}
+	}
+}
+
 // updateValue atomically prepares the trigger by setting the new value and returning the new value, the previous value,
 // the triggerID and the callbacks to trigger.
 func (v *variable[Type]) updateValue(newValueGenerator func(Type) Type) (newValue, previousValue Type, triggerID uniqueID, callbacksToTrigger []*callback[func(prevValue, newValue Type)]) {
@@ -121,6 +187,19 @@ func (v *variable[Type]) updateValue(newValueGenerator func(Type) Type) (newValu
 
 // endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// region batchedVariableUpdate ////////////////////////////////////////////////////////////////////////////////////////
+
+// batchedVariableUpdate is an update of a variable that was collected by a Batch.
+type batchedVariableUpdate[Type comparable] struct {
+	// previousValue is the value of the variable before the Batch.
+	previousValue Type
+
+	// updateID is the identifier of the latest update of the variable.
+	updateID uniqueID
+}
+
+// endregion ///////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // region readableVariable /////////////////////////////////////////////////////////////////////////////////////////////
 
 // readableVariable is the default implementation of the ReadableVariable interface.
@@ -136,6 +215,9 @@ type readableVariable[Type comparable] struct {
 
 	// valueMutex is used to ensure that access to the value is synchronized.
 	valueMutex sync.RWMutex
+
+	// elementRank tracks the position of the variable in the dependency graph.
+	elementRank
 }
 
 // newReadableVariable creates a new readableVariable instance with an optional initial value.
@@ -180,6 +262,25 @@ func (r *readableVariable[Type]) WithNonEmptyValue(setup func(value Type) (teard
 	return r.WithValue(setup, func(t Type) bool { return t != *new(Type) })
 }
 
+// WithValueDispatched works like WithValue, but executes the setup and teardown functions in the given WorkerPool (one
+// after the other in the order they were triggered). Setups that did not start before the returned teardown function
+// is called are skipped.
+func (r *readableVariable[Type]) WithValueDispatched(workerPool *workerpool.WorkerPool, setup func(value Type) (teardown func()), condition ...func(Type) bool) (teardown func()) {
+	d := newDispatcher(workerPool)
+
+	return d.subscription(r.WithValue(dispatchSetup(d, setup), condition...))
+}
+
+// OnUpdateDispatched works like OnUpdate, but executes the callback in the given WorkerPool (one update after the other
+// in the order they were triggered). Updates that did not start before the subscription is unsubscribed are skipped.
+func (r *readableVariable[Type]) OnUpdateDispatched(workerPool *workerpool.WorkerPool, callback func(prevValue, newValue Type), triggerWithInitialZeroValue ...bool) (unsubscribe func()) {
+	d := newDispatcher(workerPool)
+
+	return d.subscription(r.OnUpdate(func(prevValue, newValue Type) {
+		d.dispatchCallback(func() { callback(prevValue, newValue) })
+	}, triggerWithInitialZeroValue...))
+}
+
 // OnUpdate registers the given callback that is triggered when the value changes.
 func (r *readableVariable[Type]) OnUpdate(callback func(prevValue, newValue Type), triggerWithInitialZeroValue ...bool) (unsubscribe func()) {
 	r.valueMutex.Lock()
@@ -290,6 +391,14 @@ func (r *readableVariable[Type]) LogUpdates(logger VariableLogReceiver, logLevel
 	})
 }
 
+// describeGraphNode returns the current value, the number of updates and the number of subscribers of the variable.
+func (r *readableVariable[Type]) describeGraphNode() (value string, updateCount uint64, subscriberCount int) {
+	r.valueMutex.RLock()
+	defer r.valueMutex.RUnlock()
+
+	return fmt.Sprint(r.value), uint64(r.uniqueUpdateID), r.registeredCallbacks.Len()
+}
+
 // isNil returns true if the given value is nil.
 func isNil(value any) bool {
 	return value == nil || (*[2]uintptr)(unsafe.Pointer(&value))[1] == 0
@@ -301,8 +410,8 @@ func isNil(value any) bool {
 
 // derivedVariable implements the DerivedVariable interface.
 type derivedVariable[ValueType comparable] struct {
-	// Variable is the Variable that holds the derived value.
-	Variable[ValueType]
+	// variable is the variable that holds the derived value.
+	*variable[ValueType]
 
 	// unsubscribe is the function that is used to unsubscribe the derivedVariable from the inputs.
 	unsubscribe func()
@@ -314,8 +423,9 @@ type derivedVariable[ValueType comparable] struct {
 // newDerivedVariable creates a new derivedVariable instance.
 func newDerivedVariable[ValueType comparable](subscribe func(DerivedVariable[ValueType]) func(), initialValue ...ValueType) *derivedVariable[ValueType] {
 	d := &derivedVariable[ValueType]{
-		Variable: NewVariable[ValueType]().Init(lo.First(initialValue)),
+		variable: newVariable[ValueType](),
 	}
+	d.Set(lo.First(initialValue))
 
 	d.unsubscribe = subscribe(d)
 
@@ -1,6 +1,8 @@
 package ds
 
 import (
+	"iter"
+
 	"github.com/iotaledger/hive.go/ds/walker"
 	"github.com/iotaledger/hive.go/serializer/v2/serix"
 )
@@ -39,6 +41,18 @@ type ReadableSet[ElementType comparable] interface {
 	// Range iterates through all elements of the set.
 	Range(callback func(element ElementType))
 
+	// All returns a sequence of all elements of the set in insertion order. Every iteration works on a snapshot of the
+	// elements that is taken when the iteration starts, so the set can be modified while iterating.
+	All() iter.Seq[ElementType]
+
+	// Backward returns a sequence of all elements of the set in reverse insertion order (with the same snapshot
+	// semantics as All).
+	Backward() iter.Seq[ElementType]
+
+	// Values returns a sequence of all elements of the set in insertion order (the elements are the values of a set,
+	// so it is equivalent to All).
+	Values() iter.Seq[ElementType]
+
 	// Intersect returns the intersection of the set and the given set.
 	Intersect(other ReadableSet[ElementType]) Set[ElementType]
 
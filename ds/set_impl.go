@@ -2,6 +2,7 @@ package ds
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
 	"strings"
 	"sync"
@@ -204,6 +205,36 @@ func (r *readableSet[T]) Range(callback func(element T)) {
 	}
 }
 
+// All returns a sequence of all elements of the set in insertion order (iterating over a snapshot of the elements).
+func (r *readableSet[T]) All() iter.Seq[T] {
+	if r == nil {
+		return func(func(T) bool) {}
+	}
+
+	return r.OrderedMap.Keys()
+}
+
+// Values returns a sequence of all elements of the set in insertion order (iterating over a snapshot of the elements).
+func (r *readableSet[T]) Values() iter.Seq[T] {
+	return r.All()
+}
+
+// Backward returns a sequence of all elements of the set in reverse insertion order (iterating over a snapshot of the
+// elements).
+func (r *readableSet[T]) Backward() iter.Seq[T] {
+	if r == nil {
+		return func(func(T) bool) {}
+	}
+
+	return func(yield func(T) bool) {
+		for element := range r.OrderedMap.Backward() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
 // Intersect returns the intersection of the set and the given set.
 func (r *readableSet[T]) Intersect(other ReadableSet[T]) (intersection Set[T]) {
 	return r.Filter(other.Has)
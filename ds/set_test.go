@@ -2,6 +2,7 @@ package ds_test
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -187,6 +188,22 @@ func TestSet_Encoding(t *testing.T) {
 	require.Equal(t, testSet, decoded)
 }
 
+func TestSet_Iterators(t *testing.T) {
+	set := ds.NewSet("a", "b", "c")
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(set.All()))
+	require.Equal(t, []string{"c", "b", "a"}, slices.Collect(set.Backward()))
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(set.Values()))
+
+	// the set can be modified during the iteration (without affecting the running iteration)
+	for element := range set.All() {
+		set.Delete(element)
+		set.Add(element + element)
+	}
+	require.Equal(t, []string{"aa", "bb", "cc"}, slices.Collect(set.All()))
+
+	require.Empty(t, slices.Collect(ds.NewSet[string]().ReadOnly().All()))
+}
+
 func initSet(count int, start int) ds.Set[string] {
 	set := ds.NewSet[string]()
 	end := start + count
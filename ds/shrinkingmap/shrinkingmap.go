@@ -1,6 +1,7 @@
 package shrinkingmap
 
 import (
+	"iter"
 	"sync"
 
 	"github.com/iotaledger/hive.go/lo"
@@ -177,6 +178,39 @@ func (s *ShrinkingMap[K, V]) ForEach(callback func(K, V) bool) {
 	}
 }
 
+// All returns a sequence of all entries (in no particular order). Every iteration works on a snapshot of the entries
+// that is taken when the iteration starts, so the map can be modified while iterating (without affecting the running
+// iteration).
+func (s *ShrinkingMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.ForEach(yield)
+	}
+}
+
+// KeysSeq returns a sequence of all keys (with the same snapshot semantics as All). It is named KeysSeq because Keys
+// returns a slice.
+func (s *ShrinkingMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range s.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns a sequence of all values (with the same snapshot semantics as All). It is named ValuesSeq because
+// Values returns a slice.
+func (s *ShrinkingMap[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range s.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
 // Pop removes the first element from the map and returns it.
 func (s *ShrinkingMap[K, V]) Pop() (key K, value V, exists bool) {
 	s.mutex.Lock()
@@ -1,6 +1,8 @@
 package shrinkingmap
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -122,3 +124,27 @@ func TestShrinkingMap_Empty(t *testing.T) {
 	require.True(t, shrink.IsEmpty())
 	require.True(t, shrink.deletedKeys > 0)
 }
+
+func TestShrinkingMap_All(t *testing.T) {
+	shrink := New[int, int]()
+	for i := 0; i < 10; i++ {
+		shrink.Set(i, i*i)
+	}
+
+	expected := make(map[int]int)
+	for i := 0; i < 10; i++ {
+		expected[i] = i * i
+	}
+	require.Equal(t, expected, maps.Collect(shrink.All()))
+	require.ElementsMatch(t, slices.Collect(maps.Keys(expected)), slices.Collect(shrink.KeysSeq()))
+	require.ElementsMatch(t, slices.Collect(maps.Values(expected)), slices.Collect(shrink.ValuesSeq()))
+
+	// the map can be modified during the iteration (without affecting the running iteration)
+	iterations := 0
+	for key := range shrink.All() {
+		shrink.Delete(key)
+		iterations++
+	}
+	require.Equal(t, 10, iterations)
+	require.True(t, shrink.IsEmpty())
+}
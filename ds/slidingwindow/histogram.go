@@ -0,0 +1,134 @@
+package slidingwindow
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// subBucketBits is the number of bits that are used to distinguish values within a power of two (the resulting
+	// relative error of the recorded values is at most 2^-subBucketBits).
+	subBucketBits = 7
+
+	// subBucketHalfCount is the number of sub-buckets per power of two (above the linear range).
+	subBucketHalfCount = 1 << (subBucketBits - 1)
+)
+
+// Histogram is a mergeable histogram of non-negative integer values with a bounded relative error (similar to an HDR
+// histogram). Values below 2^7 are recorded exactly, larger values are grouped into logarithmic buckets that are split
+// into linear sub-buckets. Only the non-empty cells are stored, so the memory usage is bounded by the range of the
+// recorded values.
+//
+// A Histogram is not safe for concurrent use.
+type Histogram struct {
+	// cells contains the number of recorded values per cell.
+	cells map[uint16]uint64
+
+	// count is the total number of recorded values.
+	count uint64
+}
+
+// NewHistogram creates a new (empty) Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{
+		cells: make(map[uint16]uint64),
+	}
+}
+
+// Record records the given value (negative values are recorded as 0).
+func (h *Histogram) Record(value int64) {
+	h.RecordN(value, 1)
+}
+
+// RecordN records the given value n times (negative values are recorded as 0).
+func (h *Histogram) RecordN(value int64, n uint64) {
+	if n == 0 {
+		return
+	}
+
+	h.cells[cellIndex(uint64(max(value, 0)))] += n
+	h.count += n
+}
+
+// Merge adds all values of the other Histogram to this Histogram.
+func (h *Histogram) Merge(other *Histogram) {
+	for cell, count := range other.cells {
+		h.cells[cell] += count
+	}
+
+	h.count += other.count
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Quantile returns the (approximated) value below which the given fraction (0 <= q <= 1) of the recorded values falls.
+func (h *Histogram) Quantile(q float64) int64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	cells := make([]uint16, 0, len(h.cells))
+	for cell := range h.cells {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+
+	// the rank is the (1-based) position of the requested value in the sorted list of all recorded values
+	rank := uint64(math.Ceil(max(0, min(1, q)) * float64(h.count)))
+	rank = max(rank, 1)
+
+	seenCount := uint64(0)
+	for _, cell := range cells {
+		if seenCount += h.cells[cell]; seenCount >= rank {
+			return int64(cellValue(cell))
+		}
+	}
+
+	return int64(cellValue(cells[len(cells)-1]))
+}
+
+// Reset removes all recorded values.
+func (h *Histogram) Reset() {
+	clear(h.cells)
+
+	h.count = 0
+}
+
+// Clone returns a copy of the Histogram.
+func (h *Histogram) Clone() *Histogram {
+	cloned := NewHistogram()
+	cloned.Merge(h)
+
+	return cloned
+}
+
+// cellIndex returns the index of the cell that the given value is recorded in.
+func cellIndex(value uint64) uint16 {
+	// values in the linear range are recorded exactly
+	if value < 2*subBucketHalfCount {
+		return uint16(value)
+	}
+
+	// larger values are grouped by their highest bit (exponent) and the following subBucketBits-1 bits (sub-bucket)
+	exponent := bits.Len64(value) - subBucketBits
+	subBucket := value >> exponent
+
+	return uint16((exponent+1)*subBucketHalfCount + int(subBucket) - subBucketHalfCount)
+}
+
+// cellValue returns the value that represents the given cell (the middle of its value range).
+func cellValue(cell uint16) uint64 {
+	if cell < 2*subBucketHalfCount {
+		return uint64(cell)
+	}
+
+	exponent := int(cell)/subBucketHalfCount - 1
+	subBucket := uint64(int(cell) - exponent*subBucketHalfCount)
+	lowerBound, upperBound := subBucket<<exponent, ((subBucket+1)<<exponent)-1
+
+	return lowerBound + (upperBound-lowerBound)/2
+}
@@ -0,0 +1,79 @@
+package slidingwindow_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/slidingwindow"
+)
+
+func TestHistogram(t *testing.T) {
+	h := slidingwindow.NewHistogram()
+	require.Zero(t, h.Quantile(0.5))
+
+	// small values are recorded exactly
+	for i := int64(1); i <= 100; i++ {
+		h.Record(i)
+	}
+	require.EqualValues(t, 100, h.Count())
+	require.EqualValues(t, 1, h.Quantile(0))
+	require.EqualValues(t, 50, h.Quantile(0.5))
+	require.EqualValues(t, 99, h.Quantile(0.99))
+	require.EqualValues(t, 100, h.Quantile(1))
+
+	h.Record(-5)
+	require.EqualValues(t, 0, h.Quantile(0))
+
+	h.Reset()
+	require.Zero(t, h.Count())
+}
+
+func TestHistogramAccuracy(t *testing.T) {
+	h := slidingwindow.NewHistogram()
+
+	values := make([]int64, 100000)
+	for i := range values {
+		// log-normally distributed latencies around 1ms
+		values[i] = int64(math.Exp(rand.NormFloat64()) * 1e6)
+		h.Record(values[i])
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	for _, q := range []float64{0.5, 0.95, 0.99, 0.999} {
+		expected := float64(values[int(math.Ceil(q*float64(len(values))))-1])
+		require.InEpsilon(t, expected, float64(h.Quantile(q)), 0.01, "quantile %f", q)
+	}
+
+	// extreme values do not overflow
+	h.Record(math.MaxInt64)
+	require.InEpsilon(t, float64(math.MaxInt64), float64(h.Quantile(1)), 0.01)
+}
+
+func TestHistogramMerge(t *testing.T) {
+	h1, h2, combined := slidingwindow.NewHistogram(), slidingwindow.NewHistogram(), slidingwindow.NewHistogram()
+	for i := 0; i < 1000; i++ {
+		value := rand.Int63n(1e9)
+
+		if i%2 == 0 {
+			h1.Record(value)
+		} else {
+			h2.Record(value)
+		}
+		combined.Record(value)
+	}
+
+	merged := h1.Clone()
+	merged.Merge(h2)
+	require.Equal(t, combined.Count(), merged.Count())
+
+	for _, q := range []float64{0, 0.25, 0.5, 0.75, 0.99, 1} {
+		require.Equal(t, combined.Quantile(q), merged.Quantile(q))
+	}
+
+	// the clone is independent of the original
+	require.EqualValues(t, 500, h1.Count())
+}
@@ -0,0 +1,140 @@
+package slidingwindow
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// Resolution defines the granularity of a sliding window: it consists of BucketCount buckets that each aggregate the
+// values that were recorded during BucketDuration.
+type Resolution struct {
+	// BucketDuration is the time span that is aggregated by a single bucket.
+	BucketDuration time.Duration
+
+	// BucketCount is the number of buckets of the window.
+	BucketCount int
+}
+
+// Span returns the total time span that is covered by the Resolution.
+func (r Resolution) Span() time.Duration {
+	return r.BucketDuration * time.Duration(r.BucketCount)
+}
+
+// SlidingWindow is a concurrent-safe recorder of values that keeps statistics (count, sum, min, max, rate and
+// percentiles) over sliding windows of multiple resolutions. Every resolution uses a fixed number of buckets, so the
+// memory usage stays bounded no matter how many values are recorded.
+type SlidingWindow struct {
+	// windows contains the windows of the configured resolutions ordered from the finest to the coarsest one.
+	windows []*window
+
+	// mutex is used to synchronize access to the windows.
+	mutex sync.Mutex
+
+	// optsResolutions contains the resolutions of the windows.
+	optsResolutions []Resolution
+
+	// optsClock is the function that returns the current time.
+	optsClock func() time.Time
+}
+
+// New creates a new SlidingWindow (with a resolution of 1s for the last minute and a resolution of 1m for the last hour
+// by default).
+func New(opts ...options.Option[SlidingWindow]) *SlidingWindow {
+	return options.Apply(&SlidingWindow{
+		optsResolutions: []Resolution{
+			{BucketDuration: time.Second, BucketCount: 60},
+			{BucketDuration: time.Minute, BucketCount: 60},
+		},
+		optsClock: time.Now,
+	}, opts, func(s *SlidingWindow) {
+		resolutions := make([]Resolution, 0, len(s.optsResolutions))
+		for _, resolution := range s.optsResolutions {
+			if resolution.BucketDuration > 0 && resolution.BucketCount > 0 {
+				resolutions = append(resolutions, resolution)
+			}
+		}
+		sort.SliceStable(resolutions, func(i, j int) bool { return resolutions[i].Span() < resolutions[j].Span() })
+
+		s.optsResolutions = resolutions
+		s.windows = make([]*window, len(resolutions))
+		for i, resolution := range resolutions {
+			s.windows[i] = newWindow(resolution)
+		}
+	})
+}
+
+// Record records the given value in all windows.
+func (s *SlidingWindow) Record(value int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.optsClock()
+	for _, w := range s.windows {
+		w.record(now, value)
+	}
+}
+
+// RecordDuration records the given duration (in nanoseconds) in all windows.
+func (s *SlidingWindow) RecordDuration(duration time.Duration) {
+	s.Record(int64(duration))
+}
+
+// RecordSince records the time that elapsed since the given start time (e.g. the latency of an operation).
+func (s *SlidingWindow) RecordSince(start time.Time) {
+	s.RecordDuration(s.optsClock().Sub(start))
+}
+
+// Snapshot returns the statistics of the values that were recorded during the given duration. It uses the finest
+// resolution that covers the duration (or the coarsest resolution if none does), so the duration is rounded up to
+// the bucket duration of that resolution.
+func (s *SlidingWindow) Snapshot(duration time.Duration) *Snapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.windows) == 0 {
+		return newSnapshot(0)
+	}
+
+	selectedWindow := s.windows[len(s.windows)-1]
+	for _, w := range s.windows {
+		if w.resolution.Span() >= duration {
+			selectedWindow = w
+
+			break
+		}
+	}
+
+	return selectedWindow.snapshot(s.optsClock(), duration)
+}
+
+// Resolutions returns the resolutions of the windows ordered from the finest to the coarsest one.
+func (s *SlidingWindow) Resolutions() []Resolution {
+	return append(make([]Resolution, 0, len(s.optsResolutions)), s.optsResolutions...)
+}
+
+// Reset removes all recorded values.
+func (s *SlidingWindow) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, w := range s.windows {
+		s.windows[i] = newWindow(w.resolution)
+	}
+}
+
+// WithResolutions sets the resolutions of the windows that are maintained by the SlidingWindow.
+func WithResolutions(resolutions ...Resolution) options.Option[SlidingWindow] {
+	return func(s *SlidingWindow) {
+		s.optsResolutions = resolutions
+	}
+}
+
+// WithClock sets the function that returns the current time (defaults to time.Now).
+func WithClock(clock func() time.Time) options.Option[SlidingWindow] {
+	return func(s *SlidingWindow) {
+		s.optsClock = clock
+	}
+}
@@ -0,0 +1,161 @@
+package slidingwindow_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/slidingwindow"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	clock := newTestClock()
+	s := slidingwindow.New(slidingwindow.WithClock(clock.Now), slidingwindow.WithResolutions(
+		slidingwindow.Resolution{BucketDuration: time.Second, BucketCount: 10},
+	))
+
+	for i := int64(1); i <= 100; i++ {
+		s.Record(i)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+
+	snapshot := s.Snapshot(10 * time.Second)
+	require.EqualValues(t, 100, snapshot.Count())
+	require.EqualValues(t, 5050, snapshot.Sum())
+	require.EqualValues(t, 1, snapshot.Min())
+	require.EqualValues(t, 100, snapshot.Max())
+	require.InDelta(t, 50.5, snapshot.Mean(), 0.001)
+	require.EqualValues(t, 50, snapshot.P50())
+	require.EqualValues(t, 95, snapshot.P95())
+	require.EqualValues(t, 99, snapshot.P99())
+
+	// the current bucket is only half elapsed
+	require.Equal(t, 9500*time.Millisecond, snapshot.Duration())
+	require.InDelta(t, 100/9.5, snapshot.Rate(), 0.001)
+
+	clock.Advance(time.Second)
+	s.RecordDuration(1000)
+
+	require.EqualValues(t, 1, s.Snapshot(time.Second).Count())
+	require.EqualValues(t, 101, s.Snapshot(2*time.Second).Count())
+
+	// values leave the window once their bucket is older than the window
+	clock.Advance(9 * time.Second)
+	snapshot = s.Snapshot(10 * time.Second)
+	require.EqualValues(t, 1, snapshot.Count())
+	require.EqualValues(t, 1000, snapshot.Min())
+	require.EqualValues(t, 1000, snapshot.P99())
+
+	clock.Advance(time.Second)
+	require.Zero(t, s.Snapshot(10*time.Second).Count())
+	require.Zero(t, s.Snapshot(10*time.Second).P50())
+}
+
+func TestSlidingWindowResolutions(t *testing.T) {
+	clock := newTestClock()
+	s := slidingwindow.New(slidingwindow.WithClock(clock.Now))
+
+	require.Equal(t, []slidingwindow.Resolution{
+		{BucketDuration: time.Second, BucketCount: 60},
+		{BucketDuration: time.Minute, BucketCount: 60},
+	}, s.Resolutions())
+
+	for i := 0; i < 120; i++ {
+		s.Record(int64(i))
+		clock.Advance(time.Second)
+	}
+
+	// the fine resolution only covers the last minute (including the current bucket that just started)
+	require.EqualValues(t, 59, s.Snapshot(time.Minute).Count())
+	require.EqualValues(t, 61, s.Snapshot(time.Minute).Min())
+
+	// the coarse resolution covers the last hour (rounded to full minutes)
+	require.EqualValues(t, 120, s.Snapshot(time.Hour).Count())
+	require.EqualValues(t, 120, s.Snapshot(5*time.Minute).Count())
+
+	// durations beyond the coarsest resolution are capped
+	require.EqualValues(t, 120, s.Snapshot(24*time.Hour).Count())
+
+	s.Reset()
+	require.Zero(t, s.Snapshot(time.Hour).Count())
+}
+
+func TestSlidingWindowRecordSince(t *testing.T) {
+	clock := newTestClock()
+	s := slidingwindow.New(slidingwindow.WithClock(clock.Now))
+
+	start := clock.Now()
+	clock.Advance(250 * time.Millisecond)
+	s.RecordSince(start)
+
+	require.InEpsilon(t, float64(250*time.Millisecond), float64(s.Snapshot(time.Second).P50()), 0.01)
+}
+
+func TestSnapshotMerge(t *testing.T) {
+	clock := newTestClock()
+	s1 := slidingwindow.New(slidingwindow.WithClock(clock.Now))
+	s2 := slidingwindow.New(slidingwindow.WithClock(clock.Now))
+
+	s1.Record(10)
+	s1.Record(20)
+	s2.Record(5)
+	clock.Advance(time.Second)
+
+	merged := s1.Snapshot(time.Minute).Merge(s2.Snapshot(time.Minute))
+	require.EqualValues(t, 3, merged.Count())
+	require.EqualValues(t, 35, merged.Sum())
+	require.EqualValues(t, 5, merged.Min())
+	require.EqualValues(t, 20, merged.Max())
+	require.EqualValues(t, 10, merged.P50())
+	require.EqualValues(t, 3, merged.Histogram().Count())
+}
+
+func TestSlidingWindowConcurrency(t *testing.T) {
+	s := slidingwindow.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				s.Record(int64(j))
+
+				if j%100 == 0 {
+					_ = s.Snapshot(time.Minute).P99()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 8000, s.Snapshot(time.Hour).Count())
+}
+
+// testClock is a manually advanced clock.
+type testClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
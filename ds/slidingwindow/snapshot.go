@@ -0,0 +1,136 @@
+package slidingwindow
+
+import (
+	"time"
+)
+
+// Snapshot contains the statistics of the values that were recorded during a time span.
+type Snapshot struct {
+	// duration is the time span that is covered by the Snapshot.
+	duration time.Duration
+
+	// count is the number of recorded values.
+	count uint64
+
+	// sum is the sum of the recorded values.
+	sum int64
+
+	// min is the smallest recorded value.
+	min int64
+
+	// max is the largest recorded value.
+	max int64
+
+	// histogram contains the distribution of the recorded values.
+	histogram *Histogram
+}
+
+// newSnapshot creates a new (empty) Snapshot that covers the given duration.
+func newSnapshot(duration time.Duration) *Snapshot {
+	return &Snapshot{
+		duration:  duration,
+		histogram: NewHistogram(),
+	}
+}
+
+// Duration returns the time span that is covered by the Snapshot.
+func (s *Snapshot) Duration() time.Duration {
+	return s.duration
+}
+
+// Count returns the number of recorded values.
+func (s *Snapshot) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the sum of the recorded values.
+func (s *Snapshot) Sum() int64 {
+	return s.sum
+}
+
+// Min returns the smallest recorded value (0 if no value was recorded).
+func (s *Snapshot) Min() int64 {
+	return s.min
+}
+
+// Max returns the largest recorded value (0 if no value was recorded).
+func (s *Snapshot) Max() int64 {
+	return s.max
+}
+
+// Mean returns the average of the recorded values (0 if no value was recorded).
+func (s *Snapshot) Mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	return float64(s.sum) / float64(s.count)
+}
+
+// Rate returns the number of recorded values per second.
+func (s *Snapshot) Rate() float64 {
+	if s.duration <= 0 {
+		return 0
+	}
+
+	return float64(s.count) / s.duration.Seconds()
+}
+
+// Percentile returns the (approximated) value below which the given percentage (0 <= p <= 100) of the recorded values
+// falls.
+func (s *Snapshot) Percentile(p float64) int64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	// the approximation of the histogram must not exceed the exact bounds
+	return max(s.min, min(s.max, s.histogram.Quantile(p/100)))
+}
+
+// P50 returns the median of the recorded values.
+func (s *Snapshot) P50() int64 {
+	return s.Percentile(50)
+}
+
+// P95 returns the 95th percentile of the recorded values.
+func (s *Snapshot) P95() int64 {
+	return s.Percentile(95)
+}
+
+// P99 returns the 99th percentile of the recorded values.
+func (s *Snapshot) P99() int64 {
+	return s.Percentile(99)
+}
+
+// Histogram returns a copy of the distribution of the recorded values.
+func (s *Snapshot) Histogram() *Histogram {
+	return s.histogram.Clone()
+}
+
+// Merge returns a new Snapshot that combines the statistics of both Snapshots (e.g. of different SlidingWindows that
+// cover the same time span). The duration of the result is the longer duration of both Snapshots.
+func (s *Snapshot) Merge(other *Snapshot) *Snapshot {
+	merged := newSnapshot(max(s.duration, other.duration))
+	merged.merge(s.count, s.sum, s.min, s.max, s.histogram)
+	merged.merge(other.count, other.sum, other.min, other.max, other.histogram)
+
+	return merged
+}
+
+// merge adds the given statistics to the Snapshot.
+func (s *Snapshot) merge(count uint64, sum int64, minValue int64, maxValue int64, histogram *Histogram) {
+	if count == 0 {
+		return
+	}
+
+	if s.count == 0 || minValue < s.min {
+		s.min = minValue
+	}
+	if s.count == 0 || maxValue > s.max {
+		s.max = maxValue
+	}
+
+	s.count += count
+	s.sum += sum
+	s.histogram.Merge(histogram)
+}
@@ -0,0 +1,110 @@
+package slidingwindow
+
+import (
+	"time"
+)
+
+// window is a sliding window of a single resolution that is organized as a ring buffer of buckets.
+type window struct {
+	// resolution is the resolution of the window.
+	resolution Resolution
+
+	// buckets contains the buckets of the window (indexed by their epoch modulo the number of buckets).
+	buckets []*bucket
+}
+
+// newWindow creates a new window with the given resolution.
+func newWindow(resolution Resolution) *window {
+	return &window{
+		resolution: resolution,
+		buckets:    make([]*bucket, resolution.BucketCount),
+	}
+}
+
+// record records the given value in the bucket of the given time.
+func (w *window) record(now time.Time, value int64) {
+	epoch := w.epoch(now)
+
+	position := w.position(epoch)
+	if w.buckets[position] == nil || w.buckets[position].epoch != epoch {
+		// the bucket is reused for a new epoch (which drops the values of the epoch that left the window)
+		w.buckets[position] = newBucket(epoch)
+	}
+
+	w.buckets[position].record(value)
+}
+
+// snapshot returns the statistics of the buckets that cover the given duration before the given time.
+func (w *window) snapshot(now time.Time, duration time.Duration) *Snapshot {
+	currentEpoch := w.epoch(now)
+
+	// the number of buckets that cover the duration (including the current bucket that is only partially elapsed)
+	bucketCount := int64((duration + w.resolution.BucketDuration - 1) / w.resolution.BucketDuration)
+	bucketCount = max(1, min(bucketCount, int64(w.resolution.BucketCount)))
+
+	elapsedInCurrentBucket := time.Duration(now.UnixNano() - currentEpoch*int64(w.resolution.BucketDuration))
+	snapshot := newSnapshot(time.Duration(bucketCount-1)*w.resolution.BucketDuration + elapsedInCurrentBucket)
+
+	for epoch := currentEpoch - bucketCount + 1; epoch <= currentEpoch; epoch++ {
+		if b := w.buckets[w.position(epoch)]; b != nil && b.epoch == epoch {
+			snapshot.merge(b.count, b.sum, b.min, b.max, b.histogram)
+		}
+	}
+
+	return snapshot
+}
+
+// epoch returns the index of the bucket duration that contains the given time.
+func (w *window) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.resolution.BucketDuration)
+}
+
+// position returns the position of the bucket of the given epoch in the ring buffer.
+func (w *window) position(epoch int64) int {
+	bucketCount := int64(w.resolution.BucketCount)
+
+	return int(((epoch % bucketCount) + bucketCount) % bucketCount)
+}
+
+// bucket aggregates the values that were recorded during a single epoch.
+type bucket struct {
+	// epoch is the epoch of the bucket.
+	epoch int64
+
+	// count is the number of recorded values.
+	count uint64
+
+	// sum is the sum of the recorded values.
+	sum int64
+
+	// min is the smallest recorded value.
+	min int64
+
+	// max is the largest recorded value.
+	max int64
+
+	// histogram contains the distribution of the recorded values.
+	histogram *Histogram
+}
+
+// newBucket creates a new (empty) bucket for the given epoch.
+func newBucket(epoch int64) *bucket {
+	return &bucket{
+		epoch:     epoch,
+		histogram: NewHistogram(),
+	}
+}
+
+// record records the given value.
+func (b *bucket) record(value int64) {
+	if b.count == 0 || value < b.min {
+		b.min = value
+	}
+	if b.count == 0 || value > b.max {
+		b.max = value
+	}
+
+	b.count++
+	b.sum += value
+	b.histogram.Record(value)
+}
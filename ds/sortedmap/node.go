@@ -0,0 +1,54 @@
+package sortedmap
+
+// node is a node of the skip list that backs the SortedMap.
+type node[K any, V any] struct {
+	// key is the key of the entry.
+	key K
+
+	// value is the value of the entry.
+	value V
+
+	// next contains the successors of the node on every level that the node is part of.
+	next []*node[K, V]
+
+	// prev is the predecessor of the node on the lowest level (nil for the first node).
+	prev *node[K, V]
+}
+
+// newNode creates a new node that is part of the given number of levels.
+func newNode[K any, V any](key K, value V, level int) *node[K, V] {
+	return &node[K, V]{
+		key:   key,
+		value: value,
+		next:  make([]*node[K, V], level),
+	}
+}
+
+// entry returns the key and value of the node (exists is false if the node is nil).
+func (n *node[K, V]) entry() (key K, value V, exists bool) {
+	if n == nil {
+		return key, value, false
+	}
+
+	return n.key, n.value, true
+}
+
+// entry is a key-value pair that is part of a snapshot.
+type entry[K any, V any] struct {
+	key   K
+	value V
+}
+
+// entries is a snapshot of the entries of a SortedMap.
+type entries[K any, V any] []entry[K, V]
+
+// forEach calls the consumer for every entry until it returns false.
+func (e entries[K, V]) forEach(consumer func(key K, value V) bool) bool {
+	for _, snapshotEntry := range e {
+		if !consumer(snapshotEntry.key, snapshotEntry.value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,417 @@
+package sortedmap
+
+import (
+	"cmp"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// maxLevel is the maximum number of levels of the skip list (sufficient for 4^32 entries).
+	maxLevel = 32
+
+	// levelProbability is the probability that a node is promoted to the next level.
+	levelProbability = 0.25
+
+	// snapshotChunkSize is the maximum number of entries that an iteration copies while holding the lock.
+	snapshotChunkSize = 128
+)
+
+// SortedMap is a concurrent-safe map that keeps its entries sorted by their keys. It is backed by a skip list, so
+// lookups, insertions and deletions take O(log n).
+//
+// Iterations copy the affected entries in chunks, so the consumers are called without holding the lock and can safely
+// modify the map. Every chunk is a consistent snapshot that continues after the last key of the previous chunk, so
+// entries that are added behind the current position of a running iteration are still visited.
+type SortedMap[K any, V any] struct {
+	// head is the sentinel node that holds the first node of every level.
+	head *node[K, V]
+
+	// tail is the node with the largest key.
+	tail *node[K, V]
+
+	// level is the number of levels that are currently in use.
+	level int
+
+	// size is the number of entries in the map.
+	size int
+
+	// compare is the function that is used to order the keys.
+	compare func(a, b K) int
+
+	// random is used to determine the level of new nodes.
+	random *rand.Rand
+
+	// mutex is used to synchronize access to the map.
+	mutex sync.RWMutex
+}
+
+// New creates a new SortedMap that orders its keys by their natural order.
+func New[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return NewWithComparator[K, V](cmp.Compare[K])
+}
+
+// NewWithComparator creates a new SortedMap that orders its keys by the given comparator (which returns a negative
+// number if a < b, zero if a == b and a positive number if a > b).
+func NewWithComparator[K any, V any](compare func(a, b K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{
+		head:    newNode[K, V](*new(K), *new(V), maxLevel),
+		level:   1,
+		compare: compare,
+		//nolint:gosec // we do not care about weak random numbers here
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Get returns the value that is mapped to the given key.
+func (s *SortedMap[K, V]) Get(key K) (value V, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if candidate := s.lowerBound(key); candidate != nil && s.compare(candidate.key, key) == 0 {
+		return candidate.value, true
+	}
+
+	return value, false
+}
+
+// Has returns true if an entry with the given key exists.
+func (s *SortedMap[K, V]) Has(key K) bool {
+	_, exists := s.Get(key)
+
+	return exists
+}
+
+// Set maps the given value to the given key and returns the previous value (if it existed).
+func (s *SortedMap[K, V]) Set(key K, value V) (previousValue V, previousValueExisted bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var predecessors [maxLevel]*node[K, V]
+	if candidate := s.findPredecessors(key, &predecessors); candidate != nil && s.compare(candidate.key, key) == 0 {
+		previousValue, candidate.value = candidate.value, value
+
+		return previousValue, true
+	}
+
+	level := s.randomLevel()
+	for ; s.level < level; s.level++ {
+		predecessors[s.level] = s.head
+	}
+
+	newEntry := newNode(key, value, level)
+	for i := 0; i < level; i++ {
+		newEntry.next[i] = predecessors[i].next[i]
+		predecessors[i].next[i] = newEntry
+	}
+
+	if predecessors[0] != s.head {
+		newEntry.prev = predecessors[0]
+	}
+
+	if newEntry.next[0] != nil {
+		newEntry.next[0].prev = newEntry
+	} else {
+		s.tail = newEntry
+	}
+
+	s.size++
+
+	return previousValue, false
+}
+
+// Delete removes the entry with the given key and returns its value (if it existed).
+func (s *SortedMap[K, V]) Delete(key K) (value V, deleted bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.delete(key)
+}
+
+// Size returns the number of entries in the map.
+func (s *SortedMap[K, V]) Size() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.size
+}
+
+// IsEmpty returns true if the map contains no entries.
+func (s *SortedMap[K, V]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear removes all entries from the map.
+func (s *SortedMap[K, V]) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.head = newNode[K, V](*new(K), *new(V), maxLevel)
+	s.tail = nil
+	s.level = 1
+	s.size = 0
+}
+
+// Min returns the entry with the smallest key.
+func (s *SortedMap[K, V]) Min() (key K, value V, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.head.next[0].entry()
+}
+
+// Max returns the entry with the largest key.
+func (s *SortedMap[K, V]) Max() (key K, value V, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.tail.entry()
+}
+
+// PopMin removes and returns the entry with the smallest key.
+func (s *SortedMap[K, V]) PopMin() (key K, value V, exists bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if key, value, exists = s.head.next[0].entry(); exists {
+		s.delete(key)
+	}
+
+	return key, value, exists
+}
+
+// PopMax removes and returns the entry with the largest key.
+func (s *SortedMap[K, V]) PopMax() (key K, value V, exists bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if key, value, exists = s.tail.entry(); exists {
+		s.delete(key)
+	}
+
+	return key, value, exists
+}
+
+// Floor returns the entry with the largest key that is lower than or equal to the given key.
+func (s *SortedMap[K, V]) Floor(key K) (floorKey K, value V, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.floor(key).entry()
+}
+
+// Ceiling returns the entry with the smallest key that is greater than or equal to the given key.
+func (s *SortedMap[K, V]) Ceiling(key K) (ceilingKey K, value V, exists bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lowerBound(key).entry()
+}
+
+// Keys returns all keys in ascending order.
+func (s *SortedMap[K, V]) Keys() []K {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys := make([]K, 0, s.size)
+	for currentNode := s.head.next[0]; currentNode != nil; currentNode = currentNode.next[0] {
+		keys = append(keys, currentNode.key)
+	}
+
+	return keys
+}
+
+// Values returns all values in the ascending order of their keys.
+func (s *SortedMap[K, V]) Values() []V {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values := make([]V, 0, s.size)
+	for currentNode := s.head.next[0]; currentNode != nil; currentNode = currentNode.next[0] {
+		values = append(values, currentNode.value)
+	}
+
+	return values
+}
+
+// ForEach iterates through the map in ascending order and calls the consumer for every entry.
+// The iteration can be aborted by returning false in the consumer (in which case ForEach returns false).
+func (s *SortedMap[K, V]) ForEach(consumer func(key K, value V) bool) bool {
+	return s.iterateAscending(func() *node[K, V] { return s.head.next[0] }, nil, consumer)
+}
+
+// ForEachReverse iterates through the map in descending order and calls the consumer for every entry.
+// The iteration can be aborted by returning false in the consumer (in which case ForEachReverse returns false).
+func (s *SortedMap[K, V]) ForEachReverse(consumer func(key K, value V) bool) bool {
+	return s.iterateDescending(func() *node[K, V] { return s.tail }, nil, consumer)
+}
+
+// Range iterates through the entries with keys in the range [start, end] in ascending order and calls the consumer for
+// every entry. The iteration can be aborted by returning false in the consumer (in which case Range returns false).
+func (s *SortedMap[K, V]) Range(start K, end K, consumer func(key K, value V) bool) bool {
+	return s.iterateAscending(func() *node[K, V] { return s.lowerBound(start) }, func(key K) bool { return s.compare(key, end) <= 0 }, consumer)
+}
+
+// RangeReverse iterates through the entries with keys in the range [start, end] in descending order and calls the
+// consumer for every entry. The iteration can be aborted by returning false in the consumer (in which case RangeReverse
+// returns false).
+func (s *SortedMap[K, V]) RangeReverse(start K, end K, consumer func(key K, value V) bool) bool {
+	return s.iterateDescending(func() *node[K, V] { return s.floor(end) }, func(key K) bool { return s.compare(key, start) >= 0 }, consumer)
+}
+
+// delete removes the entry with the given key (expects the mutex to be locked).
+func (s *SortedMap[K, V]) delete(key K) (value V, deleted bool) {
+	var predecessors [maxLevel]*node[K, V]
+	target := s.findPredecessors(key, &predecessors)
+	if target == nil || s.compare(target.key, key) != 0 {
+		return value, false
+	}
+
+	for i := 0; i < s.level && predecessors[i].next[i] == target; i++ {
+		predecessors[i].next[i] = target.next[i]
+	}
+
+	if target.next[0] != nil {
+		target.next[0].prev = target.prev
+	} else {
+		s.tail = target.prev
+	}
+
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+
+	s.size--
+
+	return target.value, true
+}
+
+// findPredecessors stores the last node before the given key of every level in the given array and returns the first
+// node with a key that is greater than or equal to the given key (expects the mutex to be locked).
+func (s *SortedMap[K, V]) findPredecessors(key K, predecessors *[maxLevel]*node[K, V]) *node[K, V] {
+	currentNode := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for currentNode.next[i] != nil && s.compare(currentNode.next[i].key, key) < 0 {
+			currentNode = currentNode.next[i]
+		}
+
+		predecessors[i] = currentNode
+	}
+
+	return currentNode.next[0]
+}
+
+// lowerBound returns the first node with a key that is greater than or equal to the given key (expects the mutex to be
+// locked).
+func (s *SortedMap[K, V]) lowerBound(key K) *node[K, V] {
+	currentNode := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for currentNode.next[i] != nil && s.compare(currentNode.next[i].key, key) < 0 {
+			currentNode = currentNode.next[i]
+		}
+	}
+
+	return currentNode.next[0]
+}
+
+// floor returns the last node with a key that is lower than or equal to the given key (expects the mutex to be
+// locked).
+func (s *SortedMap[K, V]) floor(key K) *node[K, V] {
+	currentNode := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for currentNode.next[i] != nil && s.compare(currentNode.next[i].key, key) <= 0 {
+			currentNode = currentNode.next[i]
+		}
+	}
+
+	if currentNode == s.head {
+		return nil
+	}
+
+	return currentNode
+}
+
+// higher returns the first node with a key that is greater than the given key (expects the mutex to be locked).
+func (s *SortedMap[K, V]) higher(key K) *node[K, V] {
+	currentNode := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for currentNode.next[i] != nil && s.compare(currentNode.next[i].key, key) <= 0 {
+			currentNode = currentNode.next[i]
+		}
+	}
+
+	return currentNode.next[0]
+}
+
+// lower returns the last node with a key that is lower than the given key (expects the mutex to be locked).
+func (s *SortedMap[K, V]) lower(key K) *node[K, V] {
+	currentNode := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for currentNode.next[i] != nil && s.compare(currentNode.next[i].key, key) < 0 {
+			currentNode = currentNode.next[i]
+		}
+	}
+
+	if currentNode == s.head {
+		return nil
+	}
+
+	return currentNode
+}
+
+// iterateAscending calls the consumer for the entries starting at the node that is returned by the given function in
+// ascending order as long as their keys match the given condition (the entries are copied in chunks, and every chunk
+// continues after the last key of the previous one).
+func (s *SortedMap[K, V]) iterateAscending(start func() *node[K, V], condition func(key K) bool, consumer func(key K, value V) bool) bool {
+	return s.iterate(start, func(lastKey K) *node[K, V] { return s.higher(lastKey) }, func(n *node[K, V]) *node[K, V] { return n.next[0] }, condition, consumer)
+}
+
+// iterateDescending calls the consumer for the entries starting at the node that is returned by the given function in
+// descending order as long as their keys match the given condition (the entries are copied in chunks, and every chunk
+// continues before the last key of the previous one).
+func (s *SortedMap[K, V]) iterateDescending(start func() *node[K, V], condition func(key K) bool, consumer func(key K, value V) bool) bool {
+	return s.iterate(start, func(lastKey K) *node[K, V] { return s.lower(lastKey) }, func(n *node[K, V]) *node[K, V] { return n.prev }, condition, consumer)
+}
+
+// iterate calls the consumer for the entries that are visited by walking the given step function from the given start
+// as long as their keys match the given condition. The entries are copied in chunks of snapshotChunkSize, so the
+// consumer is called without holding the lock and the next chunk is continued from the last key of the previous one.
+func (s *SortedMap[K, V]) iterate(start func() *node[K, V], continueAfter func(lastKey K) *node[K, V], step func(n *node[K, V]) *node[K, V], condition func(key K) bool, consumer func(key K, value V) bool) bool {
+	s.mutex.RLock()
+	chunk := s.snapshot(start(), step, condition)
+	s.mutex.RUnlock()
+
+	for {
+		if !chunk.forEach(consumer) {
+			return false
+		} else if len(chunk) < snapshotChunkSize {
+			return true
+		}
+
+		s.mutex.RLock()
+		chunk = s.snapshot(continueAfter(chunk[len(chunk)-1].key), step, condition)
+		s.mutex.RUnlock()
+	}
+}
+
+// snapshot returns up to snapshotChunkSize entries that are visited by walking the given step function from the given
+// node as long as their keys match the given condition (expects the mutex to be locked).
+func (s *SortedMap[K, V]) snapshot(start *node[K, V], step func(n *node[K, V]) *node[K, V], condition func(key K) bool) entries[K, V] {
+	snapshot := make(entries[K, V], 0)
+	for currentNode := start; currentNode != nil && len(snapshot) < snapshotChunkSize && (condition == nil || condition(currentNode.key)); currentNode = step(currentNode) {
+		snapshot = append(snapshot, entry[K, V]{currentNode.key, currentNode.value})
+	}
+
+	return snapshot
+}
+
+// randomLevel returns a random level for a new node (expects the mutex to be locked).
+func (s *SortedMap[K, V]) randomLevel() int {
+	level := 1
+	for level < maxLevel && s.random.Float64() < levelProbability {
+		level++
+	}
+
+	return level
+}
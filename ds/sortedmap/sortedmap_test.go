@@ -0,0 +1,264 @@
+package sortedmap_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/sortedmap"
+)
+
+func TestSortedMap(t *testing.T) {
+	m := sortedmap.New[int, string]()
+	require.True(t, m.IsEmpty())
+
+	_, _, exists := m.Min()
+	require.False(t, exists)
+
+	for _, key := range []int{5, 1, 9, 3, 7} {
+		_, existed := m.Set(key, string(rune('a'+key)))
+		require.False(t, existed)
+	}
+
+	previousValue, existed := m.Set(3, "three")
+	require.True(t, existed)
+	require.Equal(t, "d", previousValue)
+
+	require.Equal(t, 5, m.Size())
+	require.Equal(t, []int{1, 3, 5, 7, 9}, m.Keys())
+	require.Equal(t, []string{"b", "three", "f", "h", "j"}, m.Values())
+
+	value, exists := m.Get(3)
+	require.True(t, exists)
+	require.Equal(t, "three", value)
+	require.False(t, m.Has(4))
+
+	key, _, exists := m.Min()
+	require.True(t, exists)
+	require.Equal(t, 1, key)
+
+	key, _, exists = m.Max()
+	require.True(t, exists)
+	require.Equal(t, 9, key)
+
+	deletedValue, deleted := m.Delete(9)
+	require.True(t, deleted)
+	require.Equal(t, "j", deletedValue)
+
+	_, deleted = m.Delete(9)
+	require.False(t, deleted)
+
+	key, _, _ = m.Max()
+	require.Equal(t, 7, key)
+
+	m.Clear()
+	require.Zero(t, m.Size())
+	require.Empty(t, m.Keys())
+}
+
+func TestSortedMapFloorCeiling(t *testing.T) {
+	m := sortedmap.New[int, int]()
+	for _, key := range []int{10, 20, 30} {
+		m.Set(key, key)
+	}
+
+	for _, testCase := range []struct {
+		key           int
+		floor         int
+		floorExists   bool
+		ceiling       int
+		ceilingExists bool
+	}{
+		{5, 0, false, 10, true},
+		{10, 10, true, 10, true},
+		{15, 10, true, 20, true},
+		{30, 30, true, 30, true},
+		{35, 30, true, 0, false},
+	} {
+		floorKey, _, floorExists := m.Floor(testCase.key)
+		require.Equal(t, testCase.floorExists, floorExists, "floor of %d", testCase.key)
+		require.Equal(t, testCase.floor, floorKey, "floor of %d", testCase.key)
+
+		ceilingKey, _, ceilingExists := m.Ceiling(testCase.key)
+		require.Equal(t, testCase.ceilingExists, ceilingExists, "ceiling of %d", testCase.key)
+		require.Equal(t, testCase.ceiling, ceilingKey, "ceiling of %d", testCase.key)
+	}
+}
+
+func TestSortedMapIteration(t *testing.T) {
+	m := sortedmap.New[int, int]()
+	for i := 1; i <= 10; i++ {
+		m.Set(i, i*i)
+	}
+
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, collect(m.ForEach))
+	require.Equal(t, []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, collect(m.ForEachReverse))
+	require.Equal(t, []int{3, 4, 5}, collectRange(m.Range, 3, 5))
+	require.Equal(t, []int{5, 4, 3}, collectRange(m.RangeReverse, 3, 5))
+	require.Equal(t, []int{9, 10}, collectRange(m.Range, 9, 42))
+	require.Equal(t, []int{2, 1}, collectRange(m.RangeReverse, -5, 2))
+	require.Empty(t, collectRange(m.Range, 11, 20))
+	require.Empty(t, collectRange(m.Range, 5, 3))
+
+	// early stop
+	visited := make([]int, 0)
+	require.False(t, m.ForEach(func(key int, value int) bool {
+		visited = append(visited, key)
+
+		return key < 3
+	}))
+	require.Equal(t, []int{1, 2, 3}, visited)
+
+	// the consumers are called without holding the lock, so they can modify the map
+	require.True(t, m.ForEach(func(key int, _ int) bool {
+		m.Delete(key)
+
+		return true
+	}))
+	require.True(t, m.IsEmpty())
+}
+
+func TestSortedMapChunkedIteration(t *testing.T) {
+	m := sortedmap.New[int, int]()
+	expected, expectedReverse := make([]int, 0), make([]int, 0)
+	for i := 0; i < 1000; i++ {
+		m.Set(i*2, i)
+		expected, expectedReverse = append(expected, i*2), append([]int{i * 2}, expectedReverse...)
+	}
+
+	require.Equal(t, expected, collect(m.ForEach))
+	require.Equal(t, expectedReverse, collect(m.ForEachReverse))
+	require.Equal(t, expected[100:900], collectRange(m.Range, 200, 1799))
+	require.Equal(t, expectedReverse[100:899], collectRange(m.RangeReverse, 201, 1798))
+
+	// the iteration continues after the last visited key, so entries that are added behind the position are visited
+	visited := make([]int, 0)
+	require.True(t, m.ForEach(func(key int, _ int) bool {
+		visited = append(visited, key)
+
+		if key == 0 {
+			m.Set(1999, 0)
+			m.Set(-1, 0)
+		}
+
+		return true
+	}))
+	require.Equal(t, append(slices.Clone(expected), 1999), visited)
+}
+
+func TestSortedMapPop(t *testing.T) {
+	m := sortedmap.New[int, int]()
+
+	_, _, exists := m.PopMin()
+	require.False(t, exists)
+
+	expectedKeys := rand.Perm(100)
+	for _, key := range expectedKeys {
+		m.Set(key, key)
+	}
+	sort.Ints(expectedKeys)
+
+	for len(expectedKeys) > 0 {
+		key, value, popped := m.PopMin()
+		require.True(t, popped)
+		require.Equal(t, expectedKeys[0], key)
+		require.Equal(t, key, value)
+		expectedKeys = expectedKeys[1:]
+
+		if len(expectedKeys) > 0 {
+			key, _, popped = m.PopMax()
+			require.True(t, popped)
+			require.Equal(t, expectedKeys[len(expectedKeys)-1], key)
+			expectedKeys = expectedKeys[:len(expectedKeys)-1]
+		}
+
+		require.Equal(t, len(expectedKeys), m.Size())
+		require.Equal(t, expectedKeys, m.Keys())
+	}
+
+	_, _, exists = m.PopMax()
+	require.False(t, exists)
+}
+
+func TestSortedMapComparator(t *testing.T) {
+	m := sortedmap.NewWithComparator[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(b), strings.ToLower(a))
+	})
+
+	m.Set("b", 1)
+	m.Set("A", 2)
+	m.Set("c", 3)
+
+	_, existed := m.Set("a", 4)
+	require.True(t, existed)
+
+	require.Equal(t, []string{"c", "b", "A"}, m.Keys())
+	require.Equal(t, []int{3, 1, 4}, m.Values())
+}
+
+func TestSortedMapConcurrency(t *testing.T) {
+	m := sortedmap.New[int, int]()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		worker := worker
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 500; i++ {
+				key := worker*1000 + i
+				m.Set(key, key)
+
+				if i%2 == 0 {
+					m.Delete(key)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				previousKey := -1
+				m.ForEach(func(key int, _ int) bool {
+					require.Greater(t, key, previousKey)
+					previousKey = key
+
+					return true
+				})
+
+				m.Floor(worker * 1000)
+				m.Range(worker*1000, worker*1000+100, func(int, int) bool { return true })
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 8*250, m.Size())
+
+	keys := m.Keys()
+	require.True(t, sort.IntsAreSorted(keys))
+}
+
+func collect(iterator func(consumer func(key int, value int) bool) bool) []int {
+	keys := make([]int, 0)
+	iterator(func(key int, _ int) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	return keys
+}
+
+func collectRange(iterator func(start int, end int, consumer func(key int, value int) bool) bool, start int, end int) []int {
+	return collect(func(consumer func(key int, value int) bool) bool {
+		return iterator(start, end, consumer)
+	})
+}
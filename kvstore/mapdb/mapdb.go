@@ -72,6 +72,21 @@ func (s *mapDB) IterateKeys(prefix kvstore.KeyPrefix, consumerFunc kvstore.Itera
 	return nil
 }
 
+// IterateKeysFrom iterates over all keys with the provided prefix that do not come before the given start key in the
+// direction of the iteration (default: IterDirectionForward).
+//
+// The keys are not stored in order, so every call still scans all keys of the map and sorts the keys after the start
+// key (O(n log n) per call) - it only saves the consumer from skipping the keys before the start key.
+func (s *mapDB) IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc, iterDirection ...kvstore.IterDirection) error {
+	if s.closed.Load() {
+		return kvstore.ErrStoreClosed
+	}
+
+	s.m.iterateKeysFrom(s.realm, prefix, start, consumerFunc, iterDirection...)
+
+	return nil
+}
+
 func (s *mapDB) Clear() error {
 	if s.closed.Load() {
 		return kvstore.ErrStoreClosed
@@ -83,12 +83,21 @@ func (s *syncedKVMap) iterate(realm []byte, keyPrefix []byte, consume func(key,
 }
 
 func (s *syncedKVMap) iterateKeys(realm []byte, keyPrefix []byte, consume func(key []byte) bool, iterDirection ...kvstore.IterDirection) {
-	// take a snapshot of the current elements
+	s.iterateKeysFrom(realm, keyPrefix, nil, consume, iterDirection...)
+}
+
+// iterateKeysFrom iterates over the keys with the given prefix that do not come before the given start key. It copies
+// and sorts all matching keys on every call, so paging through the map with it is O(n log n) per page.
+func (s *syncedKVMap) iterateKeysFrom(realm []byte, keyPrefix []byte, start []byte, consume func(key []byte) bool, iterDirection ...kvstore.IterDirection) {
+	isBackward := kvstore.GetIterDirection(iterDirection...) == kvstore.IterDirectionBackward
+	startKey := byteutils.ConcatBytesToString(realm, start)
+
+	// take a snapshot of the current elements (that do not come before the start key)
 	s.RLock()
 	copiedElements := make(map[string]struct{})
 	prefix := byteutils.ConcatBytesToString(realm, keyPrefix)
 	for key := range s.m {
-		if strings.HasPrefix(key, prefix) {
+		if strings.HasPrefix(key, prefix) && (start == nil || (isBackward && key <= startKey) || (!isBackward && key >= startKey)) {
 			copiedElements[key] = struct{}{}
 		}
 	}
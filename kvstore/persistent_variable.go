@@ -0,0 +1,124 @@
+package kvstore
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/ds/reactive"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+// PersistentVariable is a reactive.Variable that loads its initial value from a TypedValue and that writes its changes
+// back to it (either synchronously or through a BatchedWriter).
+type PersistentVariable[V comparable] struct {
+	// Variable embeds the reactive.Variable that holds the current value.
+	reactive.Variable[V]
+
+	typedValue          *TypedValue[V]
+	batchWriteScheduled atomic.Bool
+	batchWrittenValue   *V
+	unsubscribe         func()
+	opts                *PersistentVariableOptions
+}
+
+// NewPersistentVariable creates a new PersistentVariable that is backed by the given TypedValue.
+func NewPersistentVariable[V comparable](typedValue *TypedValue[V], opts ...options.Option[PersistentVariableOptions]) (*PersistentVariable[V], error) {
+	initialValue, err := typedValue.Get()
+	if err != nil && !ierrors.Is(err, ErrKeyNotFound) {
+		return nil, ierrors.Wrap(err, "failed to load initial value")
+	}
+
+	p := &PersistentVariable[V]{
+		Variable:   reactive.NewVariable[V]().Init(initialValue),
+		typedValue: typedValue,
+		opts: options.Apply(&PersistentVariableOptions{
+			errorHandler: func(err error) { slog.Error("failed to persist variable", "err", err) },
+		}, opts),
+	}
+
+	// the loaded value does not need to be written back to the store
+	isLoaded := false
+	p.unsubscribe = p.Variable.OnUpdate(func(_, newValue V) {
+		if isLoaded {
+			p.persist(newValue)
+		}
+	})
+	isLoaded = true
+
+	return p, nil
+}
+
+// TypedValue returns the underlying TypedValue.
+func (p *PersistentVariable[V]) TypedValue() *TypedValue[V] {
+	return p.typedValue
+}
+
+// Shutdown stops writing the changes of the variable to the store (writes that were already enqueued in the
+// BatchedWriter are still executed).
+func (p *PersistentVariable[V]) Shutdown() {
+	p.unsubscribe()
+}
+
+// BatchWrite marshals the current value and adds it to the BatchedMutations.
+func (p *PersistentVariable[V]) BatchWrite(batchedMuts BatchedMutations) {
+	value := p.Get()
+	if err := p.typedValue.batchWrite(batchedMuts, value); err != nil {
+		p.opts.errorHandler(ierrors.Wrap(err, "failed to persist value"))
+
+		return
+	}
+
+	p.batchWrittenValue = &value
+}
+
+// BatchWriteDone is called after the value was persisted (it updates the cache of the TypedValue).
+func (p *PersistentVariable[V]) BatchWriteDone() {
+	if p.batchWrittenValue != nil {
+		p.typedValue.batchWriteDone(*p.batchWrittenValue)
+		p.batchWrittenValue = nil
+	}
+}
+
+// BatchWriteScheduled returns true if the value is already scheduled for a BatchWrite operation (and marks it as
+// scheduled otherwise).
+func (p *PersistentVariable[V]) BatchWriteScheduled() bool {
+	return p.batchWriteScheduled.Swap(true)
+}
+
+// ResetBatchWriteScheduled resets the flag that the value is scheduled for a BatchWrite operation.
+func (p *PersistentVariable[V]) ResetBatchWriteScheduled() {
+	p.batchWriteScheduled.Store(false)
+}
+
+// persist writes the given value to the store (or enqueues it in the BatchedWriter).
+func (p *PersistentVariable[V]) persist(value V) {
+	if p.opts.batchedWriter != nil {
+		p.opts.batchedWriter.Enqueue(p)
+	} else if err := p.typedValue.Set(value); err != nil {
+		p.opts.errorHandler(ierrors.Wrap(err, "failed to persist value"))
+	}
+}
+
+// PersistentVariableOptions define options for the PersistentVariable.
+type PersistentVariableOptions struct {
+	// the BatchedWriter that is used to write the changes (nil if the changes are written synchronously).
+	batchedWriter *BatchedWriter
+	// the handler that is called when a change could not be written.
+	errorHandler func(err error)
+}
+
+// WithBatchedWriter defines the BatchedWriter that is used to write the changes of the PersistentVariable.
+func WithBatchedWriter(batchedWriter *BatchedWriter) options.Option[PersistentVariableOptions] {
+	return func(opts *PersistentVariableOptions) {
+		opts.batchedWriter = batchedWriter
+	}
+}
+
+// WithErrorHandler defines the handler that is called when a change of the PersistentVariable could not be written
+// (the default handler logs the error with the default slog.Logger).
+func WithErrorHandler(errorHandler func(err error)) options.Option[PersistentVariableOptions] {
+	return func(opts *PersistentVariableOptions) {
+		opts.errorHandler = errorHandler
+	}
+}
@@ -0,0 +1,128 @@
+package kvstore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ds/reactive"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestPersistentVariable(t *testing.T) {
+	kvStore := mapdb.NewMapDB()
+	defer kvStore.Close()
+
+	persistentVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt))
+	require.NoError(t, err)
+	require.Equal(t, 0, persistentVariable.Get())
+
+	// derivations keep working
+	doubled := reactive.NewDerivedVariable[int](func(_ int, value int) int { return value * 2 }, persistentVariable)
+
+	persistentVariable.Set(42)
+	require.Equal(t, 84, doubled.Get())
+
+	// the value survives a restart
+	restoredVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt))
+	require.NoError(t, err)
+	require.Equal(t, 42, restoredVariable.Get())
+
+	restoredVariable.Compute(func(currentValue int) int { return currentValue + 1 })
+
+	value, err := kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt).Get()
+	require.NoError(t, err)
+	require.Equal(t, 43, value)
+
+	// changes are no longer written after the shutdown
+	restoredVariable.Shutdown()
+	restoredVariable.Set(1)
+
+	value, err = kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt).Get()
+	require.NoError(t, err)
+	require.Equal(t, 43, value)
+}
+
+func TestPersistentVariableBatchedWriter(t *testing.T) {
+	kvStore := mapdb.NewMapDB()
+	defer kvStore.Close()
+
+	batchedWriter := kvstore.NewBatchedWriter(kvStore)
+
+	persistentVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt), kvstore.WithBatchedWriter(batchedWriter))
+	require.NoError(t, err)
+
+	for i := 1; i <= 100; i++ {
+		persistentVariable.Set(i)
+	}
+
+	batchedWriter.StopBatchWriter()
+
+	value, err := kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt).Get()
+	require.NoError(t, err)
+	require.Equal(t, 100, value)
+
+	value, err = persistentVariable.TypedValue().Get()
+	require.NoError(t, err)
+	require.Equal(t, 100, value)
+}
+
+func TestPersistentVariableBatchWriteCache(t *testing.T) {
+	kvStore := mapdb.NewMapDB()
+	defer kvStore.Close()
+
+	persistentVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("key"), intToBytes, bytesToInt))
+	require.NoError(t, err)
+
+	persistentVariable.Set(1)
+	persistentVariable.Shutdown()
+	persistentVariable.Set(2)
+
+	batchedMutations, err := kvStore.Batched()
+	require.NoError(t, err)
+	persistentVariable.BatchWrite(batchedMutations)
+
+	// the cached value is only updated once the mutations were committed
+	value, err := persistentVariable.TypedValue().Get()
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	require.NoError(t, batchedMutations.Commit())
+	persistentVariable.BatchWriteDone()
+
+	value, err = persistentVariable.TypedValue().Get()
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestPersistentVariableErrorHandler(t *testing.T) {
+	kvStore := mapdb.NewMapDB()
+	defer kvStore.Close()
+
+	errInvalidValue := ierrors.New("invalid value")
+	nonNegativeToBytes := func(value int) ([]byte, error) {
+		if value < 0 {
+			return nil, errInvalidValue
+		}
+
+		return intToBytes(value)
+	}
+
+	var handledErrors []error
+	persistentVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("key"), nonNegativeToBytes, bytesToInt), kvstore.WithErrorHandler(func(err error) {
+		handledErrors = append(handledErrors, err)
+	}))
+	require.NoError(t, err)
+
+	persistentVariable.Set(-1)
+	require.Len(t, handledErrors, 1)
+	require.ErrorIs(t, handledErrors[0], errInvalidValue)
+
+	// the default handler does not panic
+	defaultHandlerVariable, err := kvstore.NewPersistentVariable(kvstore.NewTypedValue[int](kvStore, []byte("otherKey"), nonNegativeToBytes, bytesToInt))
+	require.NoError(t, err)
+	require.NotPanics(t, func() { defaultHandlerVariable.Set(-1) })
+	require.Equal(t, -1, defaultHandlerVariable.Get())
+}
@@ -3,6 +3,7 @@
 package rocksdb
 
 import (
+	"bytes"
 	"sync"
 	"sync/atomic"
 
@@ -153,6 +154,46 @@ func (s *rocksDBStore) IterateKeys(prefix kvstore.KeyPrefix, consumerFunc kvstor
 	return nil
 }
 
+// IterateKeysFrom iterates over all keys with the provided prefix that do not come before the given start key in the
+// direction of the iteration (default: IterDirectionForward). The iteration seeks to the start key instead of skipping
+// the keys before it.
+func (s *rocksDBStore) IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc, iterDirection ...kvstore.IterDirection) error {
+	if s.closed.Load() {
+		return kvstore.ErrStoreClosed
+	}
+
+	it := s.instance.db.NewIterator(s.instance.ro)
+	defer it.Close()
+
+	startFunc, validFunc, moveFunc, err := s.getIterFuncs(it, s.buildKeyPrefix(prefix), iterDirection...)
+	if err != nil {
+		return err
+	}
+
+	// start at the start key if it lies within the prefix (otherwise the iteration starts at the first key of the
+	// prefix or does not find any keys at all)
+	startKey := s.buildKeyPrefix(start)
+	if kvstore.GetIterDirection(iterDirection...) == kvstore.IterDirectionBackward {
+		if prefixUpperBound := utils.KeyPrefixUpperBound(prefix); prefixUpperBound == nil || bytes.Compare(start, prefixUpperBound) < 0 {
+			startFunc = func() { it.SeekForPrev(startKey) }
+		}
+	} else if bytes.Compare(start, prefix) > 0 {
+		startFunc = func() { it.Seek(startKey) }
+	}
+
+	for startFunc(); validFunc(); moveFunc() {
+		key := it.Key()
+		k := utils.CopyBytes(key.Data(), key.Size())[len(s.dbPrefix):]
+		key.Free()
+
+		if !consumerFunc(k) {
+			break
+		}
+	}
+
+	return nil
+}
+
 func (s *rocksDBStore) Clear() error {
 	if s.closed.Load() {
 		return kvstore.ErrStoreClosed
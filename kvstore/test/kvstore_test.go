@@ -555,6 +555,47 @@ func TestIteratePrefixKeyOnly(t *testing.T) {
 	}
 }
 
+func TestIterateKeysFrom(t *testing.T) {
+	type keySeeker interface {
+		IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc, iterDirection ...kvstore.IterDirection) error
+	}
+
+	collectKeys := func(store keySeeker, prefix kvstore.KeyPrefix, start kvstore.Key, iterDirection kvstore.IterDirection) []string {
+		keys := make([]string, 0)
+		require.NoError(t, store.IterateKeysFrom(prefix, start, func(key kvstore.Key) bool {
+			keys = append(keys, string(key))
+
+			return true
+		}, iterDirection))
+
+		return keys
+	}
+
+	for _, dbImplementation := range dbImplementations {
+		store, err := testStore(t, dbImplementation, []byte("testRealm"))
+		require.NoError(t, err)
+
+		for _, key := range []string{"a1", "a2", "a3", "b1", "b2", "c1"} {
+			require.NoError(t, store.Set([]byte(key), []byte(key)), "used db: %s", dbImplementation)
+		}
+
+		seekingStore, isSeekingStore := store.(keySeeker)
+		require.True(t, isSeekingStore, "used db: %s", dbImplementation)
+
+		forward, backward := kvstore.IterDirectionForward, kvstore.IterDirectionBackward
+		require.Equal(t, []string{"a2", "a3", "b1", "b2", "c1"}, collectKeys(seekingStore, kvstore.EmptyPrefix, []byte("a2"), forward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{"b1", "a3", "a2", "a1"}, collectKeys(seekingStore, kvstore.EmptyPrefix, []byte("b10"), backward), "used db: %s", dbImplementation)
+
+		// the start key is clamped to the keys of the prefix
+		require.Equal(t, []string{"b2"}, collectKeys(seekingStore, []byte("b"), []byte("b2"), forward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{"b1", "b2"}, collectKeys(seekingStore, []byte("b"), []byte("a"), forward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{}, collectKeys(seekingStore, []byte("b"), []byte("c"), forward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{"b1"}, collectKeys(seekingStore, []byte("b"), []byte("b1"), backward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{"b2", "b1"}, collectKeys(seekingStore, []byte("b"), []byte("c"), backward), "used db: %s", dbImplementation)
+		require.Equal(t, []string{}, collectKeys(seekingStore, []byte("b"), []byte("a"), backward), "used db: %s", dbImplementation)
+	}
+}
+
 func TestDeletePrefix(t *testing.T) {
 
 	prefix := []byte("testPrefix")
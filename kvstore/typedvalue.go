@@ -180,6 +180,27 @@ func (t *TypedValue[V]) Delete() (err error) {
 	return nil
 }
 
+// batchWrite adds the given value to the given BatchedMutations (the value is only cached by batchWriteDone once the
+// mutations were committed).
+func (t *TypedValue[V]) batchWrite(batchedMuts BatchedMutations, value V) error {
+	if valueBytes, err := t.vToBytes(value); err != nil {
+		return ierrors.Wrap(err, "failed to encode value")
+	} else if err = batchedMuts.Set(t.keyBytes, valueBytes); err != nil {
+		return ierrors.Wrap(err, "failed to add value to batched mutations")
+	}
+
+	return nil
+}
+
+// batchWriteDone caches the given value after it was written to the KVStore by committed BatchedMutations.
+func (t *TypedValue[V]) batchWriteDone(value V) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.valueCached = &value
+	t.hasCached = &truePtr
+}
+
 // cachedValue returns the cached value and a boolean indicating whether the value is cached.
 func (t *TypedValue[V]) cachedValue() (value V, isCached bool) {
 	if t.valueCached == nil {
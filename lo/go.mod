@@ -1,6 +1,6 @@
 module github.com/iotaledger/hive.go/lo
 
-go 1.21
+go 1.23
 
 require (
 	github.com/iotaledger/hive.go/constraints v0.0.0-20240124155449-ae3027973624
@@ -0,0 +1,80 @@
+package lo
+
+import (
+	"iter"
+)
+
+// MapSeq returns a sequence that lazily applies the mapper function to each element of the source sequence.
+func MapSeq[SourceType any, TargetType any](source iter.Seq[SourceType], mapper func(SourceType) TargetType) iter.Seq[TargetType] {
+	return func(yield func(TargetType) bool) {
+		for value := range source {
+			if !yield(mapper(value)) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeq returns a sequence that lazily yields the elements of the source sequence that satisfy the predicate.
+func FilterSeq[V any](source iter.Seq[V], predicate func(V) bool) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for value := range source {
+			if predicate(value) && !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// TakeSeq returns a sequence that yields at most the first count elements of the source sequence (the source is not
+// consumed any further once the count is reached).
+func TakeSeq[V any](source iter.Seq[V], count int) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if count <= 0 {
+			return
+		}
+
+		taken := 0
+		for value := range source {
+			if !yield(value) {
+				return
+			}
+
+			if taken++; taken >= count {
+				return
+			}
+		}
+	}
+}
+
+// CollectSeq collects the elements of the sequence into a new slice.
+func CollectSeq[V any](source iter.Seq[V]) []V {
+	result := make([]V, 0)
+	for value := range source {
+		result = append(result, value)
+	}
+
+	return result
+}
+
+// KeysSeq returns a sequence of the keys of the given key-value sequence.
+func KeysSeq[K any, V any](source iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range source {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns a sequence of the values of the given key-value sequence.
+func ValuesSeq[K any, V any](source iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range source {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
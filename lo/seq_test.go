@@ -0,0 +1,57 @@
+package lo_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/lo"
+)
+
+func Test_MapSeq(t *testing.T) {
+	mapped := lo.MapSeq(slices.Values([]int{1, 2, 3}), func(item int) string {
+		return string(rune('a' + item - 1))
+	})
+
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(mapped), "should map the sequence")
+	require.Equal(t, []string{"a", "b", "c"}, slices.Collect(mapped), "should be reusable")
+}
+
+func Test_FilterSeq(t *testing.T) {
+	filtered := lo.FilterSeq(slices.Values([]int{1, 2, 3, 4, 5, 6}), func(item int) bool {
+		return item%2 == 0
+	})
+
+	require.Equal(t, []int{2, 4, 6}, slices.Collect(filtered), "should filter the sequence")
+}
+
+func Test_TakeSeq(t *testing.T) {
+	require.Equal(t, []int{1, 2}, slices.Collect(lo.TakeSeq(slices.Values([]int{1, 2, 3}), 2)))
+	require.Equal(t, []int{1, 2, 3}, slices.Collect(lo.TakeSeq(slices.Values([]int{1, 2, 3}), 5)))
+	require.Empty(t, slices.Collect(lo.TakeSeq(slices.Values([]int{1, 2, 3}), 0)))
+
+	// the source is evaluated lazily and not consumed beyond the taken elements
+	consumed := 0
+	source := lo.MapSeq(slices.Values([]int{1, 2, 3, 4, 5}), func(item int) int {
+		consumed++
+
+		return item
+	})
+
+	require.Equal(t, []int{1, 2}, slices.Collect(lo.TakeSeq(source, 2)))
+	require.Equal(t, 2, consumed)
+}
+
+func Test_CollectSeq(t *testing.T) {
+	require.Equal(t, []int{}, lo.CollectSeq(slices.Values([]int(nil))), "should return an empty slice")
+	require.Equal(t, []int{1, 2, 3}, lo.CollectSeq(slices.Values([]int{1, 2, 3})))
+}
+
+func Test_KeysSeqAndValuesSeq(t *testing.T) {
+	sourceMap := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	require.ElementsMatch(t, []string{"a", "b", "c"}, slices.Collect(lo.KeysSeq(maps.All(sourceMap))))
+	require.ElementsMatch(t, []int{1, 2, 3}, slices.Collect(lo.ValuesSeq(maps.All(sourceMap))))
+}